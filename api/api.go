@@ -24,6 +24,7 @@ const (
 	ModeNow   ChargeMode = "now"
 	ModeMinPV ChargeMode = "minpv"
 	ModePV    ChargeMode = "pv"
+	ModeV2H   ChargeMode = "v2h"
 )
 
 // String implements Stringer
@@ -31,6 +32,22 @@ func (c ChargeMode) String() string {
 	return string(c)
 }
 
+// BatteryMode is the home battery's operation mode
+type BatteryMode string
+
+// Battery modes
+const (
+	BatteryUnknown BatteryMode = ""
+	BatteryNormal  BatteryMode = "normal"
+	BatteryHold    BatteryMode = "hold"
+	BatteryCharge  BatteryMode = "charge"
+)
+
+// String implements Stringer
+func (m BatteryMode) String() string {
+	return string(m)
+}
+
 // ChargeStatus is the EV's charging status from A to F
 type ChargeStatus string
 
@@ -112,6 +129,11 @@ type Battery interface {
 	SoC() (float64, error)
 }
 
+// BatteryController controls the home battery's operation mode
+type BatteryController interface {
+	SetBatteryMode(BatteryMode) error
+}
+
 // ChargeState provides current charging status
 type ChargeState interface {
 	Status() (ChargeStatus, error)
@@ -135,6 +157,17 @@ type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
 }
 
+// BidirectionalCharger is able to discharge the vehicle into the home (vehicle-to-home)
+type BidirectionalCharger interface {
+	Discharge(current float64) error // discharge current in A, 0 to resume charging
+}
+
+// Consumer is a controllable non-vehicle load like a heat pump, heating rod or switch socket
+type Consumer interface {
+	Levels() []float64 // nominal power in W per level, level 0 being idle
+	SetLevel(level int) error
+}
+
 // Diagnosis is a helper interface that allows to dump diagnostic data to console
 type Diagnosis interface {
 	Diagnose()
@@ -217,6 +250,27 @@ type Tariff interface {
 	CurrentPrice() (float64, error) // EUR/kWh, CHF/kWh, ...
 }
 
+// Tariff usages
+const (
+	TariffGrid   = "grid"
+	TariffFeedIn = "feedin"
+)
+
+// TariffRates provides the tariff's price forecast
+type TariffRates interface {
+	Rates() (Rates, error)
+}
+
+// CO2Intensity provides the grid's current carbon intensity
+type CO2Intensity interface {
+	CurrentCO2Intensity() (float64, error) // gCO2/kWh
+}
+
+// SolarForecaster provides the expected pv production
+type SolarForecaster interface {
+	SolarForecast() (Forecast, error)
+}
+
 // AuthProvider is the ability to provide OAuth authentication through the ui
 type AuthProvider interface {
 	SetCallbackParams(baseURL, redirectURL string, authenticated chan<- bool)
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ForecastSlot is the expected average pv power in W for a time period
+type ForecastSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Power float64   `json:"power"`
+}
+
+// Forecast is a slice of pv power forecast slots
+type Forecast []ForecastSlot
+
+// Sort forecast by start time
+func (f Forecast) Sort() {
+	sort.Slice(f, func(i, j int) bool {
+		return f[i].Start.Before(f[j].Start)
+	})
+}
+
+// Limit returns a copy of the forecast with power capped at the given value
+func (f Forecast) Limit(power float64) Forecast {
+	res := make(Forecast, 0, len(f))
+	for _, s := range f {
+		s.Power = math.Min(s.Power, power)
+		res = append(res, s)
+	}
+	return res
+}
+
+// Reduce returns a copy of the forecast with power reduced by the given value, not below zero
+func (f Forecast) Reduce(power float64) Forecast {
+	res := make(Forecast, 0, len(f))
+	for _, s := range f {
+		s.Power = math.Max(s.Power-power, 0)
+		res = append(res, s)
+	}
+	return res
+}
+
+// Energy returns the expected energy in Wh between from and to, prorating partially covered slots
+func (f Forecast) Energy(from, to time.Time) float64 {
+	var res float64
+	for _, s := range f {
+		start, end := s.Start, s.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		if end.After(start) {
+			res += s.Power * end.Sub(start).Hours()
+		}
+	}
+	return res
+}
@@ -19,6 +19,8 @@ func ChargeModeString(mode string) (ChargeMode, error) {
 		return ModePV, nil
 	case string(ModeOff):
 		return ModeOff, nil
+	case string(ModeV2H):
+		return ModeV2H, nil
 	default:
 		return "", fmt.Errorf("invalid value: %s", mode)
 	}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"sort"
+	"time"
+)
+
+// Rate is a grid tariff rate
+type Rate struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Price float64   `json:"price"`
+}
+
+// IsActive returns true if the rate is valid at the given time
+func (r Rate) IsActive(t time.Time) bool {
+	return !r.Start.After(t) && r.End.After(t)
+}
+
+// Rates is a slice of (future) tariff rates
+type Rates []Rate
+
+// Sort rates by start time
+func (r Rates) Sort() {
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Start.Before(r[j].Start)
+	})
+}
+
+// Current returns the rate active at the given time
+func (r Rates) Current(t time.Time) (Rate, error) {
+	for _, rr := range r {
+		if rr.IsActive(t) {
+			return rr, nil
+		}
+	}
+
+	return Rate{}, ErrNotAvailable
+}
@@ -19,6 +19,8 @@ const (
 	idleFactor           = 0.6
 )
 
+// EEBus is an EEBus charger. It does not implement api.BidirectionalCharger since the eebus
+// stack only provides the overload protection and self consumption limits for charging.
 type EEBus struct {
 	log *util.Logger
 	cc  *communication.ConnectionController
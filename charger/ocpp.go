@@ -50,6 +50,8 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 		MeterValues   string
 		InitialReset  interface{} // TODO deprecated
 		Timeout       time.Duration
+		Bidirectional bool
+		Proxy         ocpp.ProxyConfig
 	}{
 		Connector: 1,
 		IdTag:     defaultIdTag,
@@ -69,6 +71,13 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 	// 	return nil, fmt.Errorf("unknown configuration option detected for reset: %s", cc.InitialReset)
 	// }
 
+	// forward to upstream central system
+	if cc.Proxy.URI != "" {
+		if err := ocpp.Instance().Proxy(cc.StationId, cc.Proxy); err != nil {
+			return nil, err
+		}
+	}
+
 	c, err := NewOCPP(cc.StationId, cc.Connector, cc.IdTag, cc.MeterValues, cc.MeterInterval, cc.Quirks, cc.Timeout)
 	if err != nil {
 		return c, err
@@ -94,10 +103,15 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 		phasesS = c.phases1p3p
 	}
 
-	return decorateOCPP(c, powerG, totalEnergyG, currentsG, phasesS), nil
+	var dischargeS func(float64) error
+	if cc.Bidirectional {
+		dischargeS = c.discharge
+	}
+
+	return decorateOCPP(c, powerG, totalEnergyG, currentsG, phasesS, dischargeS), nil
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateOCPP -b *OCPP -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)"
+// go:generate go run ../cmd/tools/decorate.go -f decorateOCPP -b *OCPP -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(phases int) error" -t "api.BidirectionalCharger,Discharge,func(current float64) error"
 
 // NewOCPP creates OCPP charger
 func NewOCPP(id string, connector int, idtag string, meterValues string, meterInterval time.Duration, quirks bool, timeout time.Duration) (*OCPP, error) {
@@ -295,6 +309,18 @@ func (c *OCPP) wait(err error, rc chan error) error {
 	return err
 }
 
+var _ ocpp.ChargePointProvider = (*OCPP)(nil)
+
+// ChargePoint implements the ocpp.ChargePointProvider interface
+func (c *OCPP) ChargePoint() *ocpp.CP {
+	return c.cp
+}
+
+// Connector implements the ocpp.ChargePointProvider interface
+func (c *OCPP) Connector() int {
+	return c.connector
+}
+
 // Status implements the api.Charger interface
 func (c *OCPP) Status() (api.ChargeStatus, error) {
 	return c.cp.Status()
@@ -311,6 +337,9 @@ func (c *OCPP) Enable(enable bool) error {
 	rc := make(chan error, 1)
 
 	if enable {
+		// remote started transactions must be accepted
+		c.cp.RemoteStart(c.idtag)
+
 		err = ocpp.Instance().RemoteStartTransaction(c.cp.ID(), func(resp *core.RemoteStartTransactionConfirmation, err error) {
 			c.log.TRACE.Printf("%T: %+v", resp, resp)
 
@@ -318,6 +347,10 @@ func (c *OCPP) Enable(enable bool) error {
 				err = errors.New(string(resp.Status))
 			}
 
+			if err != nil {
+				c.cp.RemoteStart("")
+			}
+
 			rc <- err
 		}, c.idtag, func(request *core.RemoteStartTransactionRequest) {
 			request.ConnectorId = &c.connector
@@ -406,6 +439,58 @@ func (c *OCPP) MaxCurrentMillis(current float64) error {
 	return err
 }
 
+// Discharge implements the api.BidirectionalCharger interface
+func (c *OCPP) discharge(current float64) error {
+	if current == 0 {
+		return c.updatePeriod(c.current, c.phases)
+	}
+
+	// discharging profile can only be sent if transaction is active
+	enabled, err := c.Enabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return api.ErrMustRetry
+	}
+
+	c.log.TRACE.Printf("update discharge period with phases: %d, current: %f", c.phases, current)
+
+	// discharging is requested by a negative charging schedule limit
+	period := ocpp.DischargingSchedulePeriod{Limit: -current}
+	if c.phases != 0 {
+		period.NumberPhases = &c.phases
+	}
+
+	profile := &ocpp.DischargingProfile{
+		ChargingProfileId:      1,
+		StackLevel:             0,
+		ChargingProfilePurpose: types.ChargingProfilePurposeTxProfile,
+		ChargingProfileKind:    types.ChargingProfileKindRelative,
+		ChargingSchedule: &ocpp.DischargingSchedule{
+			ChargingRateUnit:       types.ChargingRateUnitAmperes,
+			ChargingSchedulePeriod: []ocpp.DischargingSchedulePeriod{period},
+		},
+	}
+
+	rc := make(chan error, 1)
+	err = ocpp.Instance().SetDischargingProfile(c.cp.ID(), func(resp *smartcharging.SetChargingProfileConfirmation, err error) {
+		c.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil && resp.Status != smartcharging.ChargingProfileStatusAccepted {
+			err = errors.New(string(resp.Status))
+		}
+
+		rc <- err
+	}, c.connector, profile)
+
+	if err = c.wait(err, rc); err != nil {
+		err = fmt.Errorf("set discharging profile: %w", err)
+	}
+
+	return err
+}
+
 // CurrentPower implements the api.Meter interface
 func (c *OCPP) currentPower() (float64, error) {
 	return c.cp.CurrentPower()
@@ -431,8 +516,14 @@ func (c *OCPP) phases1p3p(phases int) error {
 	return c.updatePeriod(c.current, c.phases)
 }
 
-// // Identify implements the api.Identifier interface
-// Unless charger uses vehicle ID as idTag in authorize.req it is not possible to implement this in ocpp1.6
-// func (c *OCPP) Identify() (string, error) {
-// 	return "", errors.New("not implemented")
-// }
+var _ api.Identifier = (*OCPP)(nil)
+
+// Identify implements the api.Identifier interface
+func (c *OCPP) Identify() (string, error) {
+	// remote started transactions don't identify the vehicle
+	if id := c.cp.IdTag(); id != c.idtag {
+		return id, nil
+	}
+
+	return "", nil
+}
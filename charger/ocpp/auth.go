@@ -0,0 +1,133 @@
+package ocpp
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// AuthConfig is the central system's id tag authorization configuration
+type AuthConfig struct {
+	Allow         []string // id tags that are always accepted
+	Deny          []string // id tags that are always rejected
+	RejectUnknown bool     // reject id tags that are neither allowed nor vehicle identifiers
+	LocalList     bool     // send allowed and denied id tags to the stations' local authorization list
+}
+
+// Authorizer authorizes id tags against the configured allow and deny lists and the vehicle identifiers
+type Authorizer struct {
+	mu sync.Mutex
+	AuthConfig
+	identifiers []string
+	listeners   []func()
+}
+
+var auth = new(Authorizer)
+
+// Auth returns the central system's authorizer
+func Auth() *Authorizer {
+	return auth
+}
+
+// Configure sets the authorization configuration
+func (a *Authorizer) Configure(conf AuthConfig) {
+	a.mu.Lock()
+	a.AuthConfig = conf
+	a.mu.Unlock()
+
+	a.updated()
+}
+
+// AddIdentifiers adds id tags identifying vehicles or chargers
+func (a *Authorizer) AddIdentifiers(ids ...string) {
+	a.mu.Lock()
+	a.identifiers = append(a.identifiers, ids...)
+	a.mu.Unlock()
+
+	a.updated()
+}
+
+// Subscribe registers a callback for authorization updates
+func (a *Authorizer) Subscribe(fun func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.listeners = append(a.listeners, fun)
+}
+
+func (a *Authorizer) updated() {
+	a.mu.Lock()
+	listeners := a.listeners
+	a.mu.Unlock()
+
+	for _, fun := range listeners {
+		fun()
+	}
+}
+
+// matches checks if id matches any of the identifiers, supporting * placeholders
+func matches(identifiers []string, id string) bool {
+	for _, vid := range identifiers {
+		if strings.EqualFold(id, vid) {
+			return true
+		}
+
+		if strings.Contains(vid, "*") {
+			if re, err := regexp.Compile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(vid), `\*`, ".*?") + "$"); err == nil && re.MatchString(id) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Status returns the authorization status of the id tag
+func (a *Authorizer) Status(idTag string) types.AuthorizationStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case matches(a.Deny, idTag):
+		return types.AuthorizationStatusBlocked
+	case matches(a.Allow, idTag), matches(a.identifiers, idTag), !a.RejectUnknown:
+		return types.AuthorizationStatusAccepted
+	default:
+		return types.AuthorizationStatusInvalid
+	}
+}
+
+// LocalList returns the id tags for the stations' local authorization list if enabled.
+// Identifiers with placeholders cannot be part of the list and are skipped.
+func (a *Authorizer) LocalList() (map[string]types.AuthorizationStatus, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.AuthConfig.LocalList {
+		return nil, false
+	}
+
+	res := make(map[string]types.AuthorizationStatus)
+
+	add := func(ids []string, status types.AuthorizationStatus) {
+		for _, id := range ids {
+			if id != "" && !strings.Contains(id, "*") {
+				res[id] = status
+			}
+		}
+	}
+
+	add(a.identifiers, types.AuthorizationStatusAccepted)
+	add(a.Allow, types.AuthorizationStatusAccepted)
+	add(a.Deny, types.AuthorizationStatusBlocked)
+
+	return res, true
+}
+
+// localListVersion returns an increasing local list version
+func localListVersion() int {
+	return int(time.Now().Unix())
+}
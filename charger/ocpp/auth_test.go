@@ -0,0 +1,113 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthStatus(t *testing.T) {
+	a := new(Authorizer)
+	a.AddIdentifiers("vehicle1", "fleet-*")
+
+	// unknown tags are accepted by default
+	assert.Equal(t, types.AuthorizationStatusAccepted, a.Status("unknown"))
+
+	a.Configure(AuthConfig{
+		Allow:         []string{"guest"},
+		Deny:          []string{"stolen", "fleet-99"},
+		RejectUnknown: true,
+	})
+
+	tc := []struct {
+		idTag    string
+		expected types.AuthorizationStatus
+	}{
+		{"vehicle1", types.AuthorizationStatusAccepted},
+		{"VEHICLE1", types.AuthorizationStatusAccepted},
+		{"fleet-12", types.AuthorizationStatusAccepted},
+		{"guest", types.AuthorizationStatusAccepted},
+		{"stolen", types.AuthorizationStatusBlocked},
+		{"fleet-99", types.AuthorizationStatusBlocked},
+		{"unknown", types.AuthorizationStatusInvalid},
+		{"xfleet-1", types.AuthorizationStatusInvalid},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.expected, a.Status(tc.idTag), tc.idTag)
+	}
+}
+
+func TestAuthLocalList(t *testing.T) {
+	a := new(Authorizer)
+	a.AddIdentifiers("vehicle1", "fleet-*")
+
+	_, ok := a.LocalList()
+	assert.False(t, ok)
+
+	var updated int
+	a.Subscribe(func() { updated++ })
+
+	a.Configure(AuthConfig{
+		Allow:     []string{"guest"},
+		Deny:      []string{"stolen"},
+		LocalList: true,
+	})
+	assert.Equal(t, 1, updated)
+
+	list, ok := a.LocalList()
+	assert.True(t, ok)
+	assert.Equal(t, map[string]types.AuthorizationStatus{
+		"vehicle1": types.AuthorizationStatusAccepted,
+		"guest":    types.AuthorizationStatusAccepted,
+		"stolen":   types.AuthorizationStatusBlocked,
+	}, list)
+}
+
+func TestAuthStartTransaction(t *testing.T) {
+	Auth().Configure(AuthConfig{Deny: []string{"stolen"}})
+	defer Auth().Configure(AuthConfig{})
+
+	cp := NewChargePoint(util.NewLogger("foo"), "test", time.Second)
+
+	res, err := cp.StartTransaction(&core.StartTransactionRequest{IdTag: "stolen", Timestamp: types.NewDateTime(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, types.AuthorizationStatusBlocked, res.IdTagInfo.Status)
+	assert.Equal(t, 0, cp.TransactionID())
+
+	res, err = cp.StartTransaction(&core.StartTransactionRequest{IdTag: "vehicle", Timestamp: types.NewDateTime(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, types.AuthorizationStatusAccepted, res.IdTagInfo.Status)
+	assert.Equal(t, res.TransactionId, cp.TransactionID())
+	assert.Equal(t, "vehicle", cp.IdTag())
+}
+
+func TestAuthRemoteStart(t *testing.T) {
+	Auth().Configure(AuthConfig{RejectUnknown: true})
+	defer Auth().Configure(AuthConfig{})
+
+	cp := NewChargePoint(util.NewLogger("foo"), "test", time.Second)
+	other := NewChargePoint(util.NewLogger("foo"), "other", time.Second)
+
+	cp.RemoteStart("evcc")
+
+	// remote start id tag is not accepted globally
+	assert.Equal(t, types.AuthorizationStatusInvalid, Auth().Status("evcc"))
+	res, err := other.StartTransaction(&core.StartTransactionRequest{IdTag: "evcc", Timestamp: types.NewDateTime(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, types.AuthorizationStatusInvalid, res.IdTagInfo.Status)
+
+	res, err = cp.StartTransaction(&core.StartTransactionRequest{IdTag: "evcc", Timestamp: types.NewDateTime(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, types.AuthorizationStatusAccepted, res.IdTagInfo.Status)
+
+	// remote start is consumed by the transaction
+	res, err = cp.StartTransaction(&core.StartTransactionRequest{IdTag: "evcc", Timestamp: types.NewDateTime(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, types.AuthorizationStatusInvalid, res.IdTagInfo.Status)
+}
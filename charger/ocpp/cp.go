@@ -49,6 +49,11 @@ type CP struct {
 
 	txnCount int // change initial value to the last known global transaction. Needs persistence
 	txnId    int
+	idTag    string // authorized id tag
+
+	remoteIdTag string // id tag of a pending remote start, accepted on this connection only
+
+	maintenance MaintenanceStatus
 }
 
 func NewChargePoint(log *util.Logger, id string, timeout time.Duration) *CP {
@@ -93,6 +98,16 @@ func (cp *CP) HasConnected() <-chan struct{} {
 	return cp.connectC
 }
 
+// connected returns true if the chargepoint has connected
+func (cp *CP) connected() bool {
+	select {
+	case <-cp.connectC:
+		return true
+	default:
+		return false
+	}
+}
+
 func (cp *CP) Initialized(timeout time.Duration) bool {
 	cp.log.DEBUG.Printf("waiting for chargepoint status: %v", timeout)
 
@@ -137,6 +152,40 @@ func (cp *CP) TransactionID() int {
 	return cp.txnId
 }
 
+// setTransactionID replaces the current transaction id with the id assigned by an upstream central system
+func (cp *CP) setTransactionID(id int) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.txnId != 0 {
+		cp.txnId = id
+	}
+}
+
+// RemoteStart accepts the id tag of a remote start requested by the central system
+// for the next authorization and transaction on this charge point
+func (cp *CP) RemoteStart(idTag string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.remoteIdTag = idTag
+}
+
+// authStatus returns the authorization status of the id tag. Must be called with lock held.
+func (cp *CP) authStatus(idTag string) types.AuthorizationStatus {
+	if cp.remoteIdTag != "" && idTag == cp.remoteIdTag {
+		return types.AuthorizationStatusAccepted
+	}
+
+	return Auth().Status(idTag)
+}
+
+// IdTag returns the id tag of the current transaction or the last authorization
+func (cp *CP) IdTag() string {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.idTag
+}
+
 func (cp *CP) Status() (api.ChargeStatus, error) {
 	cp.mu.Lock()
 	defer cp.mu.Unlock()
@@ -183,12 +232,29 @@ func (cp *CP) CurrentPower() (float64, error) {
 		return 0, api.ErrNotAvailable
 	}
 
-	if m, ok := cp.measurements[string(types.MeasurandPowerActiveImport)]; ok {
+	m, ok := cp.measurements[string(types.MeasurandPowerActiveImport)]
+	if !ok {
+		return 0, api.ErrNotAvailable
+	}
+
+	f, err := strconv.ParseFloat(m.Value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	power := scale(f, m.Unit)
+
+	// bidirectional chargers report discharging as export
+	if m, ok := cp.measurements[string(types.MeasurandPowerActiveExport)]; ok {
 		f, err := strconv.ParseFloat(m.Value, 64)
-		return scale(f, m.Unit), err
+		if err != nil {
+			return 0, err
+		}
+
+		power -= scale(f, m.Unit)
 	}
 
-	return 0, api.ErrNotAvailable
+	return power, nil
 }
 
 var _ api.MeterEnergy = (*CP)(nil)
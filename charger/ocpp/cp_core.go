@@ -16,10 +16,20 @@ const (
 func (cp *CP) Authorize(request *core.AuthorizeRequest) (*core.AuthorizeConfirmation, error) {
 	cp.log.TRACE.Printf("%T: %+v", request, request)
 
-	// TODO check if this authorizes foreign RFID tags
+	cp.mu.Lock()
+	status := cp.authStatus(request.IdTag)
+	if status == types.AuthorizationStatusAccepted {
+		cp.idTag = request.IdTag
+	}
+	cp.mu.Unlock()
+
+	if status != types.AuthorizationStatusAccepted {
+		cp.log.WARN.Printf("authorize: %s %s", request.IdTag, status)
+	}
+
 	res := &core.AuthorizeConfirmation{
 		IdTagInfo: &types.IdTagInfo{
-			Status: types.AuthorizationStatusAccepted,
+			Status: status,
 		},
 	}
 
@@ -141,13 +151,28 @@ func (cp *CP) StartTransaction(request *core.StartTransactionRequest) (*core.Sta
 		TransactionId: 1, // default
 	}
 
+	if request != nil {
+		if status := cp.authStatus(request.IdTag); status != types.AuthorizationStatusAccepted {
+			cp.log.WARN.Printf("start transaction: %s %s", request.IdTag, status)
+			res.IdTagInfo.Status = status
+		} else {
+			cp.idTag = request.IdTag
+		}
+
+		// pending remote start is consumed by the transaction
+		cp.remoteIdTag = ""
+	}
+
 	// create new transaction
 	if request != nil && time.Since(request.Timestamp.Time) < transactionExpiry { // only respect transactions in the last hour
 		cp.txnCount++
 		res.TransactionId = cp.txnCount
 	}
 
-	cp.txnId = res.TransactionId
+	// rejected transactions are not tracked
+	if res.IdTagInfo.Status == types.AuthorizationStatusAccepted {
+		cp.txnId = res.TransactionId
+	}
 
 	return res, nil
 }
@@ -166,6 +191,7 @@ func (cp *CP) StopTransaction(request *core.StopTransactionRequest) (*core.StopT
 		}
 
 		cp.txnId = 0
+		cp.idTag = ""
 	}
 
 	res := &core.StopTransactionConfirmation{
@@ -180,11 +206,23 @@ func (cp *CP) StopTransaction(request *core.StopTransactionRequest) (*core.StopT
 func (cp *CP) DiagnosticStatusNotification(request *firmware.DiagnosticsStatusNotificationRequest) (*firmware.DiagnosticsStatusNotificationConfirmation, error) {
 	cp.log.TRACE.Printf("%T: %+v", request, request)
 
+	if request != nil {
+		cp.updateMaintenance(func(status *MaintenanceStatus) {
+			status.Diagnostics = request.Status
+		})
+	}
+
 	return &firmware.DiagnosticsStatusNotificationConfirmation{}, nil
 }
 
 func (cp *CP) FirmwareStatusNotification(request *firmware.FirmwareStatusNotificationRequest) (*firmware.FirmwareStatusNotificationConfirmation, error) {
 	cp.log.TRACE.Printf("%T: %+v", request, request)
 
+	if request != nil {
+		cp.updateMaintenance(func(status *MaintenanceStatus) {
+			status.Firmware = request.Status
+		})
+	}
+
 	return &firmware.FirmwareStatusNotificationConfirmation{}, nil
 }
@@ -0,0 +1,232 @@
+package ocpp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// ChargePointProvider is implemented by chargers connected using OCPP 1.6
+type ChargePointProvider interface {
+	ChargePoint() *CP
+	Connector() int
+}
+
+// MaintenanceStatus is the charge point's firmware update and diagnostics upload progress
+type MaintenanceStatus struct {
+	Firmware    firmware.FirmwareStatus    `json:"firmware,omitempty"`
+	Diagnostics firmware.DiagnosticsStatus `json:"diagnostics,omitempty"`
+}
+
+var publisher struct {
+	sync.Mutex
+	status  map[string]MaintenanceStatus
+	publish func(map[string]MaintenanceStatus)
+}
+
+// SetStatusPublisher registers a callback receiving the maintenance status of all charge points by id
+func SetStatusPublisher(publish func(map[string]MaintenanceStatus)) {
+	publisher.Lock()
+	defer publisher.Unlock()
+
+	publisher.publish = publish
+}
+
+// publishStatus publishes the charge point's maintenance status
+func publishStatus(id string, status MaintenanceStatus) {
+	publisher.Lock()
+	defer publisher.Unlock()
+
+	if publisher.status == nil {
+		publisher.status = make(map[string]MaintenanceStatus)
+	}
+	publisher.status[id] = status
+
+	if publisher.publish != nil {
+		res := make(map[string]MaintenanceStatus, len(publisher.status))
+		for k, v := range publisher.status {
+			res[k] = v
+		}
+
+		publisher.publish(res)
+	}
+}
+
+// MaintenanceStatus returns the charge point's firmware and diagnostics status
+func (cp *CP) MaintenanceStatus() MaintenanceStatus {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.maintenance
+}
+
+// updateMaintenance updates and publishes the maintenance status
+func (cp *CP) updateMaintenance(fun func(*MaintenanceStatus)) {
+	cp.mu.Lock()
+	fun(&cp.maintenance)
+	status := cp.maintenance
+	cp.mu.Unlock()
+
+	publishStatus(cp.ID(), status)
+}
+
+// wait waits for the charge point's response with timeout
+func (cp *CP) wait(err error, rc chan error) error {
+	if err == nil {
+		select {
+		case err = <-rc:
+		case <-time.After(cp.timeout):
+			err = api.ErrTimeout
+		}
+	}
+	return err
+}
+
+// GetConfiguration returns the charge point's configuration keys. Empty keys return all keys.
+func (cp *CP) GetConfiguration(keys []string) ([]core.ConfigurationKey, error) {
+	var res []core.ConfigurationKey
+	rc := make(chan error, 1)
+
+	err := Instance().GetConfiguration(cp.ID(), func(resp *core.GetConfigurationConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil {
+			res = resp.ConfigurationKey
+			if len(resp.UnknownKey) > 0 {
+				err = fmt.Errorf("unknown keys: %v", resp.UnknownKey)
+			}
+		}
+
+		rc <- err
+	}, keys)
+
+	if err := cp.wait(err, rc); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ChangeConfiguration changes the charge point's configuration key.
+// The returned status indicates if a reboot is required.
+func (cp *CP) ChangeConfiguration(key, value string) (core.ConfigurationStatus, error) {
+	var res core.ConfigurationStatus
+	rc := make(chan error, 1)
+
+	err := Instance().ChangeConfiguration(cp.ID(), func(resp *core.ChangeConfigurationConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil {
+			res = resp.Status
+			if res != core.ConfigurationStatusAccepted && res != core.ConfigurationStatusRebootRequired {
+				err = fmt.Errorf("change configuration: %s", res)
+			}
+		}
+
+		rc <- err
+	}, key, value)
+
+	if err := cp.wait(err, rc); err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+// Reset resets the charge point
+func (cp *CP) Reset(resetType core.ResetType) error {
+	rc := make(chan error, 1)
+
+	err := Instance().Reset(cp.ID(), func(resp *core.ResetConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil && resp.Status != core.ResetStatusAccepted {
+			err = fmt.Errorf("reset: %s", resp.Status)
+		}
+
+		rc <- err
+	}, resetType)
+
+	return cp.wait(err, rc)
+}
+
+// UnlockConnector unlocks the connector's cable
+func (cp *CP) UnlockConnector(connector int) error {
+	rc := make(chan error, 1)
+
+	err := Instance().UnlockConnector(cp.ID(), func(resp *core.UnlockConnectorConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil && resp.Status != core.UnlockStatusUnlocked {
+			err = fmt.Errorf("unlock connector: %s", resp.Status)
+		}
+
+		rc <- err
+	}, connector)
+
+	return cp.wait(err, rc)
+}
+
+// TriggerMessage requests the charge point to send the given message for the connector.
+// Connector 0 and charge point messages like BootNotification refer to the charge point.
+func (cp *CP) TriggerMessage(message remotetrigger.MessageTrigger, connector int) error {
+	rc := make(chan error, 1)
+
+	err := Instance().TriggerMessage(cp.ID(), func(resp *remotetrigger.TriggerMessageConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil && resp.Status != remotetrigger.TriggerMessageStatusAccepted {
+			err = fmt.Errorf("trigger message: %s", resp.Status)
+		}
+
+		rc <- err
+	}, message, func(request *remotetrigger.TriggerMessageRequest) {
+		if connector > 0 && message != core.BootNotificationFeatureName && message != core.HeartbeatFeatureName {
+			request.ConnectorId = &connector
+		}
+	})
+
+	return cp.wait(err, rc)
+}
+
+// GetDiagnostics requests the charge point to upload diagnostics to the given location and returns the file name.
+// Upload progress is reported by the charge point's maintenance status.
+func (cp *CP) GetDiagnostics(location string) (string, error) {
+	var res string
+	rc := make(chan error, 1)
+
+	err := Instance().GetDiagnostics(cp.ID(), func(resp *firmware.GetDiagnosticsConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil {
+			res = resp.FileName
+		}
+
+		rc <- err
+	}, location)
+
+	if err := cp.wait(err, rc); err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+// UpdateFirmware requests the charge point to install the firmware from the given location.
+// Installation progress is reported by the charge point's maintenance status.
+func (cp *CP) UpdateFirmware(location string, retrieveDate time.Time) error {
+	rc := make(chan error, 1)
+
+	err := Instance().UpdateFirmware(cp.ID(), func(resp *firmware.UpdateFirmwareConfirmation, err error) {
+		cp.log.TRACE.Printf("%T: %+v", resp, resp)
+		rc <- err
+	}, location, types.NewDateTime(retrieveDate))
+
+	return cp.wait(err, rc)
+}
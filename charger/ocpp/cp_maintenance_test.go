@@ -0,0 +1,34 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceStatus(t *testing.T) {
+	var published map[string]MaintenanceStatus
+	SetStatusPublisher(func(status map[string]MaintenanceStatus) {
+		published = status
+	})
+	defer SetStatusPublisher(nil)
+
+	cp := NewChargePoint(util.NewLogger("foo"), "cp1", time.Minute)
+
+	_, err := cp.FirmwareStatusNotification(&firmware.FirmwareStatusNotificationRequest{Status: firmware.FirmwareStatusDownloading})
+	assert.NoError(t, err)
+
+	_, err = cp.DiagnosticStatusNotification(&firmware.DiagnosticsStatusNotificationRequest{Status: firmware.DiagnosticsStatusUploading})
+	assert.NoError(t, err)
+
+	expected := MaintenanceStatus{
+		Firmware:    firmware.FirmwareStatusDownloading,
+		Diagnostics: firmware.DiagnosticsStatusUploading,
+	}
+
+	assert.Equal(t, expected, cp.MaintenanceStatus())
+	assert.Equal(t, map[string]MaintenanceStatus{"cp1": expected}, published)
+}
@@ -13,6 +13,7 @@ type CS struct {
 	mu  sync.Mutex
 	log *util.Logger
 	ocpp16.CentralSystem
+	mux *mux
 	cps map[string]*CP
 }
 
@@ -29,6 +30,35 @@ func (cs *CS) Register(id string, cp *CP) error {
 	return nil
 }
 
+// Proxy forwards the charge point's messages to an upstream central system.
+// Must be called before the charge point connects.
+func (cs *CS) Proxy(id string, conf ProxyConfig) error {
+	if id == "" {
+		return errors.New("proxy requires station id")
+	}
+
+	switch conf.Precedence {
+	case "":
+		conf.Precedence = PrecedenceEvcc
+	case PrecedenceEvcc, PrecedenceUpstream:
+	default:
+		return fmt.Errorf("invalid precedence: %s", conf.Precedence)
+	}
+
+	p := newProxy(cs.log, id, conf)
+	p.txn = func(txnId int) {
+		cs.mu.Lock()
+		cp, err := cs.chargepointByID(id)
+		cs.mu.Unlock()
+
+		if err == nil {
+			cp.setTransactionID(txnId)
+		}
+	}
+
+	return cs.mux.proxy(id, p)
+}
+
 // errorHandler logs error channel
 func (cs *CS) errorHandler(errC <-chan error) {
 	for err := range errC {
@@ -58,6 +88,7 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 			delete(cs.cps, "")
 
 			cp.Connect()
+			go cs.SendLocalListRequest(chargePoint.ID())
 
 			return
 		}
@@ -66,6 +97,7 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 	} else {
 		cs.log.DEBUG.Printf("chargepoint connected: %s", chargePoint.ID())
 		cp.Connect()
+		go cs.SendLocalListRequest(chargePoint.ID())
 	}
 }
 
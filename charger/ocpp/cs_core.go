@@ -3,7 +3,9 @@ package ocpp
 import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/localauth"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
 // cs actions
@@ -44,6 +46,63 @@ func (cs *CS) TriggerMessageRequest(id string, requestedMessage remotetrigger.Me
 	}
 }
 
+// maxIdTagLength is the maximum length of OCPP 1.6 id tags
+const maxIdTagLength = 20
+
+// SendLocalListRequest replaces the chargepoint's local authorization list if enabled
+func (cs *CS) SendLocalListRequest(id string) {
+	list, ok := Auth().LocalList()
+	if !ok {
+		return
+	}
+
+	var data []localauth.AuthorizationData
+	for idTag, status := range list {
+		if len(idTag) > maxIdTagLength {
+			continue
+		}
+
+		data = append(data, localauth.AuthorizationData{
+			IdTag:     idTag,
+			IdTagInfo: &types.IdTagInfo{Status: status},
+		})
+	}
+
+	if err := cs.SendLocalList(id, func(request *localauth.SendLocalListConfirmation, err error) {
+		log := cs.log.TRACE
+		if err == nil && request != nil && request.Status != localauth.UpdateStatusAccepted {
+			log = cs.log.ERROR
+		}
+
+		var status localauth.UpdateStatus
+		if request != nil {
+			status = request.Status
+		}
+
+		log.Printf("SendLocalList for %s: %+v", id, status)
+	}, localListVersion(), localauth.UpdateTypeFull, func(request *localauth.SendLocalListRequest) {
+		request.LocalAuthorizationList = data
+	}); err != nil {
+		cs.log.ERROR.Printf("send SendLocalList for %s failed: %v", id, err)
+	}
+}
+
+// updateLocalLists sends the local authorization list to all connected chargepoints
+func (cs *CS) updateLocalLists() {
+	cs.mu.Lock()
+	var ids []string
+	for id, cp := range cs.cps {
+		if id != "" && cp.connected() {
+			ids = append(ids, id)
+		}
+	}
+	cs.mu.Unlock()
+
+	for _, id := range ids {
+		cs.SendLocalListRequest(id)
+	}
+}
+
 // cp actions
 
 func (cs *CS) OnAuthorize(id string, request *core.AuthorizeRequest) (*core.AuthorizeConfirmation, error) {
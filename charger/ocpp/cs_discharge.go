@@ -0,0 +1,66 @@
+package ocpp
+
+import (
+	"fmt"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// DischargingSchedulePeriod is a charging schedule period with a negative limit requesting discharge
+type DischargingSchedulePeriod struct {
+	StartPeriod  int     `json:"startPeriod" validate:"gte=0"`
+	Limit        float64 `json:"limit" validate:"lte=0"`
+	NumberPhases *int    `json:"numberPhases,omitempty" validate:"omitempty,gte=0"`
+}
+
+// DischargingSchedule is a charging schedule of discharging periods
+type DischargingSchedule struct {
+	ChargingRateUnit       types.ChargingRateUnitType  `json:"chargingRateUnit" validate:"required,chargingRateUnit"`
+	ChargingSchedulePeriod []DischargingSchedulePeriod `json:"chargingSchedulePeriod" validate:"required,min=1,dive"`
+}
+
+// DischargingProfile is a charging profile with a discharging schedule
+type DischargingProfile struct {
+	ChargingProfileId      int                              `json:"chargingProfileId"`
+	StackLevel             int                              `json:"stackLevel" validate:"gte=0"`
+	ChargingProfilePurpose types.ChargingProfilePurposeType `json:"chargingProfilePurpose" validate:"required,chargingProfilePurpose"`
+	ChargingProfileKind    types.ChargingProfileKindType    `json:"chargingProfileKind" validate:"required,chargingProfileKind"`
+	ChargingSchedule       *DischargingSchedule             `json:"chargingSchedule" validate:"required"`
+}
+
+// SetDischargingProfileRequest is a SetChargingProfile request with negative schedule limits.
+// The OCPP 1.6 schema only permits non-negative limits which bidirectional chargers use for discharging.
+// Message validation stays enabled, only the limit's sign is exempted.
+type SetDischargingProfileRequest struct {
+	ConnectorId     int                 `json:"connectorId" validate:"gte=0"`
+	ChargingProfile *DischargingProfile `json:"csChargingProfiles" validate:"required"`
+}
+
+// GetFeatureName implements the ocpp.Request interface
+func (r SetDischargingProfileRequest) GetFeatureName() string {
+	return smartcharging.SetChargingProfileFeatureName
+}
+
+// SetDischargingProfile sends a discharging profile to the charge point
+func (cs *CS) SetDischargingProfile(id string, callback func(*smartcharging.SetChargingProfileConfirmation, error), connectorId int, profile *DischargingProfile) error {
+	request := &SetDischargingProfileRequest{
+		ConnectorId:     connectorId,
+		ChargingProfile: profile,
+	}
+
+	return cs.SendRequestAsync(id, request, func(response ocpp.Response, err error) {
+		if err != nil {
+			callback(nil, err)
+			return
+		}
+
+		if res, ok := response.(*smartcharging.SetChargingProfileConfirmation); ok {
+			callback(res, nil)
+			return
+		}
+
+		callback(nil, fmt.Errorf("invalid response: %T", response))
+	})
+}
@@ -0,0 +1,52 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDischargingProfileRequest(t *testing.T) {
+	phases := 3
+	req := SetDischargingProfileRequest{
+		ConnectorId: 1,
+		ChargingProfile: &DischargingProfile{
+			ChargingProfileId:      1,
+			ChargingProfilePurpose: types.ChargingProfilePurposeTxProfile,
+			ChargingProfileKind:    types.ChargingProfileKindRelative,
+			ChargingSchedule: &DischargingSchedule{
+				ChargingRateUnit:       types.ChargingRateUnitAmperes,
+				ChargingSchedulePeriod: []DischargingSchedulePeriod{{Limit: -16, NumberPhases: &phases}},
+			},
+		},
+	}
+
+	// negative limit passes validation
+	assert.NoError(t, ocppj.Validate.Struct(req))
+
+	// remaining schema is still validated
+	req.ChargingProfile.ChargingProfilePurpose = "foo"
+	assert.Error(t, ocppj.Validate.Struct(req))
+	req.ChargingProfile.ChargingProfilePurpose = types.ChargingProfilePurposeTxProfile
+
+	req.ChargingProfile.ChargingSchedule.ChargingSchedulePeriod[0].StartPeriod = -1
+	assert.Error(t, ocppj.Validate.Struct(req))
+	req.ChargingProfile.ChargingSchedule.ChargingSchedulePeriod[0].StartPeriod = 0
+
+	// payload matches the standard request
+	b, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var std smartcharging.SetChargingProfileRequest
+	require.NoError(t, json.Unmarshal(b, &std))
+	assert.Equal(t, -16.0, std.ChargingProfile.ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+	assert.Equal(t, smartcharging.SetChargingProfileFeatureName, req.GetFeatureName())
+
+	// standard schedule period rejects negative limits
+	assert.Error(t, ocppj.Validate.Struct(std.ChargingProfile.ChargingSchedule.ChargingSchedulePeriod[0]))
+}
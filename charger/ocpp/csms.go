@@ -0,0 +1,86 @@
+package ocpp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/evcc-io/evcc/util"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+)
+
+// CSMS is the OCPP 2.0.1 central system
+type CSMS struct {
+	mu  sync.Mutex
+	log *util.Logger
+	ocpp2.CSMS
+	stations map[string]*Station
+
+	remoteStartID int
+}
+
+func (cs *CSMS) Register(id string, station *Station) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, ok := cs.stations[id]; ok && id == "" {
+		return errors.New("cannot have >1 station with empty station id")
+	}
+
+	cs.stations[id] = station
+
+	return nil
+}
+
+// errorHandler logs error channel
+func (cs *CSMS) errorHandler(errC <-chan error) {
+	for err := range errC {
+		cs.log.ERROR.Println(err)
+	}
+}
+
+func (cs *CSMS) stationByID(id string) (*Station, error) {
+	station, ok := cs.stations[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown station: %s", id)
+	}
+	return station, nil
+}
+
+func (cs *CSMS) NewChargingStation(chargingStation ocpp2.ChargingStationConnection) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if station, err := cs.stationByID(chargingStation.ID()); err != nil {
+		if station, ok := cs.stations[""]; ok {
+			cs.log.INFO.Printf("station connected, registering: %s", chargingStation.ID())
+
+			// update id
+			station.RegisterID(chargingStation.ID())
+			cs.stations[chargingStation.ID()] = station
+			delete(cs.stations, "")
+
+			station.Connect()
+			go cs.SendLocalListRequest(chargingStation.ID())
+
+			return
+		}
+
+		cs.log.WARN.Printf("station connected, ignoring: %s", chargingStation.ID())
+	} else {
+		cs.log.DEBUG.Printf("station connected: %s", chargingStation.ID())
+		station.Connect()
+		go cs.SendLocalListRequest(chargingStation.ID())
+	}
+}
+
+func (cs *CSMS) ChargingStationDisconnected(chargingStation ocpp2.ChargingStationConnection) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, err := cs.stationByID(chargingStation.ID()); err != nil {
+		cs.log.ERROR.Printf("station disconnected: %v", err)
+	} else {
+		cs.log.DEBUG.Printf("station disconnected: %s", chargingStation.ID())
+	}
+}
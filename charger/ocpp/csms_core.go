@@ -0,0 +1,183 @@
+package ocpp
+
+import (
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/localauth"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// cs actions
+
+func (cs *CSMS) TriggerMessageRequest(id string, evse int, requestedMessage remotecontrol.MessageTrigger) {
+	if err := cs.TriggerMessage(id, func(request *remotecontrol.TriggerMessageResponse, err error) {
+		log := cs.log.TRACE
+		if err == nil && request != nil && request.Status != remotecontrol.TriggerMessageStatusAccepted {
+			log = cs.log.ERROR
+		}
+
+		var status remotecontrol.TriggerMessageStatus
+		if request != nil {
+			status = request.Status
+		}
+
+		log.Printf("TriggerMessage %s for %s: %+v", requestedMessage, id, status)
+	}, requestedMessage, func(request *remotecontrol.TriggerMessageRequest) {
+		request.Evse = &types.EVSE{ID: evse}
+	}); err != nil {
+		cs.log.ERROR.Printf("send TriggerMessage %s for %s failed: %v", requestedMessage, id, err)
+	}
+}
+
+// startTransactionRequest is a RequestStartTransactionRequest carrying the id token as object as defined by the specification
+type startTransactionRequest struct {
+	EvseID          *int                   `json:"evseId,omitempty" validate:"omitempty,gt=0"`
+	RemoteStartID   int                    `json:"remoteStartId" validate:"gte=0"`
+	IDToken         types.IdToken          `json:"idToken" validate:"required"`
+	ChargingProfile *types.ChargingProfile `json:"chargingProfile,omitempty" validate:"omitempty"`
+}
+
+func (r *startTransactionRequest) GetFeatureName() string {
+	return remotecontrol.RequestStartTransactionFeatureName
+}
+
+// StartTransaction requests the station to start a transaction for the given id token
+func (cs *CSMS) StartTransaction(id string, evse int, idToken string, profile *types.ChargingProfile, callback func(*remotecontrol.RequestStartTransactionResponse, error)) error {
+	cs.mu.Lock()
+	cs.remoteStartID++
+	request := &startTransactionRequest{
+		EvseID:        &evse,
+		RemoteStartID: cs.remoteStartID,
+		IDToken: types.IdToken{
+			IdToken: idToken,
+			Type:    types.IdTokenTypeCentral,
+		},
+		ChargingProfile: profile,
+	}
+	cs.mu.Unlock()
+
+	return cs.SendRequestAsync(id, request, func(response ocpp.Response, err error) {
+		res, _ := response.(*remotecontrol.RequestStartTransactionResponse)
+		callback(res, err)
+	})
+}
+
+// SendLocalListRequest replaces the station's local authorization list if enabled
+func (cs *CSMS) SendLocalListRequest(id string) {
+	list, ok := Auth().LocalList()
+	if !ok {
+		return
+	}
+
+	var data []localauth.AuthorizationData
+	for idToken, status := range list {
+		data = append(data, localauth.AuthorizationData{
+			IdToken:     types.IdToken{IdToken: idToken, Type: types.IdTokenTypeISO14443},
+			IdTokenInfo: &types.IdTokenInfo{Status: types.AuthorizationStatus(status)},
+		})
+	}
+
+	if err := cs.SendLocalList(id, func(request *localauth.SendLocalListResponse, err error) {
+		log := cs.log.TRACE
+		if err == nil && request != nil && request.Status != localauth.SendLocalListStatusAccepted {
+			log = cs.log.ERROR
+		}
+
+		var status localauth.SendLocalListStatus
+		if request != nil {
+			status = request.Status
+		}
+
+		log.Printf("SendLocalList for %s: %+v", id, status)
+	}, localListVersion(), localauth.UpdateTypeFull, func(request *localauth.SendLocalListRequest) {
+		request.LocalAuthorizationList = data
+	}); err != nil {
+		cs.log.ERROR.Printf("send SendLocalList for %s failed: %v", id, err)
+	}
+}
+
+// updateLocalLists sends the local authorization list to all connected stations
+func (cs *CSMS) updateLocalLists() {
+	cs.mu.Lock()
+	var ids []string
+	for id, station := range cs.stations {
+		if id != "" && station.connected() {
+			ids = append(ids, id)
+		}
+	}
+	cs.mu.Unlock()
+
+	for _, id := range ids {
+		cs.SendLocalListRequest(id)
+	}
+}
+
+// station actions
+
+func (cs *CSMS) OnAuthorize(id string, request *authorization.AuthorizeRequest) (*authorization.AuthorizeResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.Authorize(request)
+}
+
+func (cs *CSMS) OnBootNotification(id string, request *provisioning.BootNotificationRequest) (*provisioning.BootNotificationResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.BootNotification(request)
+}
+
+func (cs *CSMS) OnNotifyReport(id string, request *provisioning.NotifyReportRequest) (*provisioning.NotifyReportResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.NotifyReport(request)
+}
+
+func (cs *CSMS) OnHeartbeat(id string, request *availability.HeartbeatRequest) (*availability.HeartbeatResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.Heartbeat(request)
+}
+
+func (cs *CSMS) OnStatusNotification(id string, request *availability.StatusNotificationRequest) (*availability.StatusNotificationResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.StatusNotification(request)
+}
+
+func (cs *CSMS) OnMeterValues(id string, request *meter.MeterValuesRequest) (*meter.MeterValuesResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.MeterValues(request)
+}
+
+func (cs *CSMS) OnTransactionEvent(id string, request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+	station, err := cs.stationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return station.TransactionEvent(request)
+}
@@ -1,36 +1,79 @@
 package ocpp
 
 import (
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/util"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
-var instance *CS
+var (
+	once        sync.Once
+	instance    *CS
+	instance201 *CSMS
+)
 
+// Instance returns the OCPP 1.6 central system
 func Instance() *CS {
-	if instance == nil {
-		cs := ocpp16.NewCentralSystem(nil, nil)
+	once.Do(start)
+	return instance
+}
 
-		instance = &CS{
-			log:           util.NewLogger("ocpp"),
-			cps:           make(map[string]*CP),
-			CentralSystem: cs,
-		}
+// Instance201 returns the OCPP 2.0.1 central system
+func Instance201() *CSMS {
+	once.Do(start)
+	return instance201
+}
 
-		// ocppj.SetLogger(instance)
+// start runs the OCPP 1.6 and 2.0.1 central systems on a shared websocket server
+func start() {
+	log := util.NewLogger("ocpp")
+	mux := newMux(ws.NewServer())
+
+	cs := ocpp16.NewCentralSystem(nil, mux.Route(Protocol16))
+
+	instance = &CS{
+		log:           log,
+		cps:           make(map[string]*CP),
+		CentralSystem: cs,
+		mux:           mux,
+	}
 
-		cs.SetCoreHandler(instance)
-		cs.SetNewChargePointHandler(instance.NewChargePoint)
-		cs.SetChargePointDisconnectedHandler(instance.ChargePointDisconnected)
-		cs.SetFirmwareManagementHandler(instance)
+	// ocppj.SetLogger(instance)
 
-		go Instance().errorHandler(cs.Errors())
-		go cs.Start(8887, "/{ws}")
+	cs.SetCoreHandler(instance)
+	cs.SetNewChargePointHandler(instance.NewChargePoint)
+	cs.SetChargePointDisconnectedHandler(instance.ChargePointDisconnected)
+	cs.SetFirmwareManagementHandler(instance)
 
-		time.Sleep(time.Second)
+	csms := ocpp2.NewCSMS(nil, mux.Route(Protocol201))
+
+	instance201 = &CSMS{
+		log:      log,
+		stations: make(map[string]*Station),
+		CSMS:     csms,
 	}
 
-	return instance
+	csms.SetAuthorizationHandler(instance201)
+	csms.SetProvisioningHandler(instance201)
+	csms.SetAvailabilityHandler(instance201)
+	csms.SetMeterHandler(instance201)
+	csms.SetTransactionsHandler(instance201)
+	csms.SetNewChargingStationHandler(instance201.NewChargingStation)
+	csms.SetChargingStationDisconnectedHandler(instance201.ChargingStationDisconnected)
+
+	// update local authorization lists
+	Auth().Subscribe(instance.updateLocalLists)
+	Auth().Subscribe(instance201.updateLocalLists)
+
+	go instance.errorHandler(cs.Errors())
+	go instance201.errorHandler(csms.Errors())
+
+	go cs.Start(8887, "/{ws}")
+	go csms.Start(8887, "/{ws}")
+
+	time.Sleep(time.Second)
 }
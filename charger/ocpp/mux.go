@@ -0,0 +1,260 @@
+package ocpp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// websocket subprotocols of the supported OCPP versions
+const (
+	Protocol16  = "ocpp1.6"
+	Protocol201 = "ocpp2.0.1"
+)
+
+// mux shares a single websocket server between the OCPP 1.6 and 2.0.1 central systems.
+// Connections are dispatched to the central system of the subprotocol negotiated during handshake.
+type mux struct {
+	mu        sync.Mutex
+	server    ws.WsServer
+	started   int
+	routes    map[string]*route
+	pending   map[string]string // protocol negotiated during handshake by station id
+	protocols map[string]string // protocol of connected stations by station id
+	proxies   map[string]*proxy // OCPP 1.6 proxies by station id
+}
+
+func newMux(server ws.WsServer) *mux {
+	m := &mux{
+		server:    server,
+		routes:    make(map[string]*route),
+		pending:   make(map[string]string),
+		protocols: make(map[string]string),
+		proxies:   make(map[string]*proxy),
+	}
+
+	server.SetCheckOriginHandler(m.checkOrigin)
+	server.SetNewClientHandler(m.newClient)
+	server.SetDisconnectedClientHandler(m.disconnectedClient)
+	server.SetMessageHandler(m.message)
+
+	return m
+}
+
+// Route returns the websocket server for the given subprotocol
+func (m *mux) Route(protocol string) ws.WsServer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := &route{mux: m, protocol: protocol}
+	m.routes[protocol] = r
+	m.server.AddSupportedSubprotocol(protocol)
+
+	return r
+}
+
+// negotiate selects the first client subprotocol that has a route, same as the websocket server does
+func (m *mux) negotiate(r *http.Request) string {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if _, ok := m.routes[protocol]; ok {
+			return protocol
+		}
+	}
+
+	return ""
+}
+
+// checkOrigin performs the websocket server's default same origin check and records the negotiated
+// protocol of accepted handshakes. The protocol only applies once the station has connected.
+func (m *mux) checkOrigin(r *http.Request) bool {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return false
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := path.Base(r.URL.Path)
+	if _, connected := m.protocols[id]; !connected {
+		m.pending[id] = m.negotiate(r)
+	}
+
+	return true
+}
+
+// route returns the route of the station's negotiated protocol
+func (m *mux) route(id string) *route {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.routes[m.protocols[id]]
+}
+
+// proxy registers a proxy for the OCPP 1.6 station
+func (m *mux) proxy(id string, p *proxy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.proxies[id]; ok {
+		return fmt.Errorf("duplicate proxy: %s", id)
+	}
+
+	p.station = func(data []byte) error {
+		return m.server.Write(id, data)
+	}
+
+	p.local = func(ws ws.Channel, data []byte) error {
+		if r := m.route(id); r != nil && r.messageHandler != nil {
+			return r.messageHandler(ws, data)
+		}
+		return nil
+	}
+
+	m.proxies[id] = p
+
+	return nil
+}
+
+// proxyByID returns the station's proxy if the station uses OCPP 1.6
+func (m *mux) proxyByID(id string) *proxy {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.protocols[id] != Protocol16 {
+		return nil
+	}
+
+	return m.proxies[id]
+}
+
+func (m *mux) newClient(ws ws.Channel) {
+	m.mu.Lock()
+	if protocol, ok := m.pending[ws.ID()]; ok {
+		m.protocols[ws.ID()] = protocol
+		delete(m.pending, ws.ID())
+	}
+	m.mu.Unlock()
+
+	if p := m.proxyByID(ws.ID()); p != nil {
+		p.connect(ws)
+	}
+
+	if r := m.route(ws.ID()); r != nil && r.newClientHandler != nil {
+		r.newClientHandler(ws)
+	}
+}
+
+func (m *mux) disconnectedClient(ws ws.Channel) {
+	if p := m.proxyByID(ws.ID()); p != nil {
+		p.disconnect()
+	}
+
+	r := m.route(ws.ID())
+
+	m.mu.Lock()
+	delete(m.protocols, ws.ID())
+	m.mu.Unlock()
+
+	if r != nil && r.disconnectedClientHandler != nil {
+		r.disconnectedClientHandler(ws)
+	}
+}
+
+func (m *mux) message(ws ws.Channel, data []byte) error {
+	if p := m.proxyByID(ws.ID()); p != nil {
+		return p.stationMessage(ws, data)
+	}
+
+	if r := m.route(ws.ID()); r != nil && r.messageHandler != nil {
+		return r.messageHandler(ws, data)
+	}
+
+	return nil
+}
+
+// start starts the shared websocket server once all routes have been started and their handlers are in place
+func (m *mux) start(port int, listenPath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started++; m.started == len(m.routes) {
+		go m.server.Start(port, listenPath)
+	}
+}
+
+// route is the websocket server of a single subprotocol
+type route struct {
+	mux      *mux
+	protocol string
+
+	messageHandler            func(ws ws.Channel, data []byte) error
+	newClientHandler          func(ws ws.Channel)
+	disconnectedClientHandler func(ws ws.Channel)
+}
+
+var _ ws.WsServer = (*route)(nil)
+
+// Start starts the shared websocket server. Unlike ws.Server it does not block.
+func (r *route) Start(port int, listenPath string) {
+	r.mux.start(port, listenPath)
+}
+
+func (r *route) Stop() {
+	r.mux.server.Stop()
+}
+
+func (r *route) StopConnection(id string, closeError websocket.CloseError) error {
+	return r.mux.server.StopConnection(id, closeError)
+}
+
+func (r *route) Errors() <-chan error {
+	return r.mux.server.Errors()
+}
+
+func (r *route) SetMessageHandler(handler func(ws ws.Channel, data []byte) error) {
+	r.messageHandler = handler
+}
+
+func (r *route) SetNewClientHandler(handler func(ws ws.Channel)) {
+	r.newClientHandler = handler
+}
+
+func (r *route) SetDisconnectedClientHandler(handler func(ws ws.Channel)) {
+	r.disconnectedClientHandler = handler
+}
+
+func (r *route) SetTimeoutConfig(config ws.ServerTimeoutConfig) {
+	r.mux.server.SetTimeoutConfig(config)
+}
+
+func (r *route) Write(webSocketId string, data []byte) error {
+	if p := r.mux.proxyByID(webSocketId); p != nil && r.protocol == Protocol16 {
+		return p.localWrite(data)
+	}
+
+	return r.mux.server.Write(webSocketId, data)
+}
+
+func (r *route) AddSupportedSubprotocol(subProto string) {
+	r.mux.server.AddSupportedSubprotocol(subProto)
+}
+
+func (r *route) SetBasicAuthHandler(handler func(username string, password string) bool) {
+	r.mux.server.SetBasicAuthHandler(handler)
+}
+
+// SetCheckOriginHandler is not supported as the origin check is used for protocol negotiation
+func (r *route) SetCheckOriginHandler(handler func(r *http.Request) bool) {}
+
+func (r *route) Addr() *net.TCPAddr {
+	return r.mux.server.Addr()
+}
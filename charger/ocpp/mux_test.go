@@ -0,0 +1,87 @@
+package ocpp
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChannel string
+
+func (c testChannel) ID() string                               { return string(c) }
+func (c testChannel) RemoteAddr() net.Addr                     { return nil }
+func (c testChannel) TLSConnectionState() *tls.ConnectionState { return nil }
+
+func TestMuxNegotiation(t *testing.T) {
+	m := newMux(ws.NewServer())
+	m.Route(Protocol16)
+	m.Route(Protocol201)
+
+	tc := []struct {
+		id, protocols, expected string
+	}{
+		{"cp1", "ocpp1.6", Protocol16},
+		{"cs1", "ocpp2.0.1, ocpp1.6", Protocol201},
+		{"cs2", "ocpp1.5, ocpp2.0.1", Protocol201},
+		{"unknown", "ocpp1.5", ""},
+	}
+
+	for _, tc := range tc {
+		r := httptest.NewRequest("GET", "/"+tc.id, nil)
+		r.Header.Set("Sec-Websocket-Protocol", tc.protocols)
+
+		assert.True(t, m.checkOrigin(r), tc.id)
+		m.newClient(testChannel(tc.id))
+
+		if tc.expected == "" {
+			assert.Nil(t, m.route(tc.id), tc.id)
+		} else {
+			assert.Equal(t, tc.expected, m.route(tc.id).protocol, tc.id)
+		}
+	}
+
+	// reconnect attempts while connected don't change the protocol
+	r := httptest.NewRequest("GET", "/cp1", nil)
+	r.Header.Set("Sec-Websocket-Protocol", "ocpp2.0.1")
+	m.checkOrigin(r)
+	assert.Equal(t, Protocol16, m.route("cp1").protocol)
+}
+
+func TestMuxFailedHandshake(t *testing.T) {
+	m := newMux(ws.NewServer())
+	m.Route(Protocol16)
+	m.Route(Protocol201)
+
+	// rejected origin
+	r := httptest.NewRequest("GET", "http://evcc:8887/cp1", nil)
+	r.Header.Set("Origin", "http://foo")
+	r.Header.Set("Sec-Websocket-Protocol", "ocpp2.0.1")
+	assert.False(t, m.checkOrigin(r))
+	assert.Nil(t, m.route("cp1"))
+
+	// handshake failing after origin check
+	r.Header.Del("Origin")
+	assert.True(t, m.checkOrigin(r))
+	assert.Nil(t, m.route("cp1"))
+
+	// next connection negotiates again
+	r.Header.Set("Sec-Websocket-Protocol", "ocpp1.6")
+	assert.True(t, m.checkOrigin(r))
+	m.newClient(testChannel("cp1"))
+	assert.Equal(t, Protocol16, m.route("cp1").protocol)
+}
+
+func TestMuxCheckOrigin(t *testing.T) {
+	m := newMux(ws.NewServer())
+
+	r := httptest.NewRequest("GET", "http://evcc:8887/cp1", nil)
+	r.Header.Set("Origin", "http://evcc:8887")
+	assert.True(t, m.checkOrigin(r))
+
+	r.Header.Set("Origin", "http://foo")
+	assert.False(t, m.checkOrigin(r))
+}
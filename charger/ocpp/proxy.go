@@ -0,0 +1,411 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/gorilla/websocket"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// ProxyConfig configures forwarding a charge point's messages to an upstream central system
+type ProxyConfig struct {
+	URI        string // upstream central system, the station id is appended
+	Precedence string // charging profile precedence on conflicts, evcc or upstream
+}
+
+// charging profile precedence
+const (
+	PrecedenceEvcc     = "evcc"     // upstream charging profiles are acknowledged but not forwarded
+	PrecedenceUpstream = "upstream" // evcc charging profiles are rejected while an upstream profile is active
+)
+
+// OCPP-J message types
+const (
+	callType       = 2
+	callResultType = 3
+	callErrorType  = 4
+)
+
+const proxyReconnect = 10 * time.Second
+
+// message is a raw OCPP-J message
+type message struct {
+	typ     int
+	id      string
+	action  string // calls only
+	payload json.RawMessage
+}
+
+func parseMessage(data []byte) (message, error) {
+	var (
+		res    message
+		fields []json.RawMessage
+	)
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return res, err
+	}
+
+	if len(fields) < 3 {
+		return res, fmt.Errorf("invalid message: %s", data)
+	}
+
+	if err := json.Unmarshal(fields[0], &res.typ); err != nil {
+		return res, err
+	}
+
+	if err := json.Unmarshal(fields[1], &res.id); err != nil {
+		return res, err
+	}
+
+	switch res.typ {
+	case callType:
+		if len(fields) < 4 {
+			return res, fmt.Errorf("invalid call: %s", data)
+		}
+		if err := json.Unmarshal(fields[2], &res.action); err != nil {
+			return res, err
+		}
+		res.payload = fields[3]
+	case callResultType:
+		res.payload = fields[2]
+	case callErrorType:
+	default:
+		return res, fmt.Errorf("invalid message type: %d", res.typ)
+	}
+
+	return res, nil
+}
+
+// status returns the status field of a call result payload
+func (m message) status() string {
+	var res struct{ Status string }
+	_ = json.Unmarshal(m.payload, &res)
+	return res.Status
+}
+
+// statusResult creates a call result with the given status
+func statusResult(id, status string) []byte {
+	b, _ := json.Marshal([]any{callResultType, id, map[string]string{"status": status}})
+	return b
+}
+
+// upstreamConn is the upstream websocket connection
+type upstreamConn interface {
+	ReadMessage() (int, []byte, error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// stationCall is a station call answered by the upstream central system
+type stationCall struct {
+	action          string
+	local, upstream bool // answered by evcc and upstream
+	txnId           int  // upstream transaction id
+}
+
+// proxy forwards a charge point's messages to an upstream central system.
+// Station calls are answered by upstream while evcc observes them. Calls from
+// evcc and upstream are both sent to the station and their results are routed back.
+// If upstream is not connected, evcc answers the station's calls.
+type proxy struct {
+	mu  sync.Mutex
+	log *util.Logger
+	id  string
+	ProxyConfig
+
+	channel ws.Channel
+	conn    upstreamConn
+	done    chan struct{}
+
+	dial    func() (upstreamConn, error)
+	station func(data []byte) error                // write to the station
+	local   func(ws ws.Channel, data []byte) error // deliver to evcc's central system
+	txn     func(id int)                           // upstream transaction id
+
+	stationCalls    map[string]*stationCall
+	localCalls      map[string]bool
+	upstreamCalls   map[string]string
+	upstreamProfile bool // upstream charging profile active
+}
+
+func newProxy(log *util.Logger, id string, conf ProxyConfig) *proxy {
+	p := &proxy{
+		log:           log,
+		id:            id,
+		ProxyConfig:   conf,
+		stationCalls:  make(map[string]*stationCall),
+		localCalls:    make(map[string]bool),
+		upstreamCalls: make(map[string]string),
+	}
+
+	p.dial = p.dialUpstream
+
+	return p
+}
+
+func (p *proxy) dialUpstream() (upstreamConn, error) {
+	dialer := websocket.Dialer{
+		Subprotocols:     []string{Protocol16},
+		HandshakeTimeout: request.Timeout,
+	}
+
+	conn, _, err := dialer.Dial(strings.TrimSuffix(p.URI, "/")+"/"+url.PathEscape(p.id), nil)
+	return conn, err
+}
+
+// connect starts forwarding the station's messages
+func (p *proxy) connect(channel ws.Channel) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.channel = channel
+	p.done = make(chan struct{})
+
+	go p.run(p.done)
+}
+
+// disconnect closes the upstream connection
+func (p *proxy) disconnect() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
+
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+
+	p.stationCalls = make(map[string]*stationCall)
+	p.localCalls = make(map[string]bool)
+	p.upstreamCalls = make(map[string]string)
+}
+
+// run maintains the upstream connection until the station disconnects
+func (p *proxy) run(done chan struct{}) {
+	for {
+		conn, err := p.dial()
+		if err == nil {
+			p.mu.Lock()
+			select {
+			case <-done:
+				p.mu.Unlock()
+				conn.Close()
+				return
+			default:
+				p.conn = conn
+			}
+			p.mu.Unlock()
+
+			p.log.DEBUG.Printf("upstream connected: %s", p.URI)
+			err = p.read(conn)
+
+			p.mu.Lock()
+			if p.conn == conn {
+				p.conn = nil
+			}
+			p.mu.Unlock()
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+			p.log.ERROR.Printf("upstream: %v", err)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-time.After(proxyReconnect):
+		}
+	}
+}
+
+func (p *proxy) read(conn upstreamConn) error {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		if err := p.upstreamMessage(data); err != nil {
+			p.log.ERROR.Printf("upstream: %v", err)
+		}
+	}
+}
+
+// upstream writes to the upstream central system. Must be called with lock held.
+func (p *proxy) upstream(data []byte) error {
+	if p.conn == nil {
+		return errors.New("upstream not connected")
+	}
+
+	return p.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// stationMessage handles messages received from the station
+func (p *proxy) stationMessage(channel ws.Channel, data []byte) error {
+	msg, err := parseMessage(data)
+	if err != nil {
+		return p.local(channel, data)
+	}
+
+	p.mu.Lock()
+
+	switch msg.typ {
+	case callType:
+		// upstream answers the call if connected, evcc otherwise
+		if err := p.upstream(data); err == nil {
+			p.stationCalls[msg.id] = &stationCall{action: msg.action}
+		} else {
+			p.log.TRACE.Printf("upstream: %v", err)
+		}
+
+		// transaction profiles end with the transaction
+		if msg.action == core.StopTransactionFeatureName {
+			p.upstreamProfile = false
+		}
+
+	default:
+		if p.localCalls[msg.id] {
+			delete(p.localCalls, msg.id)
+			break
+		}
+
+		action, ok := p.upstreamCalls[msg.id]
+		delete(p.upstreamCalls, msg.id)
+
+		if ok && msg.typ == callResultType && msg.status() == string(smartcharging.ChargingProfileStatusAccepted) {
+			switch action {
+			case smartcharging.SetChargingProfileFeatureName:
+				p.upstreamProfile = true
+			case smartcharging.ClearChargingProfileFeatureName:
+				p.upstreamProfile = false
+			}
+		}
+
+		defer p.mu.Unlock()
+		return p.upstream(data)
+	}
+
+	p.mu.Unlock()
+
+	return p.local(channel, data)
+}
+
+// localWrite handles messages sent by evcc's central system to the station
+func (p *proxy) localWrite(data []byte) error {
+	msg, err := parseMessage(data)
+	if err != nil {
+		return p.station(data)
+	}
+
+	var txnId int
+
+	p.mu.Lock()
+
+	switch msg.typ {
+	case callType:
+		if msg.action == smartcharging.SetChargingProfileFeatureName && p.Precedence == PrecedenceUpstream && p.upstreamProfile {
+			channel := p.channel
+			p.mu.Unlock()
+
+			p.log.DEBUG.Printf("upstream precedence: rejecting %s", msg.action)
+			go func() {
+				_ = p.local(channel, statusResult(msg.id, string(smartcharging.ChargingProfileStatusRejected)))
+			}()
+
+			return nil
+		}
+
+		p.localCalls[msg.id] = true
+
+	default:
+		// station calls forwarded upstream are answered by upstream
+		if call, ok := p.stationCalls[msg.id]; ok {
+			call.local = true
+			if call.upstream {
+				delete(p.stationCalls, msg.id)
+				txnId = call.txnId
+			}
+
+			p.mu.Unlock()
+			p.transaction(txnId)
+
+			return nil
+		}
+	}
+
+	p.mu.Unlock()
+
+	return p.station(data)
+}
+
+// upstreamMessage handles messages received from the upstream central system
+func (p *proxy) upstreamMessage(data []byte) error {
+	msg, err := parseMessage(data)
+	if err != nil {
+		return err
+	}
+
+	var txnId int
+
+	p.mu.Lock()
+
+	switch msg.typ {
+	case callType:
+		if p.Precedence == PrecedenceEvcc && (msg.action == smartcharging.SetChargingProfileFeatureName || msg.action == smartcharging.ClearChargingProfileFeatureName) {
+			p.log.DEBUG.Printf("evcc precedence: ignoring upstream %s", msg.action)
+
+			defer p.mu.Unlock()
+			return p.upstream(statusResult(msg.id, string(smartcharging.ChargingProfileStatusAccepted)))
+		}
+
+		p.upstreamCalls[msg.id] = msg.action
+
+	default:
+		if call, ok := p.stationCalls[msg.id]; ok {
+			call.upstream = true
+
+			// evcc needs the upstream transaction id for controlling the transaction
+			if call.action == core.StartTransactionFeatureName && msg.typ == callResultType {
+				var res core.StartTransactionConfirmation
+				if err := json.Unmarshal(msg.payload, &res); err == nil {
+					call.txnId = res.TransactionId
+				}
+			}
+
+			// apply transaction id once evcc has handled the call, too
+			if call.local {
+				delete(p.stationCalls, msg.id)
+				txnId = call.txnId
+			}
+		}
+	}
+
+	p.mu.Unlock()
+	p.transaction(txnId)
+
+	return p.station(data)
+}
+
+func (p *proxy) transaction(id int) {
+	if id != 0 && p.txn != nil {
+		p.txn(id)
+	}
+}
@@ -0,0 +1,141 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	written []string
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) { select {} }
+func (c *fakeConn) Close() error                      { return nil }
+
+func (c *fakeConn) WriteMessage(_ int, data []byte) error {
+	c.written = append(c.written, string(data))
+	return nil
+}
+
+type proxyRecorder struct {
+	station, local []string
+	txn            int
+}
+
+func newTestProxy(precedence string) (*proxy, *fakeConn, *proxyRecorder) {
+	conn := new(fakeConn)
+	rec := new(proxyRecorder)
+
+	p := newProxy(util.NewLogger("foo"), "cp1", ProxyConfig{Precedence: precedence})
+	p.conn = conn
+	p.station = func(data []byte) error {
+		rec.station = append(rec.station, string(data))
+		return nil
+	}
+	p.local = func(_ ws.Channel, data []byte) error {
+		rec.local = append(rec.local, string(data))
+		return nil
+	}
+	p.txn = func(id int) { rec.txn = id }
+
+	return p, conn, rec
+}
+
+func TestProxyStationCalls(t *testing.T) {
+	p, conn, rec := newTestProxy(PrecedenceEvcc)
+
+	// station call is forwarded upstream and observed by evcc
+	call := `[2,"1","StartTransaction",{"connectorId":1,"idTag":"abc","meterStart":0,"timestamp":"2022-01-01T00:00:00Z"}]`
+	require.NoError(t, p.stationMessage(nil, []byte(call)))
+	assert.Equal(t, []string{call}, conn.written)
+	assert.Equal(t, []string{call}, rec.local)
+
+	// evcc's answer is dropped
+	require.NoError(t, p.localWrite([]byte(`[3,"1",{"transactionId":1}]`)))
+	assert.Empty(t, rec.station)
+
+	// upstream's answer is forwarded and its transaction id applied
+	res := `[3,"1",{"idTagInfo":{"status":"Accepted"},"transactionId":4711}]`
+	require.NoError(t, p.upstreamMessage([]byte(res)))
+	assert.Equal(t, []string{res}, rec.station)
+	assert.Equal(t, 4711, rec.txn)
+	assert.Empty(t, p.stationCalls)
+
+	// evcc answers if upstream is not connected
+	p.conn = nil
+	require.NoError(t, p.stationMessage(nil, []byte(`[2,"2","Heartbeat",{}]`)))
+	require.NoError(t, p.localWrite([]byte(`[3,"2",{}]`)))
+	assert.Equal(t, `[3,"2",{}]`, rec.station[1])
+}
+
+func TestProxyLocalCalls(t *testing.T) {
+	p, conn, rec := newTestProxy(PrecedenceEvcc)
+
+	// evcc's call is sent to the station and its answer returned to evcc
+	call := `[2,"a","RemoteStartTransaction",{"idTag":"evcc"}]`
+	require.NoError(t, p.localWrite([]byte(call)))
+	assert.Equal(t, []string{call}, rec.station)
+
+	require.NoError(t, p.stationMessage(nil, []byte(`[3,"a",{"status":"Accepted"}]`)))
+	assert.Equal(t, []string{`[3,"a",{"status":"Accepted"}]`}, rec.local)
+	assert.Empty(t, conn.written)
+}
+
+func TestProxyPrecedenceEvcc(t *testing.T) {
+	p, conn, rec := newTestProxy(PrecedenceEvcc)
+
+	// upstream profiles are acknowledged but not forwarded
+	require.NoError(t, p.upstreamMessage([]byte(`[2,"u1","SetChargingProfile",{"connectorId":1}]`)))
+	assert.Empty(t, rec.station)
+	assert.Equal(t, []string{`[3,"u1",{"status":"Accepted"}]`}, conn.written)
+
+	// other upstream calls are forwarded and answered by the station
+	call := `[2,"u2","RemoteStopTransaction",{"transactionId":1}]`
+	require.NoError(t, p.upstreamMessage([]byte(call)))
+	assert.Equal(t, []string{call}, rec.station)
+
+	require.NoError(t, p.stationMessage(nil, []byte(`[3,"u2",{"status":"Accepted"}]`)))
+	assert.Equal(t, `[3,"u2",{"status":"Accepted"}]`, conn.written[1])
+	assert.Empty(t, rec.local)
+}
+
+func TestProxyPrecedenceUpstream(t *testing.T) {
+	p, _, rec := newTestProxy(PrecedenceUpstream)
+
+	localC := make(chan string, 1)
+	p.local = func(_ ws.Channel, data []byte) error {
+		localC <- string(data)
+		return nil
+	}
+
+	profile := `[2,"e1","SetChargingProfile",{"connectorId":1}]`
+
+	// evcc profiles are sent while no upstream profile is active
+	require.NoError(t, p.localWrite([]byte(profile)))
+	assert.Equal(t, []string{profile}, rec.station)
+
+	// upstream profile accepted by the station
+	require.NoError(t, p.upstreamMessage([]byte(`[2,"u1","SetChargingProfile",{"connectorId":1}]`)))
+	require.NoError(t, p.stationMessage(nil, []byte(`[3,"u1",{"status":"Accepted"}]`)))
+	assert.True(t, p.upstreamProfile)
+
+	// evcc profiles are rejected
+	require.NoError(t, p.localWrite([]byte(`[2,"e2","SetChargingProfile",{"connectorId":1}]`)))
+	assert.Len(t, rec.station, 2)
+
+	select {
+	case res := <-localC:
+		assert.Equal(t, `[3,"e2",{"status":"Rejected"}]`, res)
+	case <-time.After(time.Second):
+		t.Error("missing result")
+	}
+
+	// upstream profile ends with the transaction
+	require.NoError(t, p.stationMessage(nil, []byte(`[2,"s1","StopTransaction",{"meterStop":0,"timestamp":"2022-01-01T00:00:00Z","transactionId":1}]`)))
+	assert.False(t, p.upstreamProfile)
+}
@@ -0,0 +1,304 @@
+package ocpp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+const (
+	// Device model components
+	ComponentSampledDataCtrlr   = "SampledDataCtrlr"
+	ComponentSmartChargingCtrlr = "SmartChargingCtrlr"
+
+	// Device model variables
+	VariableTxUpdatedMeasurands = "TxUpdatedMeasurands"
+	VariableTxUpdatedInterval   = "TxUpdatedInterval"
+	VariablePhases3to1          = "Phases3to1"
+)
+
+// Station is an OCPP 2.0.1 charging station's EVSE
+type Station struct {
+	mu   sync.Mutex
+	log  *util.Logger
+	once sync.Once
+
+	id   string
+	evse int
+
+	connectC, statusC chan struct{}
+	updated           time.Time
+	status            *availability.StatusNotificationRequest
+
+	timeout      time.Duration
+	meterUpdated time.Time
+	measurements map[string]types.SampledValue
+
+	txnId         string
+	chargingState transactions.ChargingState
+	idTag         string // authorized id token
+}
+
+func NewStation(log *util.Logger, id string, evse int, timeout time.Duration) *Station {
+	return &Station{
+		log:          log,
+		id:           id,
+		evse:         evse,
+		connectC:     make(chan struct{}),
+		statusC:      make(chan struct{}),
+		measurements: make(map[string]types.SampledValue),
+		timeout:      timeout,
+	}
+}
+
+func (s *Station) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.id
+}
+
+func (s *Station) RegisterID(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.id != "" {
+		panic("ocpp: cannot re-register id")
+	}
+
+	s.id = id
+}
+
+func (s *Station) Connect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.once.Do(func() {
+		close(s.connectC)
+	})
+}
+
+func (s *Station) HasConnected() <-chan struct{} {
+	return s.connectC
+}
+
+// connected returns true if the station has connected
+func (s *Station) connected() bool {
+	select {
+	case <-s.connectC:
+		return true
+	default:
+		return false
+	}
+}
+
+// EVSE returns the station's EVSE id
+func (s *Station) EVSE() int {
+	return s.evse
+}
+
+func (s *Station) Initialized(timeout time.Duration) bool {
+	s.log.DEBUG.Printf("waiting for station status: %v", timeout)
+
+	// trigger status
+	time.AfterFunc(5*time.Second, func() {
+		select {
+		case <-s.statusC:
+			return
+		default:
+			Instance201().TriggerMessageRequest(s.ID(), s.evse, remotecontrol.MessageTriggerStatusNotification)
+		}
+	})
+
+	// wait for status
+	select {
+	case <-s.statusC:
+		s.update()
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// WatchDog triggers meter values messages if older than timeout.
+// Must be wrapped in a goroutine.
+func (s *Station) WatchDog(timeout time.Duration) {
+	for ; true; <-time.NewTicker(timeout).C {
+		s.mu.Lock()
+		update := s.txnId != "" && time.Since(s.meterUpdated) > timeout
+		s.mu.Unlock()
+
+		if update {
+			Instance201().TriggerMessageRequest(s.ID(), s.evse, remotecontrol.MessageTriggerMeterValues)
+		}
+	}
+}
+
+// TransactionID returns the current transaction id
+func (s *Station) TransactionID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.txnId
+}
+
+// IdTag returns the id token of the current transaction or the last authorization
+func (s *Station) IdTag() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.idTag
+}
+
+// Enabled returns true if the EVSE offers energy within a transaction
+func (s *Station) Enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.txnId != "" && (s.chargingState == transactions.ChargingStateCharging || s.chargingState == transactions.ChargingStateSuspendedEV)
+}
+
+func (s *Station) Status() (api.ChargeStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := api.StatusNone
+
+	if time.Since(s.updated) > s.timeout {
+		return res, api.ErrTimeout
+	}
+
+	switch s.status.ConnectorStatus {
+	case availability.ConnectorStatusAvailable, // "Available"
+		availability.ConnectorStatusUnavailable: // "Unavailable"
+		res = api.StatusA
+	case availability.ConnectorStatusOccupied: // "Occupied"
+		res = api.StatusB
+		if s.chargingState == transactions.ChargingStateCharging {
+			res = api.StatusC
+		}
+	case availability.ConnectorStatusReserved, // "Reserved"
+		availability.ConnectorStatusFaulted: // "Faulted"
+		return api.StatusF, fmt.Errorf("station status: %s", s.status.ConnectorStatus)
+	default:
+		return api.StatusNone, fmt.Errorf("invalid station status: %s", s.status.ConnectorStatus)
+	}
+
+	return res, nil
+}
+
+// measurement returns the scaled value of the given measurand
+func (s *Station) measurement(key string) (float64, bool) {
+	m, ok := s.measurements[key]
+	if !ok {
+		return 0, false
+	}
+
+	return sampledValue(m), true
+}
+
+var _ api.Meter = (*Station)(nil)
+
+func (s *Station) CurrentPower() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timeout > 0 && time.Since(s.meterUpdated) > s.timeout {
+		return 0, api.ErrNotAvailable
+	}
+
+	power, ok := s.measurement(string(types.MeasurandPowerActiveImport))
+	if !ok {
+		return 0, api.ErrNotAvailable
+	}
+
+	// bidirectional stations report discharging as export
+	if export, ok := s.measurement(string(types.MeasurandPowerActiveExport)); ok {
+		power -= export
+	}
+
+	return power, nil
+}
+
+var _ api.MeterEnergy = (*Station)(nil)
+
+func (s *Station) TotalEnergy() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timeout > 0 && time.Since(s.meterUpdated) > s.timeout {
+		return 0, api.ErrNotAvailable
+	}
+
+	if energy, ok := s.measurement(string(types.MeasurandEnergyActiveImportRegister)); ok {
+		return energy / 1e3, nil
+	}
+
+	return 0, api.ErrNotAvailable
+}
+
+var _ api.MeterCurrent = (*Station)(nil)
+
+func (s *Station) Currents() (float64, float64, float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timeout > 0 && time.Since(s.meterUpdated) > s.timeout {
+		return 0, 0, 0, api.ErrNotAvailable
+	}
+
+	currents := make([]float64, 0, 3)
+
+	for phase := 1; phase <= 3; phase++ {
+		current, ok := s.measurement(string(types.MeasurandCurrentImport) + "@L" + strconv.Itoa(phase))
+		if !ok {
+			return 0, 0, 0, api.ErrNotAvailable
+		}
+
+		currents = append(currents, current)
+	}
+
+	return currents[0], currents[1], currents[2], nil
+}
+
+// sampledValue applies the sampled value's unit multiplier and prefix
+func sampledValue(s types.SampledValue) float64 {
+	f := s.Value
+
+	if unit := s.UnitOfMeasure; unit != nil {
+		if unit.Multiplier != nil {
+			f *= math.Pow10(*unit.Multiplier)
+		}
+
+		switch {
+		case strings.HasPrefix(unit.Unit, "k"):
+			f *= 1e3
+		case strings.HasPrefix(unit.Unit, "m"):
+			f /= 1e3
+		}
+	}
+
+	return f
+}
+
+func getSampleKey201(s types.SampledValue) string {
+	measurand := s.Measurand
+	if measurand == "" {
+		// default measurand
+		measurand = types.MeasurandEnergyActiveImportRegister
+	}
+
+	if s.Phase != "" {
+		return string(measurand) + "@" + string(s.Phase)
+	}
+
+	return string(measurand)
+}
@@ -0,0 +1,200 @@
+package ocpp
+
+import (
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// authorize checks the id token and remembers it if accepted
+func (s *Station) authorize(idToken string) types.AuthorizationStatus {
+	status := types.AuthorizationStatus(Auth().Status(idToken))
+
+	if status == types.AuthorizationStatusAccepted {
+		s.mu.Lock()
+		s.idTag = idToken
+		s.mu.Unlock()
+	} else {
+		s.log.WARN.Printf("authorize: %s %s", idToken, status)
+	}
+
+	return status
+}
+
+// acceptsEVSE returns false for messages of other EVSEs of the same station
+func (s *Station) acceptsEVSE(evse int) bool {
+	return evse == 0 || evse == s.evse
+}
+
+func (s *Station) Authorize(request *authorization.AuthorizeRequest) (*authorization.AuthorizeResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	status := s.authorize(request.IdToken.IdToken)
+
+	res := &authorization.AuthorizeResponse{
+		IdTokenInfo: types.IdTokenInfo{
+			Status: status,
+		},
+	}
+
+	return res, nil
+}
+
+func (s *Station) BootNotification(request *provisioning.BootNotificationRequest) (*provisioning.BootNotificationResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	res := &provisioning.BootNotificationResponse{
+		CurrentTime: types.NewDateTime(time.Now()),
+		Interval:    60, // TODO
+		Status:      provisioning.RegistrationStatusAccepted,
+	}
+
+	return res, nil
+}
+
+func (s *Station) NotifyReport(request *provisioning.NotifyReportRequest) (*provisioning.NotifyReportResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	return new(provisioning.NotifyReportResponse), nil
+}
+
+func (s *Station) StatusNotification(request *availability.StatusNotificationRequest) (*availability.StatusNotificationResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	if request != nil && request.EvseID == s.evse {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.status == nil {
+			s.status = request
+			close(s.statusC) // signal initial status received
+		} else if request.Timestamp == nil || s.timestampValid(request.Timestamp.Time) {
+			s.status = request
+		} else {
+			s.log.TRACE.Printf("ignoring status: %s < %s", request.Timestamp.Time, s.status.Timestamp)
+		}
+	}
+
+	return new(availability.StatusNotificationResponse), nil
+}
+
+// timestampValid returns false if status timestamps are outdated
+func (s *Station) timestampValid(t time.Time) bool {
+	// reject if expired
+	if time.Since(t) > messageExpiry {
+		return false
+	}
+
+	// assume having a timestamp is better than not
+	if s.status.Timestamp == nil {
+		return true
+	}
+
+	// reject older values than we already have
+	return !t.Before(s.status.Timestamp.Time)
+}
+
+func (s *Station) update() {
+	s.mu.Lock()
+	s.updated = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *Station) Heartbeat(request *availability.HeartbeatRequest) (*availability.HeartbeatResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	s.update()
+	res := &availability.HeartbeatResponse{
+		CurrentTime: *types.NewDateTime(time.Now()),
+	}
+
+	return res, nil
+}
+
+// meterValues stores sampled values newer than the last update. Must be called with lock held.
+func (s *Station) meterValues(values []types.MeterValue) {
+	for _, meterValue := range values {
+		// ignore old meter value requests
+		if meterValue.Timestamp.Time.After(s.meterUpdated) {
+			for _, sample := range meterValue.SampledValue {
+				s.measurements[getSampleKey201(sample)] = sample
+				s.meterUpdated = time.Now()
+			}
+		}
+	}
+}
+
+func (s *Station) MeterValues(request *meter.MeterValuesRequest) (*meter.MeterValuesResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	if request != nil && request.EvseID == s.evse {
+		s.mu.Lock()
+		s.meterValues(request.MeterValue)
+		s.mu.Unlock()
+	}
+
+	return new(meter.MeterValuesResponse), nil
+}
+
+func (s *Station) TransactionEvent(request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+	s.log.TRACE.Printf("%T: %+v", request, request)
+
+	res := new(transactions.TransactionEventResponse)
+	if request == nil {
+		return res, nil
+	}
+
+	// id token info is required when the request contains an id token
+	if request.IDToken != nil {
+		res.IDTokenInfo = &types.IdTokenInfo{
+			Status: types.AuthorizationStatus(Auth().Status(request.IDToken.IdToken)),
+		}
+	}
+
+	if request.Evse != nil && !s.acceptsEVSE(request.Evse.ID) {
+		return res, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// only respect transactions in the last hour
+	if request.Timestamp != nil && time.Since(request.Timestamp.Time) > transactionExpiry {
+		return res, nil
+	}
+
+	switch request.EventType {
+	case transactions.TransactionEventStarted, transactions.TransactionEventUpdated:
+		s.txnId = request.TransactionInfo.TransactionID
+		if request.TransactionInfo.ChargingState != "" {
+			s.chargingState = request.TransactionInfo.ChargingState
+		}
+
+		if res.IDTokenInfo != nil {
+			if res.IDTokenInfo.Status == types.AuthorizationStatusAccepted {
+				s.idTag = request.IDToken.IdToken
+			} else {
+				s.log.WARN.Printf("transaction: %s %s", request.IDToken.IdToken, res.IDTokenInfo.Status)
+			}
+		}
+
+	case transactions.TransactionEventEnded:
+		// log mismatching id but close transaction anyway
+		if id := request.TransactionInfo.TransactionID; id != s.txnId {
+			s.log.ERROR.Printf("transaction ended: invalid id %s", id)
+		}
+
+		s.txnId = ""
+		s.chargingState = ""
+		s.idTag = ""
+	}
+
+	s.meterValues(request.MeterValue)
+
+	return res, nil
+}
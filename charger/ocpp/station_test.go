@@ -0,0 +1,92 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStationTransaction(t *testing.T) {
+	s := NewStation(util.NewLogger("foo"), "cs1", 1, time.Minute)
+	s.update()
+
+	status := func(status availability.ConnectorStatus) {
+		_, err := s.StatusNotification(&availability.StatusNotificationRequest{
+			Timestamp:       types.NewDateTime(time.Now()),
+			ConnectorStatus: status,
+			EvseID:          1,
+			ConnectorID:     1,
+		})
+		require.NoError(t, err)
+	}
+
+	event := func(typ transactions.TransactionEvent, state transactions.ChargingState, values ...types.SampledValue) {
+		_, err := s.TransactionEvent(&transactions.TransactionEventRequest{
+			EventType: typ,
+			Timestamp: types.NewDateTime(time.Now()),
+			TransactionInfo: transactions.Transaction{
+				TransactionID: "txn",
+				ChargingState: state,
+			},
+			Evse: &types.EVSE{ID: 1},
+			MeterValue: []types.MeterValue{
+				{Timestamp: *types.NewDateTime(time.Now()), SampledValue: values},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	status(availability.ConnectorStatusAvailable)
+	res, err := s.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusA, res)
+
+	// plugged in, transaction started but suspended
+	status(availability.ConnectorStatusOccupied)
+	event(transactions.TransactionEventStarted, transactions.ChargingStateSuspendedEVSE)
+
+	res, err = s.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusB, res)
+	assert.False(t, s.Enabled())
+
+	// charging
+	event(transactions.TransactionEventUpdated, transactions.ChargingStateCharging, types.SampledValue{
+		Value:         3.7,
+		Measurand:     types.MeasurandPowerActiveImport,
+		UnitOfMeasure: &types.UnitOfMeasure{Unit: "kW"},
+	})
+
+	res, err = s.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, res)
+	assert.True(t, s.Enabled())
+
+	power, err := s.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 3700.0, power)
+
+	// other evse is ignored
+	_, err = s.StatusNotification(&availability.StatusNotificationRequest{
+		Timestamp:       types.NewDateTime(time.Now()),
+		ConnectorStatus: availability.ConnectorStatusFaulted,
+		EvseID:          2,
+	})
+	require.NoError(t, err)
+
+	// transaction ended
+	event(transactions.TransactionEventEnded, transactions.ChargingStateEVConnected)
+
+	res, err = s.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusB, res)
+	assert.False(t, s.Enabled())
+	assert.Empty(t, s.TransactionID())
+}
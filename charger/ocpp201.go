@@ -0,0 +1,390 @@
+package charger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/samber/lo"
+)
+
+// OCPP201 charger implementation
+type OCPP201 struct {
+	log            *util.Logger
+	station        *ocpp.Station
+	idtag          string
+	phases         int
+	current        float64
+	measurands     string
+	timeout        time.Duration
+	phaseSwitching bool
+}
+
+func init() {
+	registry.Add("ocpp201", NewOCPP201FromConfig)
+}
+
+// NewOCPP201FromConfig creates a OCPP 2.0.1 charger from generic config
+func NewOCPP201FromConfig(other map[string]interface{}) (api.Charger, error) {
+	cc := struct {
+		StationId     string
+		IdTag         string
+		Evse          int
+		MeterInterval time.Duration
+		MeterValues   string
+		Timeout       time.Duration
+	}{
+		Evse:    1,
+		IdTag:   defaultIdTag,
+		Timeout: time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	c, err := NewOCPP201(cc.StationId, cc.Evse, cc.IdTag, cc.MeterValues, cc.MeterInterval, cc.Timeout)
+	if err != nil {
+		return c, err
+	}
+
+	var powerG func() (float64, error)
+	if c.hasMeasurement(types.MeasurandPowerActiveImport) {
+		powerG = c.currentPower
+	}
+
+	var totalEnergyG func() (float64, error)
+	if c.hasMeasurement(types.MeasurandEnergyActiveImportRegister) {
+		totalEnergyG = c.totalEnergy
+	}
+
+	var currentsG func() (float64, float64, float64, error)
+	if c.hasMeasurement(types.MeasurandCurrentImport) {
+		currentsG = c.currents
+	}
+
+	var phasesS func(int) error
+	if c.phaseSwitching {
+		phasesS = c.phases1p3p
+	}
+
+	return decorateOCPP201(c, powerG, totalEnergyG, currentsG, phasesS), nil
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateOCPP201 -b *OCPP201 -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(phases int) error"
+
+// NewOCPP201 creates OCPP 2.0.1 charger
+func NewOCPP201(id string, evse int, idtag string, meterValues string, meterInterval time.Duration, timeout time.Duration) (*OCPP201, error) {
+	unit := "ocpp201"
+	if id != "" {
+		unit = id
+	}
+	log := util.NewLogger(unit)
+
+	station := ocpp.NewStation(log, id, evse, timeout)
+	if err := ocpp.Instance201().Register(id, station); err != nil {
+		return nil, err
+	}
+
+	// remote started transactions must be accepted
+	ocpp.Auth().AddIdentifiers(idtag)
+
+	c := &OCPP201{
+		log:     log,
+		station: station,
+		idtag:   idtag,
+		timeout: timeout,
+	}
+
+	c.log.DEBUG.Printf("waiting for station: %v", timeout)
+
+	select {
+	case <-time.After(timeout):
+		return nil, api.ErrTimeout
+	case <-station.HasConnected():
+	}
+
+	var meterSampleInterval time.Duration
+
+	// device model variables
+	if err := c.getVariables([]provisioning.GetVariableData{
+		getVariableData(ocpp.ComponentSampledDataCtrlr, ocpp.VariableTxUpdatedMeasurands),
+		getVariableData(ocpp.ComponentSampledDataCtrlr, ocpp.VariableTxUpdatedInterval),
+		getVariableData(ocpp.ComponentSmartChargingCtrlr, ocpp.VariablePhases3to1),
+	}, func(variable, value string) (err error) {
+		switch variable {
+		case ocpp.VariableTxUpdatedMeasurands:
+			c.measurands = value
+
+		case ocpp.VariableTxUpdatedInterval:
+			var val int
+			if val, err = strconv.Atoi(value); err == nil {
+				meterSampleInterval = time.Duration(val) * time.Second
+			}
+
+		case ocpp.VariablePhases3to1:
+			c.phaseSwitching, err = strconv.ParseBool(value)
+		}
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if meterValues != "" && meterValues != c.measurands {
+		if err := c.setVariable(ocpp.ComponentSampledDataCtrlr, ocpp.VariableTxUpdatedMeasurands, meterValues); err != nil {
+			return nil, err
+		}
+
+		// configuration activated
+		c.measurands = meterValues
+	}
+
+	// get initial meter values and configure sample rate
+	if c.hasMeasurement(types.MeasurandPowerActiveImport) || c.hasMeasurement(types.MeasurandEnergyActiveImportRegister) {
+		ocpp.Instance201().TriggerMessageRequest(station.ID(), evse, remotecontrol.MessageTriggerMeterValues)
+
+		if meterSampleInterval > meterInterval && meterInterval > 0 {
+			if err := c.setVariable(ocpp.ComponentSampledDataCtrlr, ocpp.VariableTxUpdatedInterval, strconv.Itoa(int(meterInterval.Seconds()))); err != nil {
+				return nil, err
+			}
+		}
+
+		if meterInterval > 0 {
+			c.log.DEBUG.Println("enabling meter watchdog")
+			go station.WatchDog(meterInterval)
+		}
+	}
+
+	// request initial status
+	_ = station.Initialized(statusTimeout)
+
+	return c, nil
+}
+
+// hasMeasurement checks if the updated measurands contain given measurement
+func (c *OCPP201) hasMeasurement(val types.Measurand) bool {
+	return lo.Contains(strings.Split(c.measurands, ","), string(val))
+}
+
+func getVariableData(component, variable string) provisioning.GetVariableData {
+	return provisioning.GetVariableData{
+		Component: types.Component{Name: component},
+		Variable:  types.Variable{Name: variable},
+	}
+}
+
+// getVariables reads device model variables, unsupported variables are logged and skipped
+func (c *OCPP201) getVariables(data []provisioning.GetVariableData, fun func(variable, value string) error) error {
+	rc := make(chan error, 1)
+
+	err := ocpp.Instance201().GetVariables(c.station.ID(), func(resp *provisioning.GetVariablesResponse, err error) {
+		if err == nil {
+			for _, res := range resp.GetVariableResult {
+				if res.AttributeStatus != provisioning.GetVariableStatusAccepted {
+					c.log.ERROR.Printf("%s.%s: %s", res.Component.Name, res.Variable.Name, res.AttributeStatus)
+					continue
+				}
+
+				c.log.TRACE.Printf("%s.%s: %s", res.Component.Name, res.Variable.Name, res.AttributeValue)
+
+				if err = fun(res.Variable.Name, res.AttributeValue); err != nil {
+					err = fmt.Errorf("%s.%s: %w", res.Component.Name, res.Variable.Name, err)
+					break
+				}
+			}
+		}
+
+		rc <- err
+	}, data)
+
+	return c.wait(err, rc)
+}
+
+// setVariable updates a device model variable
+func (c *OCPP201) setVariable(component, variable, value string) error {
+	rc := make(chan error, 1)
+
+	err := ocpp.Instance201().SetVariables(c.station.ID(), func(resp *provisioning.SetVariablesResponse, err error) {
+		c.log.TRACE.Printf("%T: %v", resp, resp)
+
+		if err == nil && resp != nil {
+			for _, res := range resp.SetVariableResult {
+				if res.AttributeStatus != provisioning.SetVariableStatusAccepted {
+					err = fmt.Errorf("SetVariables failed: %s", res.AttributeStatus)
+				}
+			}
+		}
+
+		rc <- err
+	}, []provisioning.SetVariableData{{
+		Component:      types.Component{Name: component},
+		Variable:       types.Variable{Name: variable},
+		AttributeValue: value,
+	}})
+
+	return c.wait(err, rc)
+}
+
+// wait waits for a station roundtrip with timeout
+func (c *OCPP201) wait(err error, rc chan error) error {
+	if err == nil {
+		select {
+		case err = <-rc:
+			close(rc)
+		case <-time.After(c.timeout):
+			err = api.ErrTimeout
+		}
+	}
+	return err
+}
+
+// Status implements the api.Charger interface
+func (c *OCPP201) Status() (api.ChargeStatus, error) {
+	return c.station.Status()
+}
+
+// Enabled implements the api.Charger interface
+func (c *OCPP201) Enabled() (bool, error) {
+	return c.station.Enabled(), nil
+}
+
+// Enable implements the api.Charger interface
+func (c *OCPP201) Enable(enable bool) error {
+	var err error
+	rc := make(chan error, 1)
+
+	if enable {
+		err = ocpp.Instance201().StartTransaction(c.station.ID(), c.station.EVSE(), c.idtag, getTxChargingProfile201(c.current, c.phases),
+			func(resp *remotecontrol.RequestStartTransactionResponse, err error) {
+				c.log.TRACE.Printf("%T: %+v", resp, resp)
+
+				if err == nil && resp != nil && resp.Status != remotecontrol.RequestStartStopStatusAccepted {
+					err = errors.New(string(resp.Status))
+				}
+
+				rc <- err
+			})
+	} else {
+		err = ocpp.Instance201().RequestStopTransaction(c.station.ID(), func(resp *remotecontrol.RequestStopTransactionResponse, err error) {
+			c.log.TRACE.Printf("%T: %+v", resp, resp)
+
+			if err == nil && resp != nil && resp.Status != remotecontrol.RequestStartStopStatusAccepted {
+				err = errors.New(string(resp.Status))
+			}
+
+			rc <- err
+		}, c.station.TransactionID())
+	}
+
+	return c.wait(err, rc)
+}
+
+// updatePeriod sets a single charging schedule period with given current and phases
+func (c *OCPP201) updatePeriod(current float64, phases int) error {
+	// current period can only be updated if transaction is active
+	if c.station.TransactionID() == "" {
+		return nil
+	}
+
+	c.log.TRACE.Printf("update period with phases: %d, current: %f", phases, current)
+
+	profile := getTxChargingProfile201(current, phases)
+	profile.TransactionID = c.station.TransactionID()
+
+	rc := make(chan error, 1)
+	err := ocpp.Instance201().SetChargingProfile(c.station.ID(), func(resp *smartcharging.SetChargingProfileResponse, err error) {
+		c.log.TRACE.Printf("%T: %+v", resp, resp)
+
+		if err == nil && resp != nil && resp.Status != smartcharging.ChargingProfileStatusAccepted {
+			err = errors.New(string(resp.Status))
+		}
+
+		rc <- err
+	}, c.station.EVSE(), profile)
+
+	if err := c.wait(err, rc); err != nil {
+		return fmt.Errorf("set charging profile: %w", err)
+	}
+
+	return nil
+}
+
+func getTxChargingProfile201(current float64, phases int) *types.ChargingProfile {
+	period := types.NewChargingSchedulePeriod(0, current)
+	if phases != 0 {
+		period.NumberPhases = &phases
+	}
+
+	return &types.ChargingProfile{
+		ID:                     1,
+		StackLevel:             0,
+		ChargingProfilePurpose: types.ChargingProfilePurposeTxProfile,
+		ChargingProfileKind:    types.ChargingProfileKindRelative,
+		ChargingSchedule: []types.ChargingSchedule{{
+			ID:                     1,
+			ChargingRateUnit:       types.ChargingRateUnitAmperes,
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{period},
+		}},
+	}
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *OCPP201) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+var _ api.ChargerEx = (*OCPP201)(nil)
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *OCPP201) MaxCurrentMillis(current float64) error {
+	err := c.updatePeriod(current, c.phases)
+	if err == nil {
+		c.current = current
+	}
+	return err
+}
+
+// CurrentPower implements the api.Meter interface
+func (c *OCPP201) currentPower() (float64, error) {
+	return c.station.CurrentPower()
+}
+
+// TotalEnergy implements the api.MeterTotal interface
+func (c *OCPP201) totalEnergy() (float64, error) {
+	return c.station.TotalEnergy()
+}
+
+// Currents implements the api.MeterCurrent interface
+func (c *OCPP201) currents() (float64, float64, float64, error) {
+	return c.station.Currents()
+}
+
+// Phases1p3p implements the api.PhaseSwitcher interface
+func (c *OCPP201) phases1p3p(phases int) error {
+	c.phases = phases
+
+	// applied with the next transaction if currently disabled
+	return c.updatePeriod(c.current, c.phases)
+}
+
+var _ api.Identifier = (*OCPP201)(nil)
+
+// Identify implements the api.Identifier interface
+func (c *OCPP201) Identify() (string, error) {
+	// remote started transactions don't identify the vehicle
+	if id := c.station.IdTag(); id != c.idtag {
+		return id, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,281 @@
+package charger
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateOCPP201(base *OCPP201, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), phaseSwitcher func(phases int) error) api.Charger {
+	switch {
+	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+		return base
+
+	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+		}
+
+	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.MeterCurrent
+		}{
+			OCPP201: base,
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterCurrent
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPP201MeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPP201PhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateOCPP201MeterImpl struct {
+	meter func() (float64, error)
+}
+
+func (impl *decorateOCPP201MeterImpl) CurrentPower() (float64, error) {
+	return impl.meter()
+}
+
+type decorateOCPP201MeterCurrentImpl struct {
+	meterCurrent func() (float64, float64, float64, error)
+}
+
+func (impl *decorateOCPP201MeterCurrentImpl) Currents() (float64, float64, float64, error) {
+	return impl.meterCurrent()
+}
+
+type decorateOCPP201MeterEnergyImpl struct {
+	meterEnergy func() (float64, error)
+}
+
+func (impl *decorateOCPP201MeterEnergyImpl) TotalEnergy() (float64, error) {
+	return impl.meterEnergy()
+}
+
+type decorateOCPP201PhaseSwitcherImpl struct {
+	phaseSwitcher func(phases int) error
+}
+
+func (impl *decorateOCPP201PhaseSwitcherImpl) Phases1p3p(phases int) error {
+	return impl.phaseSwitcher(phases)
+}
@@ -6,12 +6,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), phaseSwitcher func(int) error) api.Charger {
+func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), phaseSwitcher func(phases int) error, bidirectionalCharger func(current float64) error) api.Charger {
 	switch {
-	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
 		return base
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -22,7 +22,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.MeterEnergy
@@ -33,7 +33,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -48,7 +48,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.MeterCurrent
@@ -59,7 +59,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -74,7 +74,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.MeterCurrent
@@ -89,7 +89,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -108,7 +108,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.PhaseSwitcher
@@ -119,7 +119,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -134,7 +134,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.MeterEnergy
@@ -149,7 +149,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -168,7 +168,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.MeterCurrent
@@ -183,7 +183,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -202,7 +202,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.MeterCurrent
@@ -221,7 +221,7 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+	case bidirectionalCharger == nil && meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
 		return &struct {
 			*OCPP
 			api.Meter
@@ -243,11 +243,323 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case bidirectionalCharger != nil && meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+		return &struct {
+			*OCPP
+			api.BidirectionalCharger
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			BidirectionalCharger: &decorateOCPPBidirectionalChargerImpl{
+				bidirectionalCharger: bidirectionalCharger,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
 	}
 
 	return nil
 }
 
+type decorateOCPPBidirectionalChargerImpl struct {
+	bidirectionalCharger func(current float64) error
+}
+
+func (impl *decorateOCPPBidirectionalChargerImpl) Discharge(current float64) error {
+	return impl.bidirectionalCharger(current)
+}
+
 type decorateOCPPMeterImpl struct {
 	meter func() (float64, error)
 }
@@ -273,7 +585,7 @@ func (impl *decorateOCPPMeterEnergyImpl) TotalEnergy() (float64, error) {
 }
 
 type decorateOCPPPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+	phaseSwitcher func(phases int) error
 }
 
 func (impl *decorateOCPPPhaseSwitcherImpl) Phases1p3p(phases int) error {
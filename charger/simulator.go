@@ -0,0 +1,53 @@
+package charger
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulator"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Simulator is a virtual charger connected to the simulated site
+type Simulator struct {
+	*simulator.Charger
+}
+
+func init() {
+	registry.Add("simulator", NewSimulatorFromConfig)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateSimulator -b *Simulator -r api.Charger -t "api.PhaseSwitcher,Phases1p3p,func(int) error"
+
+// NewSimulatorFromConfig creates a simulated charger from generic config
+func NewSimulatorFromConfig(other map[string]interface{}) (api.Charger, error) {
+	var cc struct {
+		simulator.ChargerConfig `mapstructure:",squash"`
+		Phases1p3p              bool
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	c, err := simulator.Instance().AddCharger(cc.ChargerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	wb := &Simulator{
+		Charger: c,
+	}
+
+	var phases1p3p func(int) error
+	if cc.Phases1p3p {
+		phases1p3p = c.SetPhases
+	}
+
+	return decorateSimulator(wb, phases1p3p), nil
+}
+
+var _ api.ChargerEx = (*Simulator)(nil)
+var _ api.Meter = (*Simulator)(nil)
+var _ api.MeterCurrent = (*Simulator)(nil)
+var _ api.MeterEnergy = (*Simulator)(nil)
+var _ api.ChargeRater = (*Simulator)(nil)
+var _ api.Identifier = (*Simulator)(nil)
@@ -0,0 +1,35 @@
+package charger
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateSimulator(base *Simulator, phaseSwitcher func(int) error) api.Charger {
+	switch {
+	case phaseSwitcher == nil:
+		return base
+
+	case phaseSwitcher != nil:
+		return &struct {
+			*Simulator
+			api.PhaseSwitcher
+		}{
+			Simulator: base,
+			PhaseSwitcher: &decorateSimulatorPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateSimulatorPhaseSwitcherImpl struct {
+	phaseSwitcher func(int) error
+}
+
+func (impl *decorateSimulatorPhaseSwitcherImpl) Phases1p3p(p0 int) error {
+	return impl.phaseSwitcher(p0)
+}
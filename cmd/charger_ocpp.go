@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/spf13/cobra"
+)
+
+// chargerOcppCmd represents the charger ocpp command
+var chargerOcppCmd = &cobra.Command{
+	Use:   "ocpp [name]",
+	Short: "Maintain OCPP chargers. Shows the configuration if no action is given.",
+	Run:   runChargerOcpp,
+}
+
+const (
+	flagOcppKey         = "key"
+	flagOcppChange      = "change"
+	flagOcppReset       = "reset"
+	flagOcppUnlock      = "unlock"
+	flagOcppTrigger     = "trigger"
+	flagOcppDiagnostics = "diagnostics"
+	flagOcppFirmware    = "firmware"
+)
+
+func init() {
+	chargerCmd.AddCommand(chargerOcppCmd)
+
+	chargerOcppCmd.Flags().StringSlice(flagOcppKey, nil, "Show configuration keys")
+	chargerOcppCmd.Flags().String(flagOcppChange, "", "Change configuration (key=value)")
+	chargerOcppCmd.Flags().String(flagOcppReset, "", "Reset charge point (soft or hard)")
+	chargerOcppCmd.Flags().Bool(flagOcppUnlock, false, "Unlock connector")
+	chargerOcppCmd.Flags().String(flagOcppTrigger, "", "Trigger message (e.g. StatusNotification, MeterValues)")
+	chargerOcppCmd.Flags().String(flagOcppDiagnostics, "", "Upload diagnostics to location")
+	chargerOcppCmd.Flags().String(flagOcppFirmware, "", "Update firmware from location")
+}
+
+// finished returns true if firmware or diagnostics progress has completed
+func finished(status ocpp.MaintenanceStatus) bool {
+	switch status.Diagnostics {
+	case firmware.DiagnosticsStatusUploaded, firmware.DiagnosticsStatusUploadFailed:
+		return true
+	}
+
+	switch status.Firmware {
+	case firmware.FirmwareStatusInstalled, firmware.FirmwareStatusInstallationFailed, firmware.FirmwareStatusDownloadFailed:
+		return true
+	}
+
+	return false
+}
+
+func runChargerOcpp(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// select single charger
+	if err := selectByName(cmd, &conf.Chargers); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// report firmware and diagnostics progress
+	progressC := make(chan ocpp.MaintenanceStatus, 1)
+	ocpp.SetStatusPublisher(func(status map[string]ocpp.MaintenanceStatus) {
+		for id, s := range status {
+			fmt.Printf("%s: firmware %s, diagnostics %s\n", id, s.Firmware, s.Diagnostics)
+			select {
+			case progressC <- s:
+			default:
+			}
+		}
+	})
+
+	if err := cp.configureChargers(conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	chargers := cp.ocppChargers()
+	if len(args) == 1 {
+		name := args[0]
+		c, ok := chargers[name]
+		if !ok {
+			log.FATAL.Fatalf("ocpp charger does not exist: %s", name)
+		}
+		chargers = map[string]ocpp.ChargePointProvider{name: c}
+	}
+
+	flags := cmd.Flags()
+
+	var wait bool
+	for name, c := range chargers {
+		chargePoint := c.ChargePoint()
+
+		switch {
+		case flags.Lookup(flagOcppChange).Changed:
+			key, value, ok := strings.Cut(flags.Lookup(flagOcppChange).Value.String(), "=")
+			if !ok {
+				log.FATAL.Fatalln("change configuration: expected key=value")
+			}
+
+			status, err := chargePoint.ChangeConfiguration(key, value)
+			if err != nil {
+				log.ERROR.Println("change configuration:", err)
+				continue
+			}
+
+			fmt.Printf("%s: %s\n", name, status)
+
+		case flags.Lookup(flagOcppReset).Changed:
+			resetType := core.ResetTypeSoft
+			if strings.EqualFold(flags.Lookup(flagOcppReset).Value.String(), string(core.ResetTypeHard)) {
+				resetType = core.ResetTypeHard
+			}
+
+			if err := chargePoint.Reset(resetType); err != nil {
+				log.ERROR.Println("reset:", err)
+			}
+
+		case flags.Lookup(flagOcppUnlock).Changed:
+			if err := chargePoint.UnlockConnector(c.Connector()); err != nil {
+				log.ERROR.Println("unlock:", err)
+			}
+
+		case flags.Lookup(flagOcppTrigger).Changed:
+			message := remotetrigger.MessageTrigger(flags.Lookup(flagOcppTrigger).Value.String())
+			if err := chargePoint.TriggerMessage(message, c.Connector()); err != nil {
+				log.ERROR.Println("trigger:", err)
+			}
+
+		case flags.Lookup(flagOcppDiagnostics).Changed:
+			file, err := chargePoint.GetDiagnostics(flags.Lookup(flagOcppDiagnostics).Value.String())
+			if err != nil {
+				log.ERROR.Println("diagnostics:", err)
+				continue
+			}
+
+			fmt.Printf("%s: uploading %s\n", name, file)
+			wait = true
+
+		case flags.Lookup(flagOcppFirmware).Changed:
+			if err := chargePoint.UpdateFirmware(flags.Lookup(flagOcppFirmware).Value.String(), time.Now()); err != nil {
+				log.ERROR.Println("firmware:", err)
+				continue
+			}
+
+			wait = true
+
+		default:
+			var keys []string
+			if flags.Lookup(flagOcppKey).Changed {
+				keys, _ = flags.GetStringSlice(flagOcppKey)
+			}
+
+			res, err := chargePoint.GetConfiguration(keys)
+			if err != nil {
+				log.ERROR.Println("get configuration:", err)
+			}
+
+			fmt.Println(name)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+			for _, kv := range res {
+				var val string
+				if kv.Value != nil {
+					val = *kv.Value
+				}
+
+				ro := ""
+				if kv.Readonly {
+					ro = "(r/o)"
+				}
+
+				fmt.Fprintf(w, "%s:\t%s\t%s\n", kv.Key, val, ro)
+			}
+			w.Flush()
+		}
+	}
+
+	// wait for firmware or diagnostics progress
+	for wait {
+		if status := <-progressC; finished(status) {
+			break
+		}
+	}
+
+	// wait for shutdown
+	<-shutdownDoneC()
+}
@@ -14,11 +14,14 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/replay"
 	"github.com/evcc-io/evcc/server"
 	autoauth "github.com/evcc-io/evcc/server/auth"
+	"github.com/evcc-io/evcc/simulator"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/vehicle"
@@ -59,10 +62,13 @@ type config struct {
 	Interval     time.Duration
 	Mqtt         mqttConfig
 	ModbusProxy  []proxyConfig
+	ModbusServer modbusServerConfig
 	Database     dbConfig
 	Javascript   map[string]interface{}
 	Influx       server.InfluxConfig
 	EEBus        map[string]interface{}
+	OCPP         ocppConfig
+	Simulator    *simulator.Config
 	HEMS         typedConfig
 	Messaging    messagingConfig
 	Meters       []qualifiedConfig
@@ -71,19 +77,46 @@ type config struct {
 	Tariffs      tariffConfig
 	Site         map[string]interface{}
 	LoadPoints   []map[string]interface{}
+	Consumers    []map[string]interface{}
 }
 
 type mqttConfig struct {
 	mqtt.Config `mapstructure:",squash"`
 	Topic       string
+	Discovery   string // home assistant discovery prefix
 }
 
 type proxyConfig struct {
 	Port            int
 	ReadOnly        bool
+	Cache           time.Duration
+	Devices         []proxyDeviceConfig
 	modbus.Settings `mapstructure:",squash"`
 }
 
+type proxyDeviceConfig struct {
+	UnitID          uint8
+	modbus.Settings `mapstructure:",squash"`
+}
+
+// devices returns the proxy's upstream devices by unit id
+func (c proxyConfig) devices() map[uint8]modbus.Settings {
+	res := make(map[uint8]modbus.Settings, len(c.Devices))
+	for _, dev := range c.Devices {
+		res[dev.UnitID] = dev.Settings
+	}
+	return res
+}
+
+type modbusServerConfig struct {
+	Port     int
+	ReadOnly bool
+}
+
+type ocppConfig struct {
+	Auth ocpp.AuthConfig
+}
+
 type dbConfig struct {
 	Type string
 	Dsn  string
@@ -108,6 +141,8 @@ type tariffConfig struct {
 	Currency string
 	Grid     typedConfig
 	FeedIn   typedConfig
+	CO2      typedConfig
+	Solar    []typedConfig
 }
 
 type networkConfig struct {
@@ -134,6 +169,7 @@ type ConfigProvider struct {
 	vehicles map[string]api.Vehicle
 	visited  map[string]bool
 	auth     *util.AuthCollection
+	recorder *replay.Recorder // records device access if set
 }
 
 func (cp *ConfigProvider) TrackVisitors() {
@@ -164,6 +200,17 @@ func (cp *ConfigProvider) Charger(name string) (api.Charger, error) {
 	return nil, fmt.Errorf("charger does not exist: %s", name)
 }
 
+// ocppChargers returns the chargers connected using OCPP 1.6 by name
+func (cp *ConfigProvider) ocppChargers() map[string]ocpp.ChargePointProvider {
+	res := make(map[string]ocpp.ChargePointProvider)
+	for name, c := range cp.chargers {
+		if c, ok := replay.Unwrap(c).(ocpp.ChargePointProvider); ok {
+			res[name] = c
+		}
+	}
+	return res
+}
+
 // Vehicle provides vehicles by name
 func (cp *ConfigProvider) Vehicle(name string) (api.Vehicle, error) {
 	if vehicle, ok := cp.vehicles[name]; ok {
@@ -183,6 +230,41 @@ func (cp *ConfigProvider) configure(conf config) error {
 	return err
 }
 
+// replay creates stand-ins for the configured devices answering from the recording
+func (cp *ConfigProvider) replay(conf config, player *replay.Player) error {
+	cp.meters = make(map[string]api.Meter)
+	for _, cc := range conf.Meters {
+		m, err := player.Meter(cc.Name)
+		if err != nil {
+			return err
+		}
+
+		cp.meters[cc.Name] = m
+	}
+
+	cp.chargers = make(map[string]api.Charger)
+	for _, cc := range conf.Chargers {
+		c, err := player.Charger(cc.Name)
+		if err != nil {
+			return err
+		}
+
+		cp.chargers[cc.Name] = c
+	}
+
+	cp.vehicles = make(map[string]api.Vehicle)
+	for _, cc := range conf.Vehicles {
+		v, err := player.Vehicle(cc.Name)
+		if err != nil {
+			return err
+		}
+
+		cp.vehicles[cc.Name] = v
+	}
+
+	return nil
+}
+
 func (cp *ConfigProvider) configureMeters(conf config) error {
 	cp.meters = make(map[string]api.Meter)
 	for id, cc := range conf.Meters {
@@ -200,6 +282,10 @@ func (cp *ConfigProvider) configureMeters(conf config) error {
 			return fmt.Errorf("duplicate meter name: %s already defined and must be unique", cc.Name)
 		}
 
+		if cp.recorder != nil {
+			m = cp.recorder.Meter(cc.Name, m)
+		}
+
 		cp.meters[cc.Name] = m
 	}
 
@@ -231,6 +317,10 @@ func (cp *ConfigProvider) configureChargers(conf config) error {
 				return fmt.Errorf("duplicate charger name: %s already defined and must be unique", cc.Name)
 			}
 
+			if cp.recorder != nil {
+				c = cp.recorder.Charger(cc.Name, c)
+			}
+
 			cp.chargers[cc.Name] = c
 			return nil
 		})
@@ -280,6 +370,10 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 				return fmt.Errorf("duplicate vehicle name: %s already defined and must be unique", cc.Name)
 			}
 
+			if cp.recorder != nil {
+				v = cp.recorder.Vehicle(cc.Name, v)
+			}
+
 			cp.vehicles[cc.Name] = v
 			return nil
 		})
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/replay"
 	"github.com/fatih/structs"
 )
 
@@ -206,6 +207,11 @@ func (d *dumper) Dump(name string, v interface{}) {
 func (d *dumper) DumpDiagnosis(v interface{}) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 
+	// diagnosis is not recorded
+	if c, ok := v.(api.Charger); ok {
+		v = replay.Unwrap(c)
+	}
+
 	if v, ok := v.(api.Diagnosis); ok {
 		fmt.Fprintln(w, "Diagnostic dump:")
 		v.Diagnose()
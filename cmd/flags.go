@@ -36,6 +36,9 @@ const (
 	flagStop            = "stop"
 	flagStopDescription = "Stop charging"
 
+	flagRecord            = "record"
+	flagRecordDescription = "Record device readings and calls to file (compressed if ending in .gz)"
+
 	flagDigits = "digits"
 	flagDelay  = "delay"
 )
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+
+	coredb "github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [sqlite file]",
+	Short: "Copy sessions and settings from sqlite database to configured database",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if typ := strings.ToLower(conf.Database.Type); typ == "" || typ == "sqlite" {
+		log.FATAL.Fatal("target database must not be sqlite")
+	}
+
+	file := "~/.evcc/evcc.db"
+	if len(args) == 1 {
+		file = args[0]
+	}
+
+	src, err := db.New("sqlite", file)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	dst, err := db.New(strings.ToLower(conf.Database.Type), conf.Database.Dsn)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	log.INFO.Printf("migrating %s to %s", file, conf.Database.Type)
+
+	if err := db.Copy(src, dst, new(coredb.Session)); err != nil {
+		log.FATAL.Fatalf("sessions: %v", err)
+	}
+
+	if err := settings.Migrate(src, dst); err != nil {
+		log.FATAL.Fatalf("settings: %v", err)
+	}
+
+	log.INFO.Println("migration complete")
+}
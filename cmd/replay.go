@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/replay"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <recording>",
+	Short: "Replay recorded device readings and show differing charger and battery control",
+	Args:  cobra.ExactArgs(1),
+	Run:   runReplay,
+}
+
+const flagInterval = "interval"
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().Duration(flagInterval, 0, "Control interval (default from config)")
+}
+
+func runReplay(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// don't persist replayed sessions
+	conf.Database.Dsn = ""
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	clock := clock.NewMock()
+
+	player, err := replay.LoadFile(args[0], clock)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	clock.Set(player.Start())
+
+	if err := cp.replay(conf, player); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	site, err := configureSiteFromProvider(conf)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if len(site.LoadPoints()) == 0 {
+		log.FATAL.Fatal("replay requires at least one loadpoint")
+	}
+
+	interval := conf.Interval
+	if flag := cmd.Flags().Lookup(flagInterval); flag.Changed {
+		interval, _ = cmd.Flags().GetDuration(flagInterval)
+	}
+
+	// discard ui and push messages
+	valueChan := make(chan util.Param)
+	pushChan := make(chan push.Event)
+	go func() {
+		for {
+			select {
+			case <-valueChan:
+			case <-pushChan:
+			}
+		}
+	}()
+
+	site.SetClock(clock)
+	site.Prepare(valueChan, pushChan)
+
+	log.INFO.Printf("replaying %s to %s", player.Start().Format(time.RFC3339), player.End().Format(time.RFC3339))
+	site.Replay(clock, player.End(), interval)
+
+	if diffs := player.Diff(os.Stdout); diffs > 0 {
+		log.INFO.Printf("%d differences", diffs)
+		os.Exit(1)
+	}
+
+	log.INFO.Println("no differences")
+}
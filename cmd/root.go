@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
@@ -66,6 +67,8 @@ func init() {
 
 	rootCmd.Flags().Bool("profile", false, "Expose pprof profiles")
 	bind(rootCmd, "profile")
+
+	rootCmd.Flags().String(flagRecord, "", flagRecordDescription)
 }
 
 // initConfig reads in config file and ENV variables if set
@@ -162,12 +165,17 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// setup modbus proxy
 	if err == nil {
 		for _, cfg := range conf.ModbusProxy {
-			if err = modbus.StartProxy(cfg.Port, cfg.Settings, cfg.ReadOnly); err != nil {
+			if err = modbus.StartProxy(cfg.Port, cfg.Settings, cfg.devices(), cfg.ReadOnly, cfg.Cache); err != nil {
 				break
 			}
 		}
 	}
 
+	// record devices for replay
+	if file := cmd.Flags().Lookup(flagRecord).Value.String(); err == nil && file != "" {
+		err = configureRecorder(file)
+	}
+
 	// setup site and loadpoints
 	var site *core.Site
 	if err == nil {
@@ -184,6 +192,17 @@ func runRoot(cmd *cobra.Command, args []string) {
 	if err == nil && conf.Mqtt.Broker != "" {
 		publisher := server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"))
 		go publisher.Run(site, pipe.NewDropper(ignoreMqtt...).Pipe(tee.Attach()))
+
+		// home assistant discovery
+		if conf.Mqtt.Discovery != "" {
+			discovery := server.NewHomeAssistant(conf.Mqtt.Discovery, strings.Trim(conf.Mqtt.Topic, "/"))
+			go discovery.Run(site, tee.Attach())
+		}
+	}
+
+	// setup modbus server
+	if err == nil && conf.ModbusServer.Port != 0 {
+		err = modbus.StartServer(conf.ModbusServer.Port, site, tee.Attach(), conf.ModbusServer.ReadOnly)
 	}
 
 	// announce on mDNS
@@ -235,6 +254,15 @@ func runRoot(cmd *cobra.Command, args []string) {
 	if err == nil {
 		httpd.RegisterSiteHandlers(site, cache)
 
+		// ocpp maintenance
+		if chargers := cp.ocppChargers(); len(chargers) > 0 {
+			httpd.RegisterOCPPHandlers(chargers)
+
+			ocpp.SetStatusPublisher(func(status map[string]ocpp.MaintenanceStatus) {
+				valueChan <- util.Param{Key: "ocpp", Val: status}
+			})
+		}
+
 		// set channels
 		site.DumpConfig()
 		site.Prepare(valueChan, pushChan)
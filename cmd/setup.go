@@ -10,6 +10,7 @@ import (
 
 	paho "github.com/eclipse/paho.mqtt.golang"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/loadpoint"
@@ -17,9 +18,11 @@ import (
 	"github.com/evcc-io/evcc/provider/javascript"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/replay"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/simulator"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/locale"
@@ -107,6 +110,16 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 		err = configureEEBus(conf.EEBus)
 	}
 
+	// setup OCPP central system authorization
+	if err == nil {
+		configureOCPP(conf.OCPP)
+	}
+
+	// setup simulated site
+	if err == nil && conf.Simulator != nil {
+		simulator.Configure(*conf.Simulator)
+	}
+
 	return
 }
 
@@ -206,6 +219,28 @@ func configureEEBus(conf map[string]interface{}) error {
 	return nil
 }
 
+// setup OCPP
+func configureOCPP(conf ocppConfig) {
+	ocpp.Auth().Configure(conf.Auth)
+}
+
+// setup device recording
+func configureRecorder(file string) error {
+	recorder, err := replay.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed configuring recorder: %w", err)
+	}
+
+	cp.recorder = recorder
+	shutdown.Register(func() {
+		if err := recorder.Close(); err != nil {
+			log.ERROR.Println("cannot close recording:", err)
+		}
+	})
+
+	return nil
+}
+
 // setup messaging
 func configureMessengers(conf messagingConfig, cache *util.Cache) (chan push.Event, error) {
 	messageChan := make(chan push.Event, 1)
@@ -230,6 +265,8 @@ func configureMessengers(conf messagingConfig, cache *util.Cache) (chan push.Eve
 
 func configureTariffs(conf tariffConfig) (tariff.Tariffs, error) {
 	var grid, feedin api.Tariff
+	var co2 api.CO2Intensity
+	var solar api.SolarForecaster
 	var currencyCode currency.Unit = currency.EUR
 	var err error
 
@@ -245,40 +282,90 @@ func configureTariffs(conf tariffConfig) (tariff.Tariffs, error) {
 		feedin, err = tariff.NewFromConfig(conf.FeedIn.Type, conf.FeedIn.Other)
 	}
 
+	if err == nil && conf.CO2.Type != "" {
+		co2, err = tariff.NewCO2FromConfig(conf.CO2.Type, conf.CO2.Other)
+	}
+
+	if err == nil && len(conf.Solar) > 0 {
+		solar, err = configureSolarForecast(conf.Solar)
+	}
+
 	if err != nil {
 		err = fmt.Errorf("failed configuring tariff: %w", err)
 	}
 
-	tariffs := tariff.NewTariffs(currencyCode, grid, feedin)
+	tariffs := tariff.NewTariffs(currencyCode, grid, feedin, co2, solar)
 
 	return *tariffs, err
 }
 
+// configureSolarForecast combines the pv forecasts of all configured arrays
+func configureSolarForecast(conf []typedConfig) (api.SolarForecaster, error) {
+	var res tariff.SolarForecasts
+
+	for i, cc := range conf {
+		f, err := tariff.NewSolarFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return nil, fmt.Errorf("solar %d: %w", i+1, err)
+		}
+
+		res = append(res, f)
+	}
+
+	if len(res) == 1 {
+		return res[0], nil
+	}
+
+	return res, nil
+}
+
 func configureSiteAndLoadpoints(conf config) (site *core.Site, err error) {
 	if err = cp.configure(conf); err == nil {
-		var loadPoints []*core.LoadPoint
-		loadPoints, err = configureLoadPoints(conf, cp)
+		site, err = configureSiteFromProvider(conf)
+	}
 
-		var tariffs tariff.Tariffs
-		if err == nil {
-			tariffs, err = configureTariffs(conf.Tariffs)
-		}
+	// site and loadpoints follow the simulation time of the simulated devices
+	if err == nil && conf.Simulator != nil {
+		site.SetClock(simulator.Instance().Clock())
+	}
 
-		if err == nil {
-			// list of vehicles
-			vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
-				return v
-			})
+	return site, err
+}
+
+// configureSiteFromProvider creates loadpoints, consumers and site using the config provider's devices
+func configureSiteFromProvider(conf config) (site *core.Site, err error) {
+	var loadPoints []*core.LoadPoint
+	loadPoints, err = configureLoadPoints(conf, cp)
+
+	var consumers []*core.Consumer
+	if err == nil {
+		consumers, err = configureConsumers(conf, cp)
+	}
+
+	var tariffs tariff.Tariffs
+	if err == nil {
+		tariffs, err = configureTariffs(conf.Tariffs)
+	}
 
-			site, err = configureSite(conf.Site, cp, loadPoints, vehicles, tariffs)
+	if err == nil {
+		// list of vehicles
+		vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
+			return v
+		})
+
+		// authorize vehicle id tags at the OCPP central system
+		for _, v := range vehicles {
+			ocpp.Auth().AddIdentifiers(v.Identifiers()...)
 		}
+
+		site, err = configureSite(conf.Site, cp, loadPoints, consumers, vehicles, tariffs)
 	}
 
 	return site, err
 }
 
-func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
-	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, vehicles, tariffs)
+func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, consumers []*core.Consumer, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
+	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, consumers, vehicles, tariffs)
 	if err != nil {
 		return nil, fmt.Errorf("failed configuring site: %w", err)
 	}
@@ -309,3 +396,17 @@ func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.Lo
 
 	return loadPoints, nil
 }
+
+func configureConsumers(conf config, cp *ConfigProvider) (consumers []*core.Consumer, err error) {
+	for id, cc := range conf.Consumers {
+		log := util.NewLogger("consumer-" + strconv.Itoa(id+1))
+		c, err := core.NewConsumerFromConfig(log, cp, cc)
+		if err != nil {
+			return nil, fmt.Errorf("failed configuring consumer: %w", err)
+		}
+
+		consumers = append(consumers, c)
+	}
+
+	return consumers, nil
+}
@@ -24,6 +24,43 @@ type dynamicType struct {
 
 type typeStruct struct {
 	Type, ShortType, Signature, Function, VarName string
+	Params, Args, Results                         string
+}
+
+// parseSignature splits a function signature into parameters, argument names and results.
+// Unnamed parameters are named p0, p1, ...
+func parseSignature(signature string) (params, args, results string, err error) {
+	if !strings.HasPrefix(signature, "func(") {
+		return "", "", "", fmt.Errorf("invalid signature: %s", signature)
+	}
+
+	end := strings.Index(signature, ")")
+	if end < 0 {
+		return "", "", "", fmt.Errorf("invalid signature: %s", signature)
+	}
+
+	results = strings.TrimSpace(signature[end+1:])
+	inner := strings.TrimSpace(signature[len("func("):end])
+	if inner == "" {
+		return "", "", results, nil
+	}
+
+	var ps, as []string
+	for i, p := range strings.Split(inner, ",") {
+		p = strings.TrimSpace(p)
+
+		name := fmt.Sprintf("p%d", i)
+		if parts := strings.Fields(p); len(parts) > 1 {
+			name = parts[0]
+		} else {
+			p = name + " " + p
+		}
+
+		ps = append(ps, p)
+		as = append(as, name)
+	}
+
+	return strings.Join(ps, ", "), strings.Join(as, ", "), results, nil
 }
 
 func generate(out io.Writer, packageName, functionName, baseType string, dynamicTypes ...dynamicType) error {
@@ -73,12 +110,20 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 	for _, dt := range dynamicTypes {
 		parts := strings.SplitN(dt.typ, ".", 2)
 
+		params, args, results, err := parseSignature(dt.signature)
+		if err != nil {
+			return err
+		}
+
 		types[dt.typ] = typeStruct{
 			Type:      dt.typ,
 			ShortType: parts[1],
 			VarName:   strings.ToLower(parts[1][:1]) + parts[1][1:],
 			Signature: dt.signature,
 			Function:  dt.function,
+			Params:    params,
+			Args:      args,
+			Results:   results,
 		}
 
 		combos = append(combos, dt.typ)
@@ -0,0 +1,41 @@
+package consumer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+type consumerRegistry map[string]func(map[string]interface{}) (api.Consumer, error)
+
+func (r consumerRegistry) Add(name string, factory func(map[string]interface{}) (api.Consumer, error)) {
+	if _, exists := r[name]; exists {
+		panic(fmt.Sprintf("cannot register duplicate consumer type: %s", name))
+	}
+	r[name] = factory
+}
+
+func (r consumerRegistry) Get(name string) (func(map[string]interface{}) (api.Consumer, error), error) {
+	factory, exists := r[name]
+	if !exists {
+		return nil, fmt.Errorf("consumer type not registered: %s", name)
+	}
+	return factory, nil
+}
+
+var registry consumerRegistry = make(map[string]func(map[string]interface{}) (api.Consumer, error))
+
+// NewFromConfig creates consumer from configuration
+func NewFromConfig(typ string, other map[string]interface{}) (v api.Consumer, err error) {
+	factory, err := registry.Get(strings.ToLower(typ))
+	if err == nil {
+		if v, err = factory(other); err != nil {
+			err = fmt.Errorf("cannot create consumer '%s': %w", typ, err)
+		}
+	} else {
+		err = fmt.Errorf("invalid consumer type: %s", typ)
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSGReadyContacts(t *testing.T) {
+	var contacts [2]bool
+
+	setState := sgReadyContactSetter(
+		func(b bool) error { contacts[0] = b; return nil },
+		func(b bool) error { contacts[1] = b; return nil },
+	)
+
+	c := NewSGReady(setState, 1500, true)
+	assert.Equal(t, []float64{0, 1500, 1500}, c.Levels())
+
+	tc := []struct {
+		level    int
+		contacts [2]bool
+	}{
+		{0, [2]bool{false, false}},
+		{1, [2]bool{false, true}},
+		{2, [2]bool{true, true}},
+	}
+
+	for _, tc := range tc {
+		require.NoError(t, c.SetLevel(tc.level))
+		assert.Equal(t, tc.contacts, contacts, "level %d", tc.level)
+	}
+
+	assert.Error(t, c.SetLevel(3))
+}
+
+func TestSGReadyState(t *testing.T) {
+	var state int64
+
+	c := NewSGReady(func(s int64) error { state = s; return nil }, 2000, false)
+	assert.Equal(t, []float64{0, 2000}, c.Levels())
+
+	require.NoError(t, c.SetLevel(1))
+	assert.Equal(t, int64(SGReadyBoost), state)
+
+	require.NoError(t, c.SetLevel(0))
+	assert.Equal(t, int64(SGReadyNormal), state)
+
+	assert.Error(t, c.SetLevel(2))
+}
+
+func TestHeatingRodRelays(t *testing.T) {
+	relays := make([]bool, 3)
+
+	var setters []func(bool) error
+	for i := range relays {
+		i := i
+		setters = append(setters, func(b bool) error { relays[i] = b; return nil })
+	}
+
+	c := NewHeatingRod(relayLevelSetter(setters), []float64{1000, 2000, 3000})
+	assert.Equal(t, []float64{0, 1000, 2000, 3000}, c.Levels())
+
+	require.NoError(t, c.SetLevel(2))
+	assert.Equal(t, []bool{true, true, false}, relays)
+
+	require.NoError(t, c.SetLevel(0))
+	assert.Equal(t, []bool{false, false, false}, relays)
+
+	assert.Error(t, c.SetLevel(4))
+}
+
+func TestSocket(t *testing.T) {
+	var enabled bool
+
+	c := NewSocket(func(b bool) error { enabled = b; return nil }, 500)
+	assert.Equal(t, []float64{0, 500}, c.Levels())
+
+	require.NoError(t, c.SetLevel(1))
+	assert.True(t, enabled)
+
+	require.NoError(t, c.SetLevel(0))
+	assert.False(t, enabled)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	_, err := NewFromConfig("foo", nil)
+	assert.Error(t, err)
+
+	_, err = NewFromConfig("heatingrod", map[string]interface{}{"power": []float64{1000}})
+	assert.Error(t, err, "missing level or relays")
+}
@@ -0,0 +1,104 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// HeatingRod controls a heating rod with power steps, either by setting the step
+// or by switching one relay per step
+type HeatingRod struct {
+	setLevel func(int64) error
+	levels   []float64
+}
+
+func init() {
+	registry.Add("heatingrod", NewHeatingRodFromConfig)
+}
+
+// NewHeatingRodFromConfig creates a heating rod from generic config
+func NewHeatingRodFromConfig(other map[string]interface{}) (api.Consumer, error) {
+	var cc struct {
+		Power  []float64 // total power per step
+		Level  *provider.Config
+		Relays []provider.Config
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if len(cc.Power) == 0 {
+		return nil, errors.New("missing power")
+	}
+
+	var setLevel func(int64) error
+
+	switch {
+	case cc.Level != nil:
+		var err error
+		if setLevel, err = provider.NewIntSetterFromConfig("level", *cc.Level); err != nil {
+			return nil, fmt.Errorf("level: %w", err)
+		}
+
+	case len(cc.Relays) > 0:
+		if len(cc.Relays) != len(cc.Power) {
+			return nil, errors.New("need one relay per power step")
+		}
+
+		var relays []func(bool) error
+		for i, r := range cc.Relays {
+			relay, err := provider.NewBoolSetterFromConfig("enable", r)
+			if err != nil {
+				return nil, fmt.Errorf("relays[%d]: %w", i, err)
+			}
+
+			relays = append(relays, relay)
+		}
+
+		setLevel = relayLevelSetter(relays)
+
+	default:
+		return nil, errors.New("missing either level or relays")
+	}
+
+	return NewHeatingRod(setLevel, cc.Power), nil
+}
+
+// relayLevelSetter enables the relays up to the given level and disables the remaining ones
+func relayLevelSetter(relays []func(bool) error) func(int64) error {
+	return func(level int64) error {
+		for i, relay := range relays {
+			if err := relay(int64(i) < level); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NewHeatingRod creates a heating rod
+func NewHeatingRod(setLevel func(int64) error, power []float64) *HeatingRod {
+	return &HeatingRod{
+		setLevel: setLevel,
+		levels:   append([]float64{0}, power...),
+	}
+}
+
+// Levels implements the api.Consumer interface
+func (c *HeatingRod) Levels() []float64 {
+	return c.levels
+}
+
+// SetLevel implements the api.Consumer interface
+func (c *HeatingRod) SetLevel(level int) error {
+	if level < 0 || level >= len(c.levels) {
+		return fmt.Errorf("invalid level: %d", level)
+	}
+
+	return c.setLevel(int64(level))
+}
@@ -0,0 +1,94 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Socket controls a switch socket, either using a switch plugin or an existing socket charger implementation
+type Socket struct {
+	enable func(bool) error
+	power  float64
+}
+
+func init() {
+	registry.Add("socket", NewSocketFromConfig)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateSocket -b *Socket -r api.Consumer -t "api.Meter,CurrentPower,func() (float64, error)"
+
+// NewSocketFromConfig creates a switch socket from generic config
+func NewSocketFromConfig(other map[string]interface{}) (api.Consumer, error) {
+	var cc struct {
+		Power   float64 // nominal power
+		Enable  *provider.Config
+		Charger *struct {
+			Type  string
+			Other map[string]interface{} `mapstructure:",remain"`
+		}
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Power <= 0 {
+		return nil, errors.New("missing power")
+	}
+
+	switch {
+	case cc.Enable != nil:
+		enable, err := provider.NewBoolSetterFromConfig("enable", *cc.Enable)
+		if err != nil {
+			return nil, fmt.Errorf("enable: %w", err)
+		}
+
+		return NewSocket(enable, cc.Power), nil
+
+	case cc.Charger != nil:
+		ch, err := charger.NewFromConfig(cc.Charger.Type, cc.Charger.Other)
+		if err != nil {
+			return nil, err
+		}
+
+		c := NewSocket(ch.Enable, cc.Power)
+
+		// measured power of the socket
+		var currentPower func() (float64, error)
+		if m, ok := ch.(api.Meter); ok {
+			currentPower = m.CurrentPower
+		}
+
+		return decorateSocket(c, currentPower), nil
+
+	default:
+		return nil, errors.New("missing either enable or charger")
+	}
+}
+
+// NewSocket creates a switch socket
+func NewSocket(enable func(bool) error, power float64) *Socket {
+	return &Socket{
+		enable: enable,
+		power:  power,
+	}
+}
+
+// Levels implements the api.Consumer interface
+func (c *Socket) Levels() []float64 {
+	return []float64{0, c.power}
+}
+
+// SetLevel implements the api.Consumer interface
+func (c *Socket) SetLevel(level int) error {
+	if level < 0 || level > 1 {
+		return fmt.Errorf("invalid level: %d", level)
+	}
+
+	return c.enable(level == 1)
+}
@@ -0,0 +1,35 @@
+package consumer
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateSocket(base *Socket, meter func() (float64, error)) api.Consumer {
+	switch {
+	case meter == nil:
+		return base
+
+	case meter != nil:
+		return &struct {
+			*Socket
+			api.Meter
+		}{
+			Socket: base,
+			Meter: &decorateSocketMeterImpl{
+				meter: meter,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateSocketMeterImpl struct {
+	meter func() (float64, error)
+}
+
+func (impl *decorateSocketMeterImpl) CurrentPower() (float64, error) {
+	return impl.meter()
+}
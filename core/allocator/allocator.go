@@ -0,0 +1,194 @@
+package allocator
+
+import (
+	"math"
+	"sort"
+)
+
+// Allocation reasons
+const (
+	ReasonShare        = "share"        // fair share among loadpoints of equal priority
+	ReasonMinPower     = "minpower"     // raised to minimum charge power
+	ReasonMaxPower     = "maxpower"     // limited to maximum charge power
+	ReasonInsufficient = "insufficient" // remaining surplus below minimum charge power
+	ReasonPriority     = "priority"     // surplus used by loadpoints of higher priority
+)
+
+// Demand describes a loadpoint's power demand
+type Demand struct {
+	Priority int     // higher priority is served first
+	MinPower float64 // minimum charge power (W)
+	MaxPower float64 // maximum charge power (W)
+	Required bool    // minimum power is required regardless of surplus (min+pv mode)
+	Charging bool    // currently charging, preferred when surplus is insufficient for all
+}
+
+// Allocation is the share of available power assigned to a loadpoint
+type Allocation struct {
+	Power  float64 `json:"power"`
+	Reason string  `json:"reason"`
+}
+
+// Allocate splits the available power across the demands. Demands are served by descending priority,
+// equal priorities share the power equally within their minimum and maximum power.
+// If the power is insufficient to serve all demands of a priority at minimum power, currently charging
+// demands are preferred and the remainder is assigned to the first demand that could not be served.
+func Allocate(available float64, demands []Demand) []Allocation {
+	res := make([]Allocation, len(demands))
+
+	// reserve required minimum power
+	for i, d := range demands {
+		if d.Required {
+			res[i] = Allocation{Power: d.MinPower, Reason: ReasonMinPower}
+			available -= d.MinPower
+		}
+	}
+
+	// group by descending priority, keeping configuration order
+	order := make([]int, len(demands))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return demands[order[i]].Priority > demands[order[j]].Priority
+	})
+
+	var remainderAssigned bool
+
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && demands[order[end]].Priority == demands[order[start]].Priority {
+			end++
+		}
+
+		group := make([]int, end-start)
+		copy(group, order[start:end])
+		start = end
+
+		if remainderAssigned {
+			for _, i := range group {
+				if !demands[i].Required {
+					res[i].Reason = ReasonPriority
+				}
+			}
+			continue
+		}
+
+		// prefer charging demands when selecting which demands can be served
+		sort.SliceStable(group, func(i, j int) bool {
+			return demands[group[i]].Charging && !demands[group[j]].Charging
+		})
+
+		var served []int
+		var minTotal float64
+		for _, i := range group {
+			d := demands[i]
+
+			// required minimum power is already reserved
+			if !d.Required {
+				if minTotal+d.MinPower > available {
+					continue
+				}
+				minTotal += d.MinPower
+			}
+
+			served = append(served, i)
+		}
+
+		used := share(available, demands, served, res)
+		available -= used
+
+		// assign the remainder to the first demand that could not be served
+		for _, i := range group {
+			if contains(served, i) {
+				continue
+			}
+
+			if !remainderAssigned {
+				res[i] = Allocation{Power: available, Reason: ReasonInsufficient}
+				available = 0
+				remainderAssigned = true
+			} else {
+				res[i].Reason = ReasonInsufficient
+			}
+		}
+	}
+
+	return res
+}
+
+// share splits the available power equally across the served demands within their power limits
+// and returns the power used
+func share(available float64, demands []Demand, served []int, res []Allocation) float64 {
+	if len(served) == 0 {
+		return 0
+	}
+
+	// current allocation of required demands is the lower bound
+	bounds := func(i int) (float64, float64) {
+		d := demands[i]
+		lower := d.MinPower - res[i].Power
+		if d.Required {
+			lower = 0
+		}
+		return lower, math.Max(lower, d.MaxPower-res[i].Power)
+	}
+
+	total := func(level float64) float64 {
+		var sum float64
+		for _, i := range served {
+			lower, upper := bounds(i)
+			sum += math.Min(math.Max(level, lower), upper)
+		}
+		return sum
+	}
+
+	// find the share level matching the available power by bisection
+	var lo, hi float64
+	for _, i := range served {
+		if _, upper := bounds(i); upper > hi {
+			hi = upper
+		}
+	}
+
+	level := hi
+	if total(hi) > available {
+		for n := 0; n < 64; n++ {
+			level = (lo + hi) / 2
+			if total(level) > available {
+				hi = level
+			} else {
+				lo = level
+			}
+		}
+		level = lo
+	}
+
+	var used float64
+	for _, i := range served {
+		lower, upper := bounds(i)
+		power := math.Min(math.Max(level, lower), upper)
+
+		reason := ReasonShare
+		switch {
+		case power >= upper:
+			reason = ReasonMaxPower
+		case power <= lower && (lower > 0 || demands[i].Required):
+			reason = ReasonMinPower
+		}
+
+		res[i] = Allocation{Power: res[i].Power + power, Reason: reason}
+		used += power
+	}
+
+	return used
+}
+
+func contains(list []int, v int) bool {
+	for _, i := range list {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package allocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocate(t *testing.T) {
+	// 1p 6-16A and 3p 6-16A loadpoints
+	lp1 := Demand{MinPower: 1380, MaxPower: 3680}
+	lp3 := Demand{MinPower: 4140, MaxPower: 11040}
+
+	tc := []struct {
+		desc      string
+		available float64
+		demands   []Demand
+		res       []Allocation
+	}{
+		{"single loadpoint receives all", 5000, []Demand{lp3}, []Allocation{
+			{5000, ReasonShare},
+		}},
+		{"single loadpoint below minimum keeps remainder", 1000, []Demand{lp3}, []Allocation{
+			{1000, ReasonInsufficient},
+		}},
+		{"single loadpoint importing keeps remainder", -500, []Demand{lp1}, []Allocation{
+			{-500, ReasonInsufficient},
+		}},
+		{"single loadpoint at max", 12000, []Demand{lp3}, []Allocation{
+			{11040, ReasonMaxPower},
+		}},
+		{"fair share", 10000, []Demand{lp3, lp3}, []Allocation{
+			{5000, ReasonShare},
+			{5000, ReasonShare},
+		}},
+		{"fair share respects minimum", 6000, []Demand{lp1, lp3}, []Allocation{
+			{1860, ReasonShare},
+			{4140, ReasonMinPower},
+		}},
+		{"fair share respects maximum", 10000, []Demand{lp1, lp3}, []Allocation{
+			{3680, ReasonMaxPower},
+			{6320, ReasonShare},
+		}},
+		{"insufficient for both, first served", 5000, []Demand{lp3, lp3}, []Allocation{
+			{5000, ReasonShare},
+			{0, ReasonInsufficient},
+		}},
+		{"insufficient for both, charging preferred", 5000, []Demand{lp3, {MinPower: 4140, MaxPower: 11040, Charging: true}}, []Allocation{
+			{0, ReasonInsufficient},
+			{5000, ReasonShare},
+		}},
+		{"priority served first", 6000, []Demand{lp3, {MinPower: 4140, MaxPower: 11040, Priority: 1}}, []Allocation{
+			{0, ReasonInsufficient},
+			{6000, ReasonShare},
+		}},
+		{"priority surplus passed down", 14000, []Demand{lp3, {MinPower: 4140, MaxPower: 11040, Priority: 1}}, []Allocation{
+			{2960, ReasonInsufficient},
+			{11040, ReasonMaxPower},
+		}},
+		{"lower priority after remainder", 3000, []Demand{lp3, {MinPower: 4140, MaxPower: 11040, Priority: 1}, {MinPower: 1380, MaxPower: 3680, Priority: -1}}, []Allocation{
+			{0, ReasonPriority},
+			{3000, ReasonInsufficient},
+			{0, ReasonPriority},
+		}},
+		{"required minimum reserved", 2000, []Demand{lp3, {MinPower: 1380, MaxPower: 3680, Required: true}}, []Allocation{
+			{0, ReasonInsufficient},
+			{2000, ReasonShare},
+		}},
+		{"required minimum while importing", -1000, []Demand{{MinPower: 1380, MaxPower: 3680, Required: true}}, []Allocation{
+			{1380, ReasonMinPower},
+		}},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.desc)
+
+		res := Allocate(tc.available, tc.demands)
+		assert.Len(t, res, len(tc.res))
+
+		for i := range res {
+			assert.InDelta(t, tc.res[i].Power, res[i].Power, 0.01, "%s: power %d", tc.desc, i)
+			assert.Equal(t, tc.res[i].Reason, res[i].Reason, "%s: reason %d", tc.desc, i)
+		}
+	}
+}
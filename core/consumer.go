@@ -0,0 +1,246 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/consumer"
+	"github.com/evcc-io/evcc/core/allocator"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Consumer controls a non-vehicle load like a heat pump, heating rod or switch socket.
+// Depending on its mode the consumer is switched off, follows the pv surplus or runs at its highest level.
+type Consumer struct {
+	clock clock.Clock // mockable time
+	log   *util.Logger
+
+	sync.Mutex                // guard status
+	Mode       api.ChargeMode `mapstructure:"mode"` // Consumer mode off/pv/now, guarded by mutex
+
+	Title         string        `mapstructure:"title"`         // UI title
+	MeterRef      string        `mapstructure:"meter"`         // Consumer meter reference
+	Priority      int           `mapstructure:"priority"`      // PV mode: surplus is allocated to consumers and loadpoints of higher priority first
+	GuardDuration time.Duration `mapstructure:"guardDuration"` // minimum time between level changes
+	Device        struct {
+		Type  string
+		Other map[string]interface{} `mapstructure:",remain"`
+	} `mapstructure:"device"` // Consumer device
+
+	device api.Consumer
+	meter  api.Meter
+
+	level     int       // current level
+	power     float64   // current power
+	allocated float64   // allocated pv surplus
+	switched  time.Time // last level change
+}
+
+// ConsumerStatus is the published consumer state
+type ConsumerStatus struct {
+	Title    string         `json:"title"`
+	Mode     api.ChargeMode `json:"mode"`
+	Priority int            `json:"priority"`
+	Level    int            `json:"level"`
+	Power    float64        `json:"power"`
+}
+
+// NewConsumerFromConfig creates a new consumer
+func NewConsumerFromConfig(log *util.Logger, cp configProvider, other map[string]interface{}) (*Consumer, error) {
+	c := NewConsumer(log)
+	if err := util.DecodeOther(other, c); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateMode(c.Mode); err != nil {
+		return nil, err
+	}
+
+	if c.Device.Type == "" {
+		return nil, errors.New("missing device")
+	}
+
+	var err error
+	if c.device, err = consumer.NewFromConfig(c.Device.Type, c.Device.Other); err != nil {
+		return nil, err
+	}
+
+	if len(c.device.Levels()) < 2 {
+		return nil, errors.New("device must provide at least one active level")
+	}
+
+	if c.MeterRef != "" {
+		if c.meter, err = cp.Meter(c.MeterRef); err != nil {
+			return nil, err
+		}
+	} else if m, ok := c.device.(api.Meter); ok {
+		c.meter = m
+	}
+
+	return c, nil
+}
+
+// NewConsumer creates a Consumer with sane defaults
+func NewConsumer(log *util.Logger) *Consumer {
+	return &Consumer{
+		clock:         clock.New(),
+		log:           log,
+		Mode:          api.ModeOff,
+		GuardDuration: 5 * time.Minute,
+	}
+}
+
+// validateMode checks if mode is supported by consumers
+func (c *Consumer) validateMode(mode api.ChargeMode) error {
+	switch mode {
+	case api.ModeOff, api.ModePV, api.ModeNow:
+		return nil
+	default:
+		return fmt.Errorf("invalid consumer mode: %s", mode)
+	}
+}
+
+// GetTitle returns the consumer title
+func (c *Consumer) GetTitle() string {
+	return c.Title
+}
+
+// GetMode returns the consumer mode
+func (c *Consumer) GetMode() api.ChargeMode {
+	c.Lock()
+	defer c.Unlock()
+	return c.Mode
+}
+
+// SetMode sets the consumer mode
+func (c *Consumer) SetMode(mode api.ChargeMode) error {
+	if err := c.validateMode(mode); err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.Mode != mode {
+		c.log.INFO.Printf("set mode: %s", mode)
+		c.Mode = mode
+	}
+
+	return nil
+}
+
+// status returns the published consumer state
+func (c *Consumer) status() ConsumerStatus {
+	return ConsumerStatus{
+		Title:    c.Title,
+		Mode:     c.GetMode(),
+		Priority: c.Priority,
+		Level:    c.level,
+		Power:    c.power,
+	}
+}
+
+// UpdatePower updates the consumer's power from meter or nominal level power
+func (c *Consumer) UpdatePower() {
+	if c.meter == nil {
+		c.power = c.device.Levels()[c.level]
+		return
+	}
+
+	power, err := c.meter.CurrentPower()
+	if err != nil {
+		c.log.ERROR.Printf("consumer meter: %v", err)
+		return
+	}
+
+	c.power = power
+}
+
+// GetPower returns the consumer's current power
+func (c *Consumer) GetPower() float64 {
+	return c.power
+}
+
+// powerDemand returns the consumer's pv surplus demand for allocation
+func (c *Consumer) powerDemand() allocator.Demand {
+	levels := c.device.Levels()
+
+	d := allocator.Demand{
+		Priority: c.Priority,
+		Charging: c.level > 0,
+	}
+
+	for _, power := range levels[1:] {
+		if d.MinPower == 0 || power < d.MinPower {
+			d.MinPower = power
+		}
+		if power > d.MaxPower {
+			d.MaxPower = power
+		}
+	}
+
+	return d
+}
+
+// targetLevel returns the level depending on mode and allocated pv surplus
+func (c *Consumer) targetLevel(mode api.ChargeMode, cheap bool) int {
+	levels := c.device.Levels()
+
+	switch {
+	case mode == api.ModeOff:
+		return 0
+
+	case mode == api.ModeNow, cheap:
+		return len(levels) - 1
+	}
+
+	// highest power level covered by the surplus, lowest level if equal
+	var level int
+	for i, power := range levels {
+		if power <= c.allocated && power > levels[level] {
+			level = i
+		}
+	}
+
+	return level
+}
+
+// Update sets the consumer level according to mode and allocated pv surplus
+func (c *Consumer) Update(cheap bool) {
+	mode := c.GetMode()
+
+	level := c.targetLevel(mode, cheap)
+	if level == c.level {
+		return
+	}
+
+	// pv mode level changes are limited by guard duration
+	if mode == api.ModePV && !cheap && !c.switched.IsZero() {
+		if remaining := c.GuardDuration - c.clock.Since(c.switched); remaining > 0 {
+			c.log.DEBUG.Printf("level change from %d to %d delayed by guard: %v", c.level, level, remaining.Round(time.Second))
+			return
+		}
+	}
+
+	if err := c.setLevel(level); err != nil {
+		c.log.ERROR.Printf("set level %d: %v", level, err)
+	}
+}
+
+// setLevel switches the device to the given level
+func (c *Consumer) setLevel(level int) error {
+	if err := c.device.SetLevel(level); err != nil {
+		return err
+	}
+
+	c.log.DEBUG.Printf("set level: %d (%.0fW)", level, c.device.Levels()[level])
+
+	c.level = level
+	c.switched = c.clock.Now()
+
+	return nil
+}
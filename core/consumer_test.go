@@ -0,0 +1,134 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/allocator"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConsumerDevice struct {
+	levels []float64
+	level  int
+}
+
+func (d *testConsumerDevice) Levels() []float64 {
+	return d.levels
+}
+
+func (d *testConsumerDevice) SetLevel(level int) error {
+	d.level = level
+	return nil
+}
+
+func newTestConsumer(clck clock.Clock, mode api.ChargeMode, levels ...float64) (*Consumer, *testConsumerDevice) {
+	dev := &testConsumerDevice{levels: append([]float64{0}, levels...)}
+
+	c := NewConsumer(util.NewLogger("foo"))
+	c.clock = clck
+	c.Mode = mode
+	c.device = dev
+
+	return c, dev
+}
+
+func TestConsumerModes(t *testing.T) {
+	clck := clock.NewMock()
+	c, dev := newTestConsumer(clck, api.ModeNow, 1000, 2000, 3000)
+
+	c.Update(false)
+	assert.Equal(t, 3, dev.level)
+
+	// off is not delayed by guard
+	assert.NoError(t, c.SetMode(api.ModeOff))
+	c.Update(true)
+	assert.Equal(t, 0, dev.level)
+
+	// cheap tariff runs at highest level in pv mode
+	assert.NoError(t, c.SetMode(api.ModePV))
+	c.Update(true)
+	assert.Equal(t, 3, dev.level)
+
+	assert.Error(t, c.SetMode(api.ModeMinPV))
+}
+
+func TestConsumerPV(t *testing.T) {
+	clck := clock.NewMock()
+	c, dev := newTestConsumer(clck, api.ModePV, 1000, 2000, 3000)
+
+	c.allocated = 2500
+	c.Update(false)
+	assert.Equal(t, 2, dev.level)
+
+	// guard delays level changes
+	c.allocated = 3200
+	clck.Add(time.Minute)
+	c.Update(false)
+	assert.Equal(t, 2, dev.level)
+
+	clck.Add(c.GuardDuration)
+	c.Update(false)
+	assert.Equal(t, 3, dev.level)
+
+	// insufficient surplus
+	c.allocated = 500
+	clck.Add(c.GuardDuration)
+	c.Update(false)
+	assert.Equal(t, 0, dev.level)
+}
+
+func TestConsumerPowerDemand(t *testing.T) {
+	c, _ := newTestConsumer(clock.NewMock(), api.ModePV, 1500, 1500)
+	c.Priority = 2
+
+	assert.Equal(t, allocator.Demand{Priority: 2, MinPower: 1500, MaxPower: 1500}, c.powerDemand())
+}
+
+func TestSiteAllocatesConsumers(t *testing.T) {
+	clck := clock.NewMock()
+	c, dev := newTestConsumer(clck, api.ModePV, 1000, 2000)
+	c.Priority = 1
+
+	site := &Site{
+		log:       util.NewLogger("foo"),
+		consumers: []*Consumer{c},
+	}
+
+	// running at 1kW with 1.5kW export
+	assert.NoError(t, c.setLevel(1))
+	c.UpdatePower()
+	clck.Add(c.GuardDuration)
+
+	site.allocatePower(-1500, false)
+	assert.Equal(t, 2000.0, c.allocated) // limited to maximum level
+
+	site.updateConsumers(false)
+	assert.Equal(t, 2, dev.level)
+}
+
+func TestSiteAllocationSkipsGridCharging(t *testing.T) {
+	clck := clock.NewMock()
+	c, _ := newTestConsumer(clck, api.ModePV, 1000, 2000, 3000, 4000, 5000)
+
+	lp := &LoadPoint{
+		log:         util.NewLogger("foo"),
+		Mode:        api.ModePV,
+		status:      api.StatusC,
+		chargePower: 3000,
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: []*LoadPoint{lp},
+		consumers:  []*Consumer{c},
+	}
+
+	// loadpoint charging 3kW from grid at cheap tariff doesn't add to the 1.5kW export
+	res := site.allocatePower(-1500, true)
+	assert.Equal(t, 1500.0, c.allocated)
+	assert.NotContains(t, res, lp)
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Filter selects sessions by loadpoint, vehicle, identifier, creation time and charged energy
+type Filter struct {
+	Loadpoint  string
+	Vehicle    string
+	Identifier string
+	From, To   time.Time // session created within [From, To)
+	MinEnergy  float64   // minimum charged energy in kWh, 0 for all sessions
+	Page       int       // 1-based page number, requires PageSize
+	PageSize   int       // number of sessions per page, 0 for unlimited
+}
+
+// Apply adds the filter's conditions to the query
+func (f Filter) Apply(txn *gorm.DB) *gorm.DB {
+	if f.Loadpoint != "" {
+		txn = txn.Where("loadpoint = ?", f.Loadpoint)
+	}
+	if f.Vehicle != "" {
+		txn = txn.Where("vehicle = ?", f.Vehicle)
+	}
+	if f.Identifier != "" {
+		txn = txn.Where("identifier = ?", f.Identifier)
+	}
+	if !f.From.IsZero() {
+		txn = txn.Where("created >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		txn = txn.Where("created < ?", f.To)
+	}
+	if f.MinEnergy > 0 {
+		txn = txn.Where("charged_kwh >= ?", f.MinEnergy)
+	}
+
+	if f.PageSize > 0 {
+		txn = txn.Limit(f.PageSize)
+		if f.Page > 1 {
+			txn = txn.Offset((f.Page - 1) * f.PageSize)
+		}
+	}
+
+	return txn
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFilter(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), new(gorm.Config))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Session)))
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	for i, s := range []Session{
+		{Loadpoint: "garage", Vehicle: "zoe", ChargedEnergy: 10},
+		{Loadpoint: "garage", Vehicle: "model3", Identifier: "rfid", ChargedEnergy: 20},
+		{Loadpoint: "carport", Vehicle: "zoe", ChargedEnergy: 30},
+		{Loadpoint: "carport", Vehicle: "zoe", ChargedEnergy: 40},
+	} {
+		s.Created = start.AddDate(0, 0, 10*i)
+		require.NoError(t, db.Create(&s).Error)
+	}
+
+	tc := []struct {
+		filter Filter
+		res    []float64
+	}{
+		{Filter{}, []float64{10, 20, 30, 40}},
+		{Filter{Loadpoint: "garage"}, []float64{10, 20}},
+		{Filter{Vehicle: "zoe"}, []float64{10, 30, 40}},
+		{Filter{Identifier: "rfid"}, []float64{20}},
+		{Filter{From: start.AddDate(0, 0, 10), To: start.AddDate(0, 0, 30)}, []float64{20, 30}},
+		{Filter{MinEnergy: 25}, []float64{30, 40}},
+		{Filter{PageSize: 3}, []float64{10, 20, 30}},
+		{Filter{Page: 2, PageSize: 3}, []float64{40}},
+	}
+
+	for _, tc := range tc {
+		var res Sessions
+		require.NoError(t, tc.filter.Apply(db).Order("created").Find(&res).Error)
+
+		var energy []float64
+		for _, s := range res {
+			energy = append(energy, s.ChargedEnergy)
+		}
+
+		assert.Equal(t, tc.res, energy, "%+v", tc.filter)
+	}
+}
+
+func TestTotals(t *testing.T) {
+	jan := time.Date(2023, 1, 15, 12, 0, 0, 0, time.Local)
+	feb := jan.AddDate(0, 1, 0)
+
+	res := Sessions{
+		{Created: jan, Loadpoint: "garage", Vehicle: "zoe", ChargedEnergy: 10, Price: 3},
+		{Created: jan, Loadpoint: "garage", Vehicle: "zoe", ChargedEnergy: 5, Price: 1},
+		{Created: jan, Loadpoint: "garage", Vehicle: "model3", ChargedEnergy: 20},
+		{Created: feb, Loadpoint: "garage", Vehicle: "zoe", ChargedEnergy: 7},
+	}.Totals()
+
+	assert.Equal(t, []Total{
+		{Month: "2023-01", Loadpoint: "garage", Vehicle: "zoe", Sessions: 2, ChargedEnergy: 15, Price: 4},
+		{Month: "2023-01", Loadpoint: "garage", Vehicle: "model3", Sessions: 1, ChargedEnergy: 20},
+		{Month: "2023-02", Loadpoint: "garage", Vehicle: "zoe", Sessions: 1, ChargedEnergy: 7},
+	}, res)
+}
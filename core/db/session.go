@@ -17,15 +17,42 @@ import (
 
 // Session is a single charging session
 type Session struct {
-	ID            uint      `json:"-" csv:"-" gorm:"primarykey"`
-	Created       time.Time `json:"created"`
-	Finished      time.Time `json:"finished"`
-	Loadpoint     string    `json:"loadpoint"`
-	Identifier    string    `json:"identifier"`
-	Vehicle       string    `json:"vehicle"`
-	MeterStart    float64   `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
-	MeterStop     float64   `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
-	ChargedEnergy float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	ID               uint      `json:"id" csv:"-" gorm:"primarykey"`
+	Created          time.Time `json:"created"`
+	Finished         time.Time `json:"finished"`
+	Loadpoint        string    `json:"loadpoint"`
+	Identifier       string    `json:"identifier"`
+	Vehicle          string    `json:"vehicle"`
+	MeterStart       float64   `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
+	MeterStop        float64   `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
+	ChargedEnergy    float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	DischargedEnergy float64   `json:"dischargedEnergy" csv:"Discharged Energy (kWh)" gorm:"column:discharged_kwh"`
+	CO2              float64   `json:"co2" csv:"CO2 (kg)" gorm:"column:co2_kg"`
+	SolarPercentage  float64   `json:"solarPercentage" csv:"Solar (%)" gorm:"column:solar_percentage"`
+	GridEnergy       float64   `json:"gridEnergy" csv:"Grid Energy (kWh)" gorm:"column:grid_kwh"`
+	PricePerKWh      float64   `json:"pricePerKWh" csv:"Price/kWh" gorm:"column:price_per_kwh"`
+	Price            float64   `json:"price" csv:"Price" gorm:"column:price"`
+	MaxPower         float64   `json:"maxPower" csv:"Max Power (kW)" gorm:"column:max_power_kw"`
+	solarEnergy      float64   // self-produced energy (kWh) for calculating the solar percentage
+}
+
+// AddEnergy attributes grid and self-produced energy in kWh at the given prices to the session
+func (t *Session) AddEnergy(grid, self, gridPrice, feedinPrice float64) {
+	t.GridEnergy += grid
+	t.solarEnergy += self
+	t.Price += grid*gridPrice + self*feedinPrice
+
+	if total := t.GridEnergy + t.solarEnergy; total > 0 {
+		t.SolarPercentage = 100 * t.solarEnergy / total
+		t.PricePerKWh = t.Price / total
+	}
+}
+
+// UpdatePower records the session's peak charge power in W
+func (t *Session) UpdatePower(power float64) {
+	if kw := power / 1e3; kw > t.MaxPower {
+		t.MaxPower = kw
+	}
 }
 
 // Stop stops charging session with end meter reading and due total amount
@@ -40,6 +67,49 @@ func (t *Session) Stop(chargedWh, total float64) {
 // Sessions is a list of sessions
 type Sessions []Session
 
+// Total is the monthly sum of sessions per loadpoint and vehicle
+type Total struct {
+	Month            string  `json:"month"` // YYYY-MM
+	Loadpoint        string  `json:"loadpoint"`
+	Vehicle          string  `json:"vehicle"`
+	Sessions         int     `json:"sessions"`
+	ChargedEnergy    float64 `json:"chargedEnergy"`
+	DischargedEnergy float64 `json:"dischargedEnergy"`
+	GridEnergy       float64 `json:"gridEnergy"`
+	Price            float64 `json:"price"`
+	CO2              float64 `json:"co2"`
+}
+
+// Totals sums the sessions by month, loadpoint and vehicle
+func (t Sessions) Totals() []Total {
+	var res []Total
+
+	idx := make(map[Total]int)
+	for _, s := range t {
+		key := Total{
+			Month:     s.Created.Local().Format("2006-01"),
+			Loadpoint: s.Loadpoint,
+			Vehicle:   s.Vehicle,
+		}
+
+		i, ok := idx[key]
+		if !ok {
+			i = len(res)
+			idx[key] = i
+			res = append(res, key)
+		}
+
+		res[i].Sessions++
+		res[i].ChargedEnergy += s.ChargedEnergy
+		res[i].DischargedEnergy += s.DischargedEnergy
+		res[i].GridEnergy += s.GridEnergy
+		res[i].Price += s.Price
+		res[i].CO2 += s.CO2
+	}
+
+	return res
+}
+
 var _ api.CsvWriter = (*Sessions)(nil)
 
 func (t *Sessions) writeHeader(ctx context.Context, ww *csv.Writer) {
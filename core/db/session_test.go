@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionAddEnergy(t *testing.T) {
+	var s Session
+
+	s.AddEnergy(1, 3, 0.3, 0.1)
+	assert.Equal(t, 1.0, s.GridEnergy)
+	assert.Equal(t, 75.0, s.SolarPercentage)
+	assert.InDelta(t, 0.6, s.Price, 1e-9)
+	assert.InDelta(t, 0.15, s.PricePerKWh, 1e-9)
+
+	s.AddEnergy(4, 0, 0.35, 0.1)
+	assert.Equal(t, 5.0, s.GridEnergy)
+	assert.InDelta(t, 37.5, s.SolarPercentage, 1e-9)
+	assert.InDelta(t, 2.0, s.Price, 1e-9)
+	assert.InDelta(t, 0.25, s.PricePerKWh, 1e-9)
+}
+
+func TestSessionUpdatePower(t *testing.T) {
+	var s Session
+
+	s.UpdatePower(7200)
+	s.UpdatePower(3600)
+	assert.Equal(t, 7.2, s.MaxPower)
+}
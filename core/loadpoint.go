@@ -11,9 +11,11 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/allocator"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/pvcontrol"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/wrapper"
 	"github.com/evcc-io/evcc/provider"
@@ -111,18 +113,25 @@ type LoadPoint struct {
 	MeterRef          string   `mapstructure:"meter"`    // Charge meter reference
 	SoC               SoCConfig
 	Enable, Disable   ThresholdConfig
-	ResetOnDisconnect bool `mapstructure:"resetOnDisconnect"`
+	PVControl         pvcontrol.Config `mapstructure:"pvControl"` // PV mode start/stop strategy
+	ResetOnDisconnect bool             `mapstructure:"resetOnDisconnect"`
 	onDisconnect      api.ActionConfig
 	targetEnergy      int // Target charge energy for dumb vehicles
 
 	MinCurrent    float64       // PV mode: start current	Min+PV mode: min current
 	MaxCurrent    float64       // Max allowed current. Physically ensured by the charger
 	GuardDuration time.Duration // charger enable/disable minimum holding time
+	CO2Threshold  float64       // PV mode: grid carbon intensity (gCO2/kWh) considered as cheap
+	Priority      int           // PV mode: surplus is allocated to loadpoints of higher priority first
+	DischargeSoC  int           // V2H mode: vehicle is not discharged below this SoC
 
 	enabled             bool      // Charger enabled state
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	chargeCurrent       float64   // Charger current limit
+	dischargeCurrent    float64   // Bidirectional charger discharge current
+	gridLimit           float64   // Site grid connection current limit
+	gridLimited         bool      // Site grid connection limit active
 	guardUpdated        time.Time // Charger enabled/disabled timestamp
 	socUpdated          time.Time // SoC updated timestamp (poll: connected)
 	vehicleDetect       time.Time // Vehicle connected timestamp
@@ -137,6 +146,10 @@ type LoadPoint struct {
 	vehicle        api.Vehicle // Currently active vehicle
 	defaultVehicle api.Vehicle // Default vehicle (disables detection)
 	coordinator    coordinator.API
+	pvControl      pvcontrol.Strategy  // PV mode start/stop strategy, nil for enable/disable timers
+	co2            api.CO2Intensity    // Grid carbon intensity
+	solar          api.SolarForecaster // PV production forecast
+	baseLoad       float64             // Expected power of house and other loadpoints not available from pv forecast
 	socEstimator   *soc.Estimator
 	socTimer       *soc.Timer
 
@@ -154,6 +167,8 @@ type LoadPoint struct {
 	vehicleSoc              float64       // Vehicle SoC
 	chargeDuration          time.Duration // Charge duration
 	chargedEnergy           float64       // Charged energy while connected in Wh
+	dischargedEnergy        float64       // Discharged energy while connected in Wh
+	dischargeUpdated        time.Time     // Discharged energy updated timestamp
 	chargeRemainingDuration time.Duration // Remaining charge duration
 	chargeRemainingEnergy   float64       // Remaining charge energy in Wh
 	progress                *Progress     // Step-wise progress indicator
@@ -241,6 +256,10 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 	}
 	lp.configureChargerType(lp.charger)
 
+	if lp.Mode == api.ModeV2H && !lp.bidirectional() {
+		return nil, fmt.Errorf("invalid mode: %s requires bidirectional charger", lp.Mode)
+	}
+
 	// setup fixed phases:
 	// - simple charger starts with phases config if specified or 3p
 	// - switchable charger starts at 0p since we don't know the current setting
@@ -254,6 +273,10 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		lp.log.WARN.Printf("locking phase config to %dp for switchable charger", lp.ConfiguredPhases)
 	}
 
+	if lp.pvControl, err = pvcontrol.NewFromConfig(lp.log, lp.clock, lp.PVControl.Type, lp.PVControl.Other); err != nil {
+		return nil, err
+	}
+
 	// validate thresholds
 	if lp.Enable.Threshold > lp.Disable.Threshold {
 		lp.log.WARN.Printf("PV mode enable threshold (%.0fW) is larger than disable threshold (%.0fW)", lp.Enable.Threshold, lp.Disable.Threshold)
@@ -264,6 +287,22 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 	return lp, nil
 }
 
+// setClock replaces the loadpoint's clock including timers and pv control strategy
+func (lp *LoadPoint) setClock(clock clock.Clock) {
+	lp.clock = clock
+
+	if lp.wakeUpTimer != nil {
+		lp.wakeUpTimer.clck = clock
+	}
+
+	// strategy has been validated during creation
+	lp.pvControl, _ = pvcontrol.NewFromConfig(lp.log, clock, lp.PVControl.Type, lp.PVControl.Other)
+
+	if fs, ok := lp.pvControl.(pvcontrol.ForecastStrategy); ok && lp.solar != nil {
+		fs.SetForecaster(lp.solar)
+	}
+}
+
 // NewLoadPoint creates a LoadPoint with sane defaults
 func NewLoadPoint(log *util.Logger) *LoadPoint {
 	clock := clock.New()
@@ -281,6 +320,7 @@ func NewLoadPoint(log *util.Logger) *LoadPoint {
 		Enable:        ThresholdConfig{Delay: time.Minute, Threshold: 0},     // t, W
 		Disable:       ThresholdConfig{Delay: 3 * time.Minute, Threshold: 0}, // t, W
 		GuardDuration: 5 * time.Minute,
+		DischargeSoC:  50,                     // %
 		progress:      NewProgress(0, 10),     // soc progress indicator
 		coordinator:   coordinator.NewDummy(), // dummy vehicle coordinator
 		tasks:         aq.New(),               // task queue
@@ -414,6 +454,8 @@ func (lp *LoadPoint) evVehicleConnectHandler() {
 	// energy
 	lp.chargedEnergy = 0
 	lp.publish("chargedEnergy", lp.chargedEnergy)
+	lp.dischargedEnergy = 0
+	lp.publish("dischargedEnergy", lp.dischargedEnergy)
 
 	// duration
 	lp.connectedTime = lp.clock.Now()
@@ -548,6 +590,7 @@ func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 	lp.publish("title", lp.Title)
 	lp.publish("minCurrent", lp.MinCurrent)
 	lp.publish("maxCurrent", lp.MaxCurrent)
+	lp.publish("priority", lp.Priority)
 
 	lp.setConfiguredPhases(lp.ConfiguredPhases)
 	lp.publish(phasesEnabled, lp.phases)
@@ -591,7 +634,7 @@ func (lp *LoadPoint) syncCharger() {
 	enabled, err := lp.charger.Enabled()
 	if err == nil {
 		if enabled != lp.enabled {
-			if time.Since(lp.guardUpdated) > guardGracePeriod {
+			if lp.clock.Since(lp.guardUpdated) > guardGracePeriod {
 				lp.log.WARN.Printf("charger out of sync: expected %vd, got %vd", status[lp.enabled], status[enabled])
 			}
 			err = lp.charger.Enable(lp.enabled)
@@ -609,6 +652,13 @@ func (lp *LoadPoint) syncCharger() {
 
 // setLimit applies charger current limits and enables/disables accordingly
 func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
+	// site grid connection limit, disable immediately if below minimum current
+	if lp.gridLimited && chargeCurrent > lp.gridLimit {
+		lp.log.DEBUG.Printf("grid limit: %.3gA", lp.gridLimit)
+		chargeCurrent = lp.gridLimit
+		force = force || chargeCurrent < lp.GetMinCurrent()
+	}
+
 	// set current
 	if chargeCurrent != lp.chargeCurrent && chargeCurrent >= lp.GetMinCurrent() {
 		var err error
@@ -687,6 +737,29 @@ func (lp *LoadPoint) charging() bool {
 	return lp.GetStatus() == api.StatusC
 }
 
+// gridCharging returns true if the loadpoint is charging regardless of pv surplus,
+// i.e. in now mode, below minSoC, for target charging or at cheap tariff
+func (lp *LoadPoint) gridCharging(cheap bool) bool {
+	if !lp.charging() {
+		return false
+	}
+
+	switch lp.GetMode() {
+	case api.ModeNow:
+		return true
+	case api.ModeMinPV, api.ModePV:
+		return cheap || lp.minSocNotReached() || lp.socTimer.Active()
+	default:
+		return false
+	}
+}
+
+// pvGridCharging returns true if the loadpoint in pv modes charges regardless of pv surplus,
+// i.e. below minSoC, for target charging, at cheap tariff or low carbon intensity
+func (lp *LoadPoint) pvGridCharging(cheap bool) bool {
+	return cheap || lp.minSocNotReached() || lp.socTimer.Active() || lp.lowCarbon()
+}
+
 // charging returns the EVs charging state
 func (lp *LoadPoint) setStatus(status api.ChargeStatus) {
 	lp.Lock()
@@ -803,6 +876,8 @@ func (lp *LoadPoint) identifyVehicle() {
 
 		if vehicle := lp.selectVehicleByID(id); vehicle != nil {
 			lp.setActiveVehicle(vehicle)
+		} else {
+			lp.updateSession()
 		}
 	}
 }
@@ -1365,6 +1440,16 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 		return minCurrent
 	}
 
+	// pluggable start/stop strategy
+	if mode == api.ModePV && lp.pvControl != nil {
+		minPower := minCurrent * float64(activePhases) * Voltage
+		if !lp.pvControl.Update(lp.enabled, -sitePower+lp.chargePower, minPower) {
+			return 0
+		}
+
+		return math.Min(math.Max(targetCurrent, minCurrent), maxCurrent)
+	}
+
 	if mode == api.ModePV && lp.enabled && targetCurrent < minCurrent {
 		// kick off disable sequence
 		if sitePower >= lp.Disable.Threshold && lp.phaseTimer.IsZero() {
@@ -1453,7 +1538,7 @@ func (lp *LoadPoint) UpdateChargePower() {
 		lp.publish("chargePower", value)
 
 		// use -1 for https://github.com/evcc-io/evcc/issues/2153
-		if lp.chargePower < -1 {
+		if _, ok := lp.charger.(api.BidirectionalCharger); !ok && lp.chargePower < -1 {
 			lp.log.WARN.Printf("charge power must not be negative: %.0f", lp.chargePower)
 		}
 
@@ -1641,6 +1726,102 @@ func (lp *LoadPoint) processTasks() {
 	}
 }
 
+// phaseLoad returns the loadpoint's current per phase
+func (lp *LoadPoint) phaseLoad() phaseLoad {
+	res := phaseLoad{phases: lp.activePhases()}
+
+	if lp.chargeCurrents != nil {
+		copy(res.currents[:], lp.chargeCurrents)
+	} else {
+		for p := 0; p < res.phases; p++ {
+			res.currents[p] = lp.effectiveCurrent()
+		}
+	}
+
+	return res
+}
+
+// setGridLimit applies the site's grid connection current limit. Exceeding charge current is reduced immediately.
+func (lp *LoadPoint) setGridLimit(current float64) {
+	limited := !math.IsInf(current, 1)
+	if limited == lp.gridLimited && current == lp.gridLimit {
+		return
+	}
+
+	lp.gridLimit = current
+	lp.gridLimited = limited
+
+	if limited {
+		lp.publish("gridLimit", current)
+	} else {
+		lp.publish("gridLimit", nil)
+	}
+
+	if limited && lp.enabled && lp.chargeCurrent > current {
+		if err := lp.setLimit(current, true); err != nil {
+			lp.log.ERROR.Println(err)
+		}
+	}
+}
+
+// powerDemand returns the loadpoint's charge power range for allocating the site's surplus
+func (lp *LoadPoint) powerDemand(required bool) allocator.Demand {
+	minPhases := lp.activePhases()
+	if _, ok := lp.charger.(api.PhaseSwitcher); ok && lp.ConfiguredPhases == 0 {
+		minPhases = 1
+	}
+
+	return allocator.Demand{
+		Priority: lp.Priority,
+		MinPower: lp.GetMinCurrent() * float64(minPhases) * Voltage,
+		MaxPower: lp.GetMaxCurrent() * float64(lp.maxActivePhases()) * Voltage,
+		Required: required,
+		Charging: lp.charging(),
+	}
+}
+
+// lowCarbon returns true if the grid's carbon intensity is below the configured threshold
+func (lp *LoadPoint) lowCarbon() bool {
+	if lp.co2 == nil || lp.CO2Threshold <= 0 {
+		return false
+	}
+
+	co2, err := lp.co2.CurrentCO2Intensity()
+	if err != nil {
+		lp.log.ERROR.Printf("co2: %v", err)
+		return false
+	}
+
+	return co2 <= lp.CO2Threshold
+}
+
+// solarCoversTarget checks if in pv modes the forecasted pv production until target time
+// is expected to provide the energy required for reaching the target soc.
+// The forecast only delays the start of target charging, active target charging is not interrupted.
+func (lp *LoadPoint) solarCoversTarget(mode api.ChargeMode) bool {
+	if lp.solar == nil || lp.socEstimator == nil || lp.socTimer == nil || lp.socTimer.Time.IsZero() ||
+		mode != api.ModeMinPV && mode != api.ModePV || lp.socTimer.Active() {
+		return false
+	}
+
+	forecast, err := lp.solar.SolarForecast()
+	if err != nil {
+		lp.log.DEBUG.Printf("solar forecast: %v", err)
+		return false
+	}
+
+	// pv power used by the base load or beyond the loadpoint's maximum power can't be used for charging
+	required := lp.socEstimator.RemainingChargeEnergy(lp.socTimer.SoC) * 1e3
+	expected := forecast.Reduce(lp.baseLoad).Limit(lp.GetMaxPower()).Energy(lp.clock.Now(), lp.socTimer.Time)
+
+	covered := expected >= required
+	if covered {
+		lp.log.DEBUG.Printf("target charging: solar forecast %.0fWh covers required %.0fWh", expected, required)
+	}
+
+	return covered
+}
+
 // Update is the main control function. It reevaluates meters and charger state
 func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	lp.processTasks()
@@ -1651,6 +1832,9 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	// read and publish meters first- charge power has already been updated by the site
 	lp.updateChargeCurrents()
 
+	// account vehicle-to-home energy
+	lp.updateDischargedEnergy()
+
 	// update ChargeRater here to make sure initial meter update is caught
 	lp.bus.Publish(evChargeCurrent, lp.chargeCurrent)
 	lp.bus.Publish(evChargePower, lp.chargePower)
@@ -1689,6 +1873,9 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	// check if car connected and ready for charging
 	var err error
 
+	// track if vehicle-to-home mode is evaluated
+	var v2h bool
+
 	// track if remote disabled is actually active
 	remoteDisabled := loadpoint.RemoteEnable
 
@@ -1736,14 +1923,18 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 			err = lp.setLimit(lp.GetMaxCurrent(), true)
 		}
 
-	// target charging
-	case lp.socTimer.DemandActive():
+	// target charging unless pv is expected to cover the target
+	case !lp.solarCoversTarget(mode) && lp.socTimer.DemandActive():
 		// 3p if available
 		if err = lp.scalePhasesIfAvailable(3); err == nil {
 			targetCurrent := lp.socTimer.Handle()
 			err = lp.setLimit(targetCurrent, true)
 		}
 
+	case mode == api.ModeV2H:
+		v2h = true
+		err = lp.v2hCurrent(sitePower, batteryBuffered)
+
 	case mode == api.ModeMinPV || mode == api.ModePV:
 		targetCurrent := lp.pvMaxCurrent(mode, sitePower, batteryBuffered)
 
@@ -1759,6 +1950,10 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 			targetCurrent = lp.GetMaxCurrent()
 			lp.log.DEBUG.Printf("cheap tariff: %.3gA", targetCurrent)
 			required = true
+		} else if lp.lowCarbon() {
+			targetCurrent = lp.GetMaxCurrent()
+			lp.log.DEBUG.Printf("low co2 intensity: %.3gA", targetCurrent)
+			required = true
 		}
 
 		// Sunny Home Manager
@@ -1771,6 +1966,11 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		err = lp.setLimit(targetCurrent, required)
 	}
 
+	// resume charging if discharging is no longer evaluated
+	if !v2h && lp.dischargeCurrent > 0 && err == nil {
+		err = lp.setDischargeCurrent(0)
+	}
+
 	// Wake-up checks
 	if lp.enabled && lp.status == api.StatusB &&
 		int(lp.vehicleSoc) < lp.SoC.target && lp.wakeUpTimer.Expired() {
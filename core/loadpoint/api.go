@@ -82,11 +82,17 @@ type API interface {
 	GetRemainingDuration() time.Duration
 	// GetRemainingEnergy is the remaining charge energy in Wh
 	GetRemainingEnergy() float64
+	// GetChargedEnergy is the energy charged in the current session in Wh
+	GetChargedEnergy() float64
+	// GetChargeMeterTotal is the charge meter's total energy in kWh, 0 if not available
+	GetChargeMeterTotal() float64
 
 	//
 	// vehicles
 	//
 
+	// GetVehicleSoC returns the vehicle's soc
+	GetVehicleSoC() float64
 	// SetVehicle sets the active vehicle
 	SetVehicle(vehicle api.Vehicle)
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/core/soc"
 )
 
@@ -17,3 +18,7 @@ func (a *adapter) Publish(key string, val interface{}) {
 func (a *adapter) SocEstimator() *soc.Estimator {
 	return a.LoadPoint.socEstimator
 }
+
+func (a *adapter) Clock() clock.Clock {
+	return a.LoadPoint.clock
+}
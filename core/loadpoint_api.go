@@ -35,6 +35,11 @@ func (lp *LoadPoint) SetMode(mode api.ChargeMode) {
 		return
 	}
 
+	if mode == api.ModeV2H && !lp.bidirectional() {
+		lp.log.ERROR.Printf("invalid charge mode: %s requires bidirectional charger", string(mode))
+		return
+	}
+
 	lp.log.DEBUG.Printf("set charge mode: %s", string(mode))
 
 	// apply immediately
@@ -303,6 +308,25 @@ func (lp *LoadPoint) GetRemainingEnergy() float64 {
 	return lp.chargeRemainingEnergy
 }
 
+// GetChargedEnergy is the energy charged in the current session in Wh
+func (lp *LoadPoint) GetChargedEnergy() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.chargedEnergy
+}
+
+// GetChargeMeterTotal is the charge meter's total energy in kWh, 0 if not available
+func (lp *LoadPoint) GetChargeMeterTotal() float64 {
+	return lp.chargeMeterTotal()
+}
+
+// GetVehicleSoC returns the vehicle's soc
+func (lp *LoadPoint) GetVehicleSoC() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.vehicleSoc
+}
+
 // SetVehicle sets the active vehicle
 func (lp *LoadPoint) SetVehicle(vehicle api.Vehicle) {
 	// TODO develop universal locking approach
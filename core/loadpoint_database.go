@@ -64,12 +64,37 @@ func (lp *LoadPoint) updateSession() {
 		title = lp.vehicle.Title()
 	}
 
-	if lp.session.Vehicle != title {
+	// identification may complete after the session has started
+	if lp.session.Vehicle != title || lp.session.Identifier == "" && lp.vehicleIdentifier != "" {
 		lp.session.Vehicle = title
+		if lp.session.Identifier == "" {
+			lp.session.Identifier = lp.vehicleIdentifier
+		}
 		lp.db.Persist(lp.session)
 	}
 }
 
+// addSessionEnergy attributes grid and self-produced energy in kWh at the given prices to the session
+func (lp *LoadPoint) addSessionEnergy(grid, self, gridPrice, feedinPrice float64) {
+	// test guard
+	if lp.db == nil || lp.session == nil {
+		return
+	}
+
+	lp.session.AddEnergy(grid, self, gridPrice, feedinPrice)
+	lp.session.UpdatePower(lp.GetChargePower())
+}
+
+// addSessionCO2 adds the emissions of grid energy in kWh drawn at given carbon intensity in gCO2/kWh
+func (lp *LoadPoint) addSessionCO2(gridEnergy, intensity float64) {
+	// test guard
+	if lp.db == nil || lp.session == nil {
+		return
+	}
+
+	lp.session.CO2 += gridEnergy * intensity / 1e3
+}
+
 func (lp *LoadPoint) finalizeSession() {
 	// test guard
 	if lp.db == nil {
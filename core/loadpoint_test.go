@@ -7,6 +7,7 @@ import (
 	evbus "github.com/asaskevich/EventBus"
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/pvcontrol"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/mock"
 	"github.com/evcc-io/evcc/push"
@@ -771,3 +772,185 @@ func TestMinSoC(t *testing.T) {
 		}
 	}
 }
+
+type co2Intensity float64
+
+func (c co2Intensity) CurrentCO2Intensity() (float64, error) {
+	return float64(c), nil
+}
+
+func TestLowCarbon(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+
+	tc := []struct {
+		co2       api.CO2Intensity
+		threshold float64
+		res       bool
+	}{
+		{nil, 200, false},
+		{co2Intensity(150), 0, false},
+		{co2Intensity(150), 200, true},
+		{co2Intensity(250), 200, false},
+	}
+
+	for _, tc := range tc {
+		lp.co2 = tc.co2
+		lp.CO2Threshold = tc.threshold
+
+		if res := lp.lowCarbon(); res != tc.res {
+			t.Errorf("%v at threshold %v: expected %v, got %v", tc.co2, tc.threshold, tc.res, res)
+		}
+	}
+}
+
+type solarForecast api.Forecast
+
+func (f solarForecast) SolarForecast() (api.Forecast, error) {
+	return api.Forecast(f), nil
+}
+
+func TestSolarCoversTarget(t *testing.T) {
+	Voltage = 230 // V
+
+	clck := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	vehicle := mock.NewMockVehicle(ctrl)
+
+	vehicle.EXPECT().Capacity().Return(float64(10))
+	vehicle.EXPECT().Phases().Return(0).AnyTimes()
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.phases = 1
+	lp.socEstimator = soc.NewEstimator(util.NewLogger("foo"), nil, vehicle, false)
+	lp.socTimer.SoC = 50
+
+	// 2kW for 3 hours
+	now := clck.Now()
+	forecast := solarForecast{
+		{Start: now, End: now.Add(time.Hour), Power: 2e3},
+		{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Power: 2e3},
+		{Start: now.Add(2 * time.Hour), End: now.Add(3 * time.Hour), Power: 2e3},
+	}
+
+	tc := []struct {
+		solar  api.SolarForecaster
+		mode   api.ChargeMode
+		target time.Duration
+		res    bool
+	}{
+		{nil, api.ModePV, 3 * time.Hour, false},
+		{forecast, api.ModeNow, 3 * time.Hour, false},
+		{forecast, api.ModePV, 0, false},
+		{forecast, api.ModePV, 2 * time.Hour, false},
+		{forecast, api.ModePV, 3 * time.Hour, true},
+		{forecast, api.ModeMinPV, 3 * time.Hour, true},
+	}
+
+	for _, tc := range tc {
+		lp.solar = tc.solar
+		lp.socTimer.Time = time.Time{}
+		if tc.target > 0 {
+			lp.socTimer.Time = now.Add(tc.target)
+		}
+
+		if res := lp.solarCoversTarget(tc.mode); res != tc.res {
+			t.Errorf("%s until %v: expected %v, got %v", tc.mode, tc.target, tc.res, res)
+		}
+	}
+
+	// pv used by the house and other loadpoints does not count
+	lp.solar = forecast
+	lp.socTimer.Time = now.Add(3 * time.Hour)
+	lp.baseLoad = 1e3
+
+	if lp.solarCoversTarget(api.ModePV) {
+		t.Error("expected target not to be covered with 1kW base load")
+	}
+
+	lp.baseLoad = 0
+
+	// pv beyond the loadpoint's maximum power does not count
+	lp.solar = solarForecast{{Start: now, End: now.Add(time.Hour), Power: 20e3}}
+	lp.socTimer.Time = now.Add(time.Hour)
+	lp.MaxCurrent = 16
+
+	if lp.solarCoversTarget(api.ModePV) {
+		t.Error("expected target not to be covered by 1p 16A")
+	}
+
+	// target time is evaluated on the loadpoint's clock
+	lp.socTimer.Time = now.Add(3 * time.Hour)
+	if lp.socTimer.DemandActive() {
+		t.Fatal("expected target charging not to be active yet")
+	}
+
+	// active target charging is not interrupted by the forecast
+	lp.socTimer.Time = now.Add(time.Hour)
+	if !lp.socTimer.DemandActive() {
+		t.Fatal("expected target charging to be active")
+	}
+
+	lp.solar = forecast
+	lp.socTimer.Time = now.Add(3 * time.Hour)
+
+	if lp.solarCoversTarget(api.ModePV) {
+		t.Error("expected active target charging to continue")
+	}
+
+	ctrl.Finish()
+}
+
+func TestPVControlBridgesClouds(t *testing.T) {
+	const phases = 3
+
+	clck := clock.NewMock()
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		clock:          clck,
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         phases,
+		measuredPhases: phases,
+		status:         api.StatusB,
+		pvControl:      pvcontrol.NewAverage(util.NewLogger("foo"), clck, time.Minute, 0, 5*time.Minute, 100),
+	}
+
+	step := func(sitePower float64) float64 {
+		clck.Add(10 * time.Second)
+		return lp.pvMaxCurrent(api.ModePV, sitePower, false)
+	}
+
+	// surplus must be available for the entire window
+	for i := 0; i < 6; i++ {
+		if current := step(-2400); current != 0 {
+			t.Errorf("step %d: expected 0, got %.1f", i, current)
+		}
+	}
+	if current := step(-2400); current != 8 {
+		t.Errorf("expected 8A, got %.1f", current)
+	}
+
+	// start charging at min current, then clouds come in
+	lp.enabled = true
+	lp.status = api.StatusC
+	lp.chargeCurrent = minA
+	lp.chargePower = phases * minA * Voltage
+
+	// short cloud is bridged using grid energy
+	for i := 0; i < 18; i++ {
+		if current := step(lp.chargePower); current != minA {
+			t.Errorf("step %d: expected %.0fA, got %.1f", i, minA, current)
+		}
+	}
+
+	// grid energy budget exceeded
+	for i := 0; i < 3; i++ {
+		step(lp.chargePower)
+	}
+	if current := step(lp.chargePower); current != 0 {
+		t.Errorf("expected 0, got %.1f", current)
+	}
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// bidirectional checks if the charger supports discharging
+func (lp *LoadPoint) bidirectional() bool {
+	_, ok := lp.charger.(api.BidirectionalCharger)
+	return ok
+}
+
+// dischargeable checks if the vehicle may be discharged into the home
+func (lp *LoadPoint) dischargeable() bool {
+	if !lp.bidirectional() {
+		return false
+	}
+
+	// unknown soc is never discharged
+	return lp.vehicleSoc > float64(lp.DischargeSoC)
+}
+
+// setDischargeCurrent sets the bidirectional charger's discharge current, 0 resumes charging
+func (lp *LoadPoint) setDischargeCurrent(current float64) error {
+	if current == lp.dischargeCurrent {
+		return nil
+	}
+
+	charger, ok := lp.charger.(api.BidirectionalCharger)
+	if !ok {
+		return nil
+	}
+
+	if err := charger.Discharge(current); err != nil {
+		return fmt.Errorf("discharge current %.3gA: %w", current, err)
+	}
+
+	lp.log.DEBUG.Printf("discharge current: %.3gA", current)
+	lp.dischargeCurrent = current
+	lp.publish("dischargeCurrent", current)
+
+	return nil
+}
+
+// v2hCurrent covers the home's grid import by discharging the vehicle down to the discharge soc.
+// Without grid import the vehicle is charged from pv surplus like in pv mode.
+func (lp *LoadPoint) v2hCurrent(sitePower float64, batteryBuffered bool) error {
+	minCurrent := lp.GetMinCurrent()
+
+	// grid import to be covered including current discharge
+	targetCurrent := lp.dischargeCurrent + powerToCurrent(sitePower, lp.activePhases())
+
+	// keep discharging or start discharging once pv charging has stopped
+	if lp.dischargeable() && targetCurrent >= minCurrent && (lp.dischargeCurrent > 0 || !lp.enabled) {
+		lp.log.DEBUG.Printf("v2h: discharge %.3gA", targetCurrent)
+
+		// discharging requires the charger to be enabled
+		if err := lp.setLimit(minCurrent, true); err != nil {
+			return err
+		}
+
+		return lp.setDischargeCurrent(math.Min(targetCurrent, lp.GetMaxCurrent()))
+	}
+
+	if lp.dischargeCurrent > 0 {
+		if err := lp.setDischargeCurrent(0); err != nil {
+			return err
+		}
+
+		// start over with pv charging from disabled charger
+		return lp.setLimit(0, true)
+	}
+
+	return lp.setLimit(lp.pvMaxCurrent(api.ModePV, sitePower, batteryBuffered), false)
+}
+
+// updateDischargedEnergy integrates the negative charge power into the discharged energy
+func (lp *LoadPoint) updateDischargedEnergy() {
+	now := lp.clock.Now()
+
+	if power := lp.GetChargePower(); power < 0 && !lp.dischargeUpdated.IsZero() {
+		lp.dischargedEnergy += -power * now.Sub(lp.dischargeUpdated).Hours()
+		lp.publish("dischargedEnergy", lp.dischargedEnergy)
+
+		// test guard
+		if lp.session != nil {
+			lp.session.DischargedEnergy = lp.dischargedEnergy / 1e3
+		}
+	}
+
+	lp.dischargeUpdated = now
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+)
+
+type bidirectionalCharger struct {
+	*mock.MockCharger
+	current float64
+}
+
+func (c *bidirectionalCharger) Discharge(current float64) error {
+	c.current = current
+	return nil
+}
+
+func TestV2HDischarge(t *testing.T) {
+	const phases = 3
+
+	ctrl := gomock.NewController(t)
+	charger := &bidirectionalCharger{MockCharger: mock.NewMockCharger(ctrl)}
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		DischargeSoC:   50,
+		phases:         phases,
+		measuredPhases: phases,
+		status:         api.StatusB,
+		vehicleSoc:     80,
+	}
+
+	// grid import of 9A starts discharging
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+
+	if err := lp.v2hCurrent(phases*9*Voltage, false); err != nil {
+		t.Fatal(err)
+	}
+	if charger.current != 9 {
+		t.Errorf("expected 9A discharge, got %.1f", charger.current)
+	}
+
+	// remaining import increases discharge up to max current
+	if err := lp.v2hCurrent(phases*10*Voltage, false); err != nil {
+		t.Fatal(err)
+	}
+	if charger.current != maxA {
+		t.Errorf("expected %.0fA discharge, got %.1f", maxA, charger.current)
+	}
+
+	// floor soc reached stops discharging
+	lp.vehicleSoc = 50
+	charger.EXPECT().Enable(false).Return(nil)
+
+	if err := lp.v2hCurrent(0, false); err != nil {
+		t.Fatal(err)
+	}
+	if charger.current != 0 || lp.enabled {
+		t.Errorf("expected charger disabled, got %.1fA discharge", charger.current)
+	}
+}
+
+func TestV2HDischargedEnergy(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+	}
+
+	lp.updateDischargedEnergy()
+
+	lp.chargePower = -2000
+	clck.Add(30 * time.Minute)
+	lp.updateDischargedEnergy()
+
+	lp.chargePower = 1000
+	clck.Add(30 * time.Minute)
+	lp.updateDischargedEnergy()
+
+	if lp.dischargedEnergy != 1000 {
+		t.Errorf("expected 1000Wh, got %.0f", lp.dischargedEnergy)
+	}
+}
+
+func TestV2HModeRequiresBidirectionalCharger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		clock:   clock.NewMock(),
+		charger: mock.NewMockCharger(ctrl),
+		Mode:    api.ModePV,
+	}
+
+	lp.SetMode(api.ModeV2H)
+	if lp.Mode != api.ModePV {
+		t.Errorf("expected v2h mode to be rejected, got %s", lp.Mode)
+	}
+
+	lp.charger = &bidirectionalCharger{MockCharger: mock.NewMockCharger(ctrl)}
+
+	lp.SetMode(api.ModeV2H)
+	if lp.Mode != api.ModeV2H {
+		t.Errorf("expected v2h mode, got %s", lp.Mode)
+	}
+}
@@ -0,0 +1,148 @@
+package planner
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// ErrIncomplete indicates that the tariff does not provide rates up to the target time
+var ErrIncomplete = errors.New("rates incomplete")
+
+// Planner plans a series of charging slots for a given (variable) tariff
+type Planner struct {
+	log    *util.Logger
+	clock  clock.Clock // mockable time
+	tariff api.TariffRates
+}
+
+// Slot is a planned charging slot including its expected cost
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Price float64   `json:"price"`
+	Cost  float64   `json:"cost"`
+}
+
+// New creates a price planner
+func New(log *util.Logger, tariff api.TariffRates) *Planner {
+	return &Planner{
+		log:    log,
+		clock:  clock.New(),
+		tariff: tariff,
+	}
+}
+
+// plan selects the cheapest slots from the available rates that add up to the required duration
+func (t *Planner) plan(rates api.Rates, requiredDuration time.Duration, targetTime time.Time) api.Rates {
+	now := t.clock.Now()
+
+	// limit rates to the window between now and target time
+	var slots api.Rates
+	for _, r := range rates {
+		if !r.End.After(now) || !r.Start.Before(targetTime) {
+			continue
+		}
+
+		if r.Start.Before(now) {
+			r.Start = now
+		}
+		if r.End.After(targetTime) {
+			r.End = targetTime
+		}
+
+		slots = append(slots, r)
+	}
+
+	// cheapest first, prefer earlier slots at equal price
+	slots.Sort()
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Price < slots[j].Price
+	})
+
+	var plan api.Rates
+	var partial *api.Rate
+
+	for _, slot := range slots {
+		if requiredDuration <= 0 {
+			break
+		}
+
+		if duration := slot.End.Sub(slot.Start); duration > requiredDuration {
+			slot := slot
+			partial = &slot
+			break
+		}
+
+		plan = append(plan, slot)
+		requiredDuration -= slot.End.Sub(slot.Start)
+	}
+
+	// place partial slot adjacent to the preceding planned slot if possible, otherwise as late as possible
+	if partial != nil {
+		adjacent := partial.Start.Equal(now)
+		for _, slot := range plan {
+			if slot.End.Equal(partial.Start) {
+				adjacent = true
+			}
+		}
+
+		if adjacent {
+			partial.End = partial.Start.Add(requiredDuration)
+		} else {
+			partial.Start = partial.End.Add(-requiredDuration)
+		}
+
+		plan = append(plan, *partial)
+	}
+
+	plan.Sort()
+
+	return plan
+}
+
+// Plan creates a lowest-cost charging plan for the required duration to be finished at target time.
+// Returns ErrIncomplete if the tariff's rates do not extend to the target time.
+func (t *Planner) Plan(requiredDuration time.Duration, targetTime time.Time) (api.Rates, error) {
+	rates, err := t.tariff.Rates()
+	if err != nil {
+		return nil, err
+	}
+
+	var last time.Time
+	for _, r := range rates {
+		if r.End.After(last) {
+			last = r.End
+		}
+	}
+
+	if last.Before(targetTime) {
+		return nil, ErrIncomplete
+	}
+
+	return t.plan(rates, requiredDuration, targetTime), nil
+}
+
+// Active returns true if the current time is covered by the plan
+func (t *Planner) Active(plan api.Rates) bool {
+	_, err := plan.Current(t.clock.Now())
+	return err == nil
+}
+
+// Slots converts the plan into slots with expected cost at the given charge power in W
+func Slots(plan api.Rates, power float64) []Slot {
+	res := make([]Slot, 0, len(plan))
+	for _, r := range plan {
+		res = append(res, Slot{
+			Start: r.Start,
+			End:   r.End,
+			Price: r.Price,
+			Cost:  r.End.Sub(r.Start).Hours() * power / 1e3 * r.Price,
+		})
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type rates api.Rates
+
+func (r rates) Rates() (api.Rates, error) {
+	return api.Rates(r), nil
+}
+
+// hourly creates hourly rates starting at the given time
+func hourly(start time.Time, prices ...float64) rates {
+	res := make(rates, 0, len(prices))
+	for i, p := range prices {
+		slotStart := start.Add(time.Duration(i) * time.Hour)
+		res = append(res, api.Rate{
+			Start: slotStart,
+			End:   slotStart.Add(time.Hour),
+			Price: p,
+		})
+	}
+	return res
+}
+
+func TestPlan(t *testing.T) {
+	clck := clock.NewMock()
+	now := clck.Now()
+
+	tariff := hourly(now, 20, 60, 10, 80, 40, 90)
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clck,
+		tariff: tariff,
+	}
+
+	tc := []struct {
+		desc     string
+		duration time.Duration
+		target   time.Duration
+		plan     api.Rates
+	}{
+		{"nothing to charge", 0, 6 * time.Hour, nil},
+		{"cheapest single slot", time.Hour, 6 * time.Hour, api.Rates{tariff[2]}},
+		{"cheapest two slots non-contiguous", 2 * time.Hour, 6 * time.Hour, api.Rates{tariff[0], tariff[2]}},
+		{"target limits window", time.Hour, 2 * time.Hour, api.Rates{tariff[0]}},
+		{"partial slot adjacent", 90 * time.Minute, 6 * time.Hour, api.Rates{
+			{Start: now, End: now.Add(30 * time.Minute), Price: 20},
+			tariff[2],
+		}},
+		{"partial slot late", 150 * time.Minute, 6 * time.Hour, api.Rates{
+			tariff[0],
+			tariff[2],
+			{Start: now.Add(4*time.Hour + 30*time.Minute), End: now.Add(5 * time.Hour), Price: 40},
+		}},
+		{"insufficient time", 3 * time.Hour, 2 * time.Hour, api.Rates{tariff[0], tariff[1]}},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.desc)
+
+		plan, err := p.Plan(tc.duration, now.Add(tc.target))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.plan, plan)
+	}
+}
+
+func TestPlanIncomplete(t *testing.T) {
+	clck := clock.NewMock()
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clck,
+		tariff: hourly(clck.Now(), 20, 10),
+	}
+
+	_, err := p.Plan(time.Hour, clck.Now().Add(3*time.Hour))
+	assert.ErrorIs(t, err, ErrIncomplete)
+}
+
+func TestActive(t *testing.T) {
+	clck := clock.NewMock()
+	tariff := hourly(clck.Now(), 30, 10, 20)
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clck,
+		tariff: tariff,
+	}
+
+	plan, err := p.Plan(time.Hour, clck.Now().Add(3*time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, p.Active(plan))
+
+	// replan after moving into the cheapest slot
+	clck.Add(90 * time.Minute)
+	plan, err = p.Plan(time.Hour, clck.Now().Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, p.Active(plan))
+}
+
+func TestSlots(t *testing.T) {
+	clck := clock.NewMock()
+	plan := api.Rates(hourly(clck.Now(), 0.3, 0.2))
+
+	slots := Slots(plan, 11e3)
+	assert.Len(t, slots, 2)
+	assert.InDelta(t, 3.3, slots[0].Cost, 1e-9)
+	assert.InDelta(t, 2.2, slots[1].Cost, 1e-9)
+}
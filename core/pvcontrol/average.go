@@ -0,0 +1,209 @@
+package pvcontrol
+
+import (
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Average starts and stops charging based on the energy balance over a rolling window.
+// Charge starts are limited per hour and a started session is kept for a minimum duration
+// as long as the grid import required for bridging does not exceed the configured energy.
+// With a solar forecast, start and stop thresholds are shifted by the hysteresis power
+// depending on the pv production expected for the next hour.
+type Average struct {
+	log           *util.Logger
+	clock         clock.Clock
+	window        time.Duration
+	maxCycles     int
+	minSession    time.Duration
+	maxGridEnergy float64
+	hysteresis    float64
+	forecast      api.SolarForecaster
+
+	samples    []sample
+	starts     []time.Time
+	session    time.Time
+	gridEnergy float64
+}
+
+type sample struct {
+	ts    time.Time
+	power float64
+}
+
+// NewAverageFromConfig creates an averaging strategy from generic config
+func NewAverageFromConfig(log *util.Logger, clock clock.Clock, other map[string]interface{}) (*Average, error) {
+	cc := struct {
+		Window        time.Duration
+		MaxCycles     int
+		MinSession    time.Duration
+		MaxGridEnergy float64
+		Hysteresis    float64
+	}{
+		Window:        5 * time.Minute,
+		MaxCycles:     4,
+		MinSession:    10 * time.Minute,
+		MaxGridEnergy: 250,
+		Hysteresis:    500,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	s := NewAverage(log, clock, cc.Window, cc.MaxCycles, cc.MinSession, cc.MaxGridEnergy)
+	s.hysteresis = cc.Hysteresis
+
+	return s, nil
+}
+
+// NewAverage creates an averaging strategy
+func NewAverage(log *util.Logger, clock clock.Clock, window time.Duration, maxCycles int, minSession time.Duration, maxGridEnergy float64) *Average {
+	return &Average{
+		log:           log,
+		clock:         clock,
+		window:        window,
+		maxCycles:     maxCycles,
+		minSession:    minSession,
+		maxGridEnergy: maxGridEnergy,
+	}
+}
+
+// SetForecaster implements the ForecastStrategy interface
+func (s *Average) SetForecaster(forecast api.SolarForecaster) {
+	s.forecast = forecast
+}
+
+// thresholds returns the average power required for starting and stopping charging.
+// If the forecast expects sufficient pv production for the next hour, stopping is delayed
+// by the hysteresis. Otherwise, starting requires the hysteresis in addition.
+func (s *Average) thresholds(now time.Time, minPower float64) (float64, float64) {
+	if s.forecast == nil || s.hysteresis <= 0 {
+		return minPower, minPower
+	}
+
+	forecast, err := s.forecast.SolarForecast()
+	if err != nil {
+		s.log.DEBUG.Printf("pv control: solar forecast: %v", err)
+		return minPower, minPower
+	}
+
+	// energy in Wh over one hour equals the average power in W
+	if forecast.Energy(now, now.Add(time.Hour)) >= minPower {
+		return minPower, minPower - s.hysteresis
+	}
+
+	return minPower + s.hysteresis, minPower
+}
+
+// average returns the time-weighted available power over the window and if the window is fully covered
+func (s *Average) average(now time.Time) (float64, bool) {
+	start := now.Add(-s.window)
+	if len(s.samples) == 0 || s.samples[0].ts.After(start) {
+		return 0, false
+	}
+
+	var energy float64
+	for i, smp := range s.samples {
+		from := smp.ts
+		if from.Before(start) {
+			from = start
+		}
+
+		to := now
+		if i+1 < len(s.samples) {
+			to = s.samples[i+1].ts
+		}
+
+		if to.After(from) {
+			energy += smp.power * to.Sub(from).Hours()
+		}
+	}
+
+	return energy / s.window.Hours(), true
+}
+
+// record adds a sample and drops samples no longer required for covering the window.
+// Samples are discarded if updates have been interrupted for longer than the window.
+func (s *Average) record(now time.Time, power float64) {
+	if n := len(s.samples); n > 0 && now.Sub(s.samples[n-1].ts) > s.window {
+		s.samples = nil
+	}
+
+	s.samples = append(s.samples, sample{ts: now, power: power})
+
+	start := now.Add(-s.window)
+	for len(s.samples) > 1 && !s.samples[1].ts.After(start) {
+		s.samples = s.samples[1:]
+	}
+}
+
+// cycles returns the number of charge starts within the last hour
+func (s *Average) cycles(now time.Time) int {
+	for len(s.starts) > 0 && now.Sub(s.starts[0]) >= time.Hour {
+		s.starts = s.starts[1:]
+	}
+	return len(s.starts)
+}
+
+// Update implements the Strategy interface
+func (s *Average) Update(enabled bool, available, minPower float64) bool {
+	now := s.clock.Now()
+
+	// track charging session
+	switch {
+	case enabled && s.session.IsZero():
+		s.session = now
+		s.gridEnergy = 0
+		s.starts = append(s.starts, now)
+	case !enabled:
+		s.session = time.Time{}
+	}
+
+	// grid import required for charging at minimum power since last sample
+	if enabled && len(s.samples) > 0 {
+		last := s.samples[len(s.samples)-1]
+		if from := last.ts; !from.Before(s.session) {
+			s.gridEnergy += math.Max(0, minPower-last.power) * now.Sub(from).Hours()
+		}
+	}
+
+	s.record(now, available)
+	avg, filled := s.average(now)
+	enableAt, disableAt := s.thresholds(now, minPower)
+
+	if !enabled {
+		if !filled || avg < enableAt {
+			return false
+		}
+
+		if s.maxCycles > 0 && s.cycles(now) >= s.maxCycles {
+			s.log.DEBUG.Printf("pv control: %d charge starts within last hour, keep disabled", s.maxCycles)
+			return false
+		}
+
+		s.log.DEBUG.Printf("pv control: enable at %.0fW average over %v", avg, s.window)
+		return true
+	}
+
+	// bridge short deficits during minimum session length
+	if elapsed := now.Sub(s.session); elapsed < s.minSession {
+		if s.gridEnergy > s.maxGridEnergy {
+			s.log.DEBUG.Printf("pv control: disable after %.0fWh grid import", s.gridEnergy)
+			return false
+		}
+
+		return true
+	}
+
+	if filled && avg < disableAt {
+		s.log.DEBUG.Printf("pv control: disable at %.0fW average over %v", avg, s.window)
+		return false
+	}
+
+	return true
+}
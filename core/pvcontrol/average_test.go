@@ -0,0 +1,137 @@
+package pvcontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	minPower = 4140 // 6A @ 3p
+	interval = 10 * time.Second
+)
+
+// run feeds constant available power for the given duration and returns the last decision
+func run(clck *clock.Mock, s *Average, enabled bool, available float64, d time.Duration) bool {
+	var res bool
+	for end := clck.Now().Add(d); !clck.Now().After(end); clck.Add(interval) {
+		res = s.Update(enabled, available, minPower)
+	}
+	return res
+}
+
+func TestAverageEnable(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 10*time.Minute, 250)
+
+	// window not yet covered
+	assert.False(t, run(clck, s, false, 5000, 4*time.Minute))
+
+	// window covered with sufficient average
+	assert.True(t, run(clck, s, false, 5000, time.Minute))
+
+	// short peaks do not enable
+	clck = clock.NewMock()
+	s = NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 10*time.Minute, 250)
+	run(clck, s, false, 0, 5*time.Minute)
+	assert.False(t, run(clck, s, false, 8000, 2*time.Minute))
+
+	// until energy balance turns positive
+	assert.True(t, run(clck, s, false, 8000, 2*time.Minute))
+}
+
+func TestAverageMinSession(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 10*time.Minute, 250)
+
+	assert.True(t, run(clck, s, false, 5000, 5*time.Minute))
+
+	// bridge short cloud within grid energy budget
+	assert.True(t, run(clck, s, true, 0, 3*time.Minute))
+
+	// disable once budget is exceeded
+	assert.False(t, run(clck, s, true, 0, 2*time.Minute))
+}
+
+func TestAverageDisable(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 10*time.Minute, 250)
+
+	assert.True(t, run(clck, s, false, 5000, 5*time.Minute))
+	assert.True(t, run(clck, s, true, 5000, 10*time.Minute))
+
+	// after minimum session length a negative energy balance disables
+	assert.True(t, run(clck, s, true, 3000, time.Minute))
+	assert.False(t, run(clck, s, true, 3000, 4*time.Minute))
+}
+
+func TestAverageCycles(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewAverage(util.NewLogger("foo"), clck, time.Minute, 2, 0, 0)
+
+	for i := 0; i < 2; i++ {
+		assert.True(t, run(clck, s, false, 5000, time.Minute), "cycle %d", i+1)
+		s.Update(true, 5000, minPower)
+
+		assert.False(t, run(clck, s, true, 0, time.Minute), "cycle %d", i+1)
+	}
+
+	// cycles exhausted
+	assert.False(t, run(clck, s, false, 5000, 10*time.Minute))
+
+	// until the first start has left the hour
+	assert.True(t, run(clck, s, false, 5000, time.Hour))
+}
+
+func TestAverageGap(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 10*time.Minute, 250)
+
+	run(clck, s, false, 5000, 4*time.Minute)
+
+	// samples from before an interruption don't count towards the window
+	clck.Add(time.Hour)
+	assert.False(t, run(clck, s, false, 5000, 4*time.Minute))
+	assert.True(t, run(clck, s, false, 5000, time.Minute))
+}
+
+type forecast api.Forecast
+
+func (f forecast) SolarForecast() (api.Forecast, error) {
+	return api.Forecast(f), nil
+}
+
+func TestAverageForecastHysteresis(t *testing.T) {
+	clck := clock.NewMock()
+	now := clck.Now()
+
+	s := NewAverage(util.NewLogger("foo"), clck, 5*time.Minute, 0, 0, 0)
+	s.hysteresis = 500
+
+	// low forecast requires additional surplus for starting
+	s.SetForecaster(forecast{{Start: now, End: now.Add(24 * time.Hour), Power: 1000}})
+	assert.False(t, run(clck, s, false, minPower+200, 5*time.Minute))
+	assert.True(t, run(clck, s, false, minPower+600, 5*time.Minute))
+
+	// sufficient forecast delays stopping
+	s.SetForecaster(forecast{{Start: now, End: now.Add(24 * time.Hour), Power: 8000}})
+	assert.True(t, run(clck, s, true, minPower-200, 5*time.Minute))
+	assert.False(t, run(clck, s, true, minPower-600, 5*time.Minute))
+}
+
+func TestNewFromConfig(t *testing.T) {
+	s, err := NewFromConfig(util.NewLogger("foo"), clock.NewMock(), "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = NewFromConfig(util.NewLogger("foo"), clock.NewMock(), "average", map[string]interface{}{"window": "2m"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, s.(*Average).window)
+
+	_, err = NewFromConfig(util.NewLogger("foo"), clock.NewMock(), "foo", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package pvcontrol
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Strategy decides when pv mode charging starts and stops
+type Strategy interface {
+	// Update records the power available for charging and returns true if charging should be enabled.
+	// enabled is the charger's current state, minPower the power required for charging at minimum current.
+	Update(enabled bool, available, minPower float64) bool
+}
+
+// ForecastStrategy is a strategy taking the pv forecast into account
+type ForecastStrategy interface {
+	SetForecaster(api.SolarForecaster)
+}
+
+// Config is the loadpoint's pv control strategy configuration
+type Config struct {
+	Type  string
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+// Timer is the default strategy using the loadpoint's enable/disable thresholds.
+// It is implemented by the loadpoint itself and therefore represented by a nil Strategy.
+const Timer = "timer"
+
+// NewFromConfig creates a pv control strategy. The timer strategy returns nil.
+func NewFromConfig(log *util.Logger, clock clock.Clock, typ string, other map[string]interface{}) (Strategy, error) {
+	switch strings.ToLower(typ) {
+	case "", Timer:
+		return nil, nil
+	case "average":
+		return NewAverageFromConfig(log, clock, other)
+	default:
+		return nil, errors.New("unknown pv control strategy: " + typ)
+	}
+}
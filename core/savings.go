@@ -48,6 +48,13 @@ func NewSavings(tariffs tariff.Tariffs) *Savings {
 	return savings
 }
 
+// setClock replaces the clock, restarting the savings period
+func (s *Savings) setClock(clock clock.Clock) {
+	s.clock = clock
+	s.started = clock.Now()
+	s.updated = clock.Now()
+}
+
 func (s *Savings) load() {
 	s.started, _ = settings.Time("savings.started")
 	s.gridCharged, _ = settings.Float("savings.gridCharged")
@@ -147,6 +154,11 @@ func (s *Savings) updatePrices(p publisher) (float64, float64) {
 	return gridPrice, feedinPrice
 }
 
+// Prices returns the grid and feed-in prices used by the last update
+func (s *Savings) Prices() (float64, float64) {
+	return s.lastGridPrice, s.lastFeedInPrice
+}
+
 // Update savings calculation and return grid/green energy added since last update
 func (s *Savings) Update(p publisher, gridPower, pvPower, batteryPower, chargePower float64) (float64, float64) {
 	gridPrice, feedinPrice := s.updatePrices(p)
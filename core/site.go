@@ -8,16 +8,21 @@ import (
 	"time"
 
 	"github.com/avast/retry-go/v3"
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/allocator"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/pvcontrol"
 	"github.com/evcc-io/evcc/push"
 	serverdb "github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/telemetry"
+	"golang.org/x/exp/slices"
 )
 
 const standbyPower = 10 // consider less than 10W as charger in standby
@@ -35,7 +40,8 @@ type Site struct {
 	*Health
 
 	sync.Mutex
-	log *util.Logger
+	log   *util.Logger
+	clock clock.Clock // mockable time
 
 	// configuration
 	Title                             string       `mapstructure:"title"`         // UI title
@@ -45,6 +51,10 @@ type Site struct {
 	PrioritySoC                       float64      `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
 	BufferSoC                         float64      `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
 	MaxGridSupplyWhileBatteryCharging float64      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	MaxGridCurrent                    float64      `mapstructure:"maxGridCurrent"`                    // maximum grid current per phase (main fuse)
+	MaxGridPower                      float64      `mapstructure:"maxGridPower"`                      // maximum grid import power
+	BatteryDischargeControl           bool         `mapstructure:"batteryDischargeControl"`           // hold battery while charging from grid
+	BatteryGridChargeLimit            float64      `mapstructure:"batteryGridChargeLimit"`            // charge battery from grid up to this SoC when cheap
 
 	// meters
 	gridMeter     api.Meter   // Grid usage meter
@@ -53,14 +63,20 @@ type Site struct {
 
 	tariffs     tariff.Tariffs           // Tariff
 	loadpoints  []*LoadPoint             // Loadpoints
+	consumers   []*Consumer              // Non-vehicle consumers
 	coordinator *coordinator.Coordinator // Savings
 	savings     *Savings                 // Savings
 
 	// cached state
-	gridPower       float64 // Grid power
-	pvPower         float64 // PV power
-	batteryPower    float64 // Battery charge power
-	batteryBuffered bool    // Battery buffer active
+	gridPower       float64   // Grid power
+	gridCurrents    []float64 // Grid phase currents
+	pvPower         float64   // PV power
+	batteryPower    float64   // Battery charge power
+	batterySoC      float64   // Battery SoC
+	batteryBuffered bool      // Battery buffer active
+	batteryMode     api.BatteryMode
+	gridRates       api.Rates // Grid tariff price forecast
+	feedinRates     api.Rates // Feed-in tariff price forecast
 }
 
 // MetersConfig contains the loadpoint's meter configuration
@@ -78,6 +94,7 @@ func NewSiteFromConfig(
 	cp configProvider,
 	other map[string]interface{},
 	loadpoints []*LoadPoint,
+	consumers []*Consumer,
 	vehicles []api.Vehicle,
 	tariffs tariff.Tariffs,
 ) (*Site, error) {
@@ -88,6 +105,7 @@ func NewSiteFromConfig(
 
 	Voltage = site.Voltage
 	site.loadpoints = loadpoints
+	site.consumers = consumers
 	site.tariffs = tariffs
 	site.coordinator = coordinator.New(log, vehicles)
 	site.savings = NewSavings(tariffs)
@@ -107,9 +125,19 @@ func NewSiteFromConfig(
 		}
 	}
 
-	// give loadpoints access to vehicles and database
+	// give loadpoints access to vehicles, database, price forecast and carbon intensity
 	for _, lp := range loadpoints {
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
+		lp.co2 = tariffs.CO2
+		lp.solar = tariffs.Solar
+
+		if fs, ok := lp.pvControl.(pvcontrol.ForecastStrategy); ok && tariffs.Solar != nil {
+			fs.SetForecaster(tariffs.Solar)
+		}
+
+		if rates, ok := tariffs.Grid.(api.TariffRates); ok {
+			lp.socTimer.Planner = planner.New(lp.log, rates)
+		}
 
 		if serverdb.Instance != nil {
 			var err error
@@ -182,6 +210,7 @@ func NewSiteFromConfig(
 func NewSite() *Site {
 	lp := &Site{
 		log:     util.NewLogger("site"),
+		clock:   clock.New(),
 		Voltage: 230, // V
 	}
 
@@ -380,9 +409,11 @@ func (site *Site) updateMeters() error {
 	err := retryMeter("grid", site.gridMeter, &site.gridPower)
 
 	// currents
+	site.gridCurrents = nil
 	if phaseMeter, ok := site.gridMeter.(api.MeterCurrent); err == nil && ok {
 		i1, i2, i3, err := phaseMeter.Currents()
 		if err == nil {
+			site.gridCurrents = []float64{i1, i2, i3}
 			site.log.DEBUG.Printf("grid currents: %.3gA", []float64{i1, i2, i3})
 			site.publish("gridCurrents", []float64{i1, i2, i3})
 		} else {
@@ -403,6 +434,25 @@ func (site *Site) updateMeters() error {
 	return err
 }
 
+// publishTariffRates publishes the tariff's price forecast if changed
+func (site *Site) publishTariffRates(key string, tariff api.Tariff, cached *api.Rates) {
+	tr, ok := tariff.(api.TariffRates)
+	if !ok {
+		return
+	}
+
+	rates, err := tr.Rates()
+	if err != nil {
+		site.log.ERROR.Printf("%s: %v", key, err)
+		return
+	}
+
+	if !slices.Equal(rates, *cached) {
+		*cached = rates
+		site.publish(key, rates)
+	}
+}
+
 // sitePower returns the net power exported by the site minus a residual margin.
 // negative values mean grid: export, battery: charging
 func (site *Site) sitePower(totalChargePower float64) (float64, error) {
@@ -441,6 +491,7 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 			}
 		}
 		site.publish("batterySoC", math.Round(socs))
+		site.batterySoC = socs
 
 		site.Lock()
 		defer site.Unlock()
@@ -481,25 +532,173 @@ func (site *Site) update(lp Updater) {
 		totalChargePower += lp.GetChargePower()
 	}
 
+	for _, c := range site.consumers {
+		c.UpdatePower()
+	}
+
 	if sitePower, err := site.sitePower(totalChargePower); err == nil {
-		lp.Update(sitePower, cheap, site.batteryBuffered)
+		// limit loadpoints to the grid connection's capacity
+		site.updateGridLimit()
 
 		// ignore negative pvPower values as that means it is not an energy source but consumption
 		homePower := site.gridPower + math.Max(0, site.pvPower) + site.batteryPower - totalChargePower
 		homePower = math.Max(homePower, 0)
 		site.publish("homePower", homePower)
 
+		// pv forecasts are shared with the house and other loadpoints
+		for _, lp := range site.loadpoints {
+			lp.baseLoad = homePower + site.ResidualPower + totalChargePower - lp.GetChargePower()
+		}
+
+		// split surplus across pv loadpoints
+		lpPower := sitePower
+		if power, ok := site.allocatePower(sitePower, cheap)[lp]; ok {
+			lpPower = power
+		}
+
+		lp.Update(lpPower, cheap, site.batteryBuffered)
+
+		// switch consumers according to mode and allocated surplus
+		site.updateConsumers(cheap)
+
+		// hold or charge home battery depending on loadpoints and tariff
+		site.updateBatteryMode(cheap)
+
 		site.Health.Update()
 	}
 
+	site.publishTariffRates("tariffGridRates", site.tariffs.Grid, &site.gridRates)
+	site.publishTariffRates("tariffFeedInRates", site.tariffs.FeedIn, &site.feedinRates)
+	site.publishSolarForecast()
+
 	// update savings and aggregate telemetry
 	// TODO: use energy instead of current power for better results
 	deltaCharged, deltaSelf := site.savings.Update(site, site.gridPower, site.pvPower, site.batteryPower, totalChargePower)
+	site.updateSessions(deltaCharged, deltaSelf, totalChargePower)
+	site.updateCO2(deltaCharged-deltaSelf, totalChargePower)
 	if telemetry.Enabled() && totalChargePower > standbyPower {
 		go telemetry.UpdateChargeProgress(site.log, totalChargePower, deltaCharged, deltaSelf)
 	}
 }
 
+// publishSolarForecast publishes the expected remaining pv energy for today and tomorrow
+func (site *Site) publishSolarForecast() {
+	if site.tariffs.Solar == nil {
+		return
+	}
+
+	forecast, err := site.tariffs.Solar.SolarForecast()
+	if err != nil {
+		if !errors.Is(err, api.ErrNotAvailable) {
+			site.log.ERROR.Printf("solar forecast: %v", err)
+		}
+		return
+	}
+
+	now := site.clock.Now()
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+
+	site.publish("solarForecastToday", math.Round(forecast.Energy(now, midnight)))
+	site.publish("solarForecastTomorrow", math.Round(forecast.Energy(midnight, midnight.AddDate(0, 0, 1))))
+}
+
+// allocatePower splits the surplus across all connected loadpoints and consumers in pv modes by priority and fair share.
+// Loadpoints charging from grid regardless of surplus neither share nor contribute their charge power.
+// Returns each loadpoint's share expressed as site power from the loadpoint's point of view.
+// Consumer shares are stored with the consumers.
+func (site *Site) allocatePower(sitePower float64, cheap bool) map[Updater]float64 {
+	var (
+		lps       []*LoadPoint
+		consumers []*Consumer
+		demands   []allocator.Demand
+	)
+
+	// available power includes the pv loadpoints' current charge power and pv consumers' power
+	available := -sitePower
+
+	for _, lp := range site.loadpoints {
+		mode := lp.GetMode()
+		if mode != api.ModePV && mode != api.ModeMinPV || !lp.connected() || lp.pvGridCharging(cheap) {
+			continue
+		}
+
+		lps = append(lps, lp)
+		demands = append(demands, lp.powerDemand(mode == api.ModeMinPV))
+		available += lp.GetChargePower()
+	}
+
+	for _, c := range site.consumers {
+		if c.GetMode() != api.ModePV {
+			continue
+		}
+
+		consumers = append(consumers, c)
+		demands = append(demands, c.powerDemand())
+		available += c.GetPower()
+	}
+
+	res := make(map[Updater]float64, len(lps))
+
+	for i, a := range allocator.Allocate(available, demands) {
+		if i >= len(lps) {
+			consumers[i-len(lps)].allocated = a.Power
+			continue
+		}
+
+		lp := lps[i]
+		res[lp] = lp.GetChargePower() - a.Power
+
+		lp.publish("pvAllocation", a.Power)
+		lp.publish("pvAllocationReason", a.Reason)
+	}
+
+	return res
+}
+
+// updateSessions attributes the charged grid and self-produced energy and its cost
+// to the loadpoints' sessions proportional to their charge power
+func (site *Site) updateSessions(deltaCharged, deltaSelf, totalChargePower float64) {
+	if deltaCharged <= 0 || totalChargePower <= 0 {
+		return
+	}
+
+	gridPrice, feedinPrice := site.savings.Prices()
+
+	for _, lp := range site.loadpoints {
+		if power := lp.GetChargePower(); power > 0 {
+			share := power / totalChargePower
+			lp.addSessionEnergy((deltaCharged-deltaSelf)*share, deltaSelf*share, gridPrice, feedinPrice)
+		}
+	}
+}
+
+// updateCO2 publishes the grid's carbon intensity and attributes the emissions of
+// the charged grid energy to the loadpoints' sessions proportional to their charge power
+func (site *Site) updateCO2(deltaGrid, totalChargePower float64) {
+	if site.tariffs.CO2 == nil {
+		return
+	}
+
+	intensity, err := site.tariffs.CO2.CurrentCO2Intensity()
+	if err != nil {
+		site.log.ERROR.Printf("co2: %v", err)
+		return
+	}
+
+	site.publish("tariffCO2", intensity)
+
+	if deltaGrid <= 0 || totalChargePower <= 0 {
+		return
+	}
+
+	for _, lp := range site.loadpoints {
+		if power := lp.GetChargePower(); power > 0 {
+			lp.addSessionCO2(deltaGrid*power/totalChargePower, intensity)
+		}
+	}
+}
+
 // prepare publishes initial values
 func (site *Site) prepare() {
 	site.publish("siteTitle", site.Title)
@@ -555,6 +754,28 @@ func (site *Site) loopLoadpoints(next chan<- Updater) {
 	}
 }
 
+// SetClock replaces the clock of site and loadpoints for replaying recorded data. Must be called before Prepare.
+func (site *Site) SetClock(clock clock.Clock) {
+	site.clock = clock
+	site.savings.setClock(clock)
+
+	for _, lp := range site.loadpoints {
+		lp.setClock(clock)
+	}
+}
+
+// Replay is the control loop for replaying recorded data. It updates the loadpoints in turn,
+// advancing the mocked clock by interval after each update until the end time is reached.
+// The site must have at least one loadpoint.
+func (site *Site) Replay(clock *clock.Mock, end time.Time, interval time.Duration) {
+	site.Health = NewHealth(time.Minute + interval)
+
+	for i := 0; !clock.Now().After(end); i++ {
+		site.update(site.loadpoints[i%len(site.loadpoints)])
+		clock.Add(interval)
+	}
+}
+
 // Run is the main control loop. It reacts to trigger events by
 // updating measurements and executing control logic.
 func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
@@ -563,7 +784,8 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 	loadpointChan := make(chan Updater)
 	go site.loopLoadpoints(loadpointChan)
 
-	ticker := time.NewTicker(interval)
+	// interval is site time, i.e. scaled when running on the simulation clock
+	ticker := site.clock.Ticker(interval)
 	site.update(<-loadpointChan) // start immediately
 
 	for {
@@ -573,6 +795,8 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		case lp := <-site.lpUpdateChan:
 			site.update(lp)
 		case <-stopC:
+			site.restoreBatteryMode()
+			site.stopConsumers()
 			return
 		}
 	}
@@ -9,6 +9,7 @@ import (
 type API interface {
 	Healthy() bool
 	LoadPoints() []loadpoint.API
+	Consumers() []ConsumerAPI
 
 	//
 	// battery
@@ -32,4 +33,18 @@ type API interface {
 
 	// GetVehicles is the list of vehicles
 	GetVehicles() []api.Vehicle
+
+	//
+	// tariffs
+	//
+
+	// GetTariff returns the respective tariff if configured or nil
+	GetTariff(string) api.Tariff
+}
+
+// ConsumerAPI is the external consumer API
+type ConsumerAPI interface {
+	GetTitle() string
+	GetMode() api.ChargeMode
+	SetMode(api.ChargeMode) error
 }
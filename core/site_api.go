@@ -77,3 +77,26 @@ func (site *Site) GetVehicles() []api.Vehicle {
 	defer site.Unlock()
 	return site.coordinator.GetVehicles()
 }
+
+// GetTariff returns the respective tariff if configured or nil
+func (site *Site) GetTariff(tariff string) api.Tariff {
+	site.Lock()
+	defer site.Unlock()
+
+	switch tariff {
+	case api.TariffGrid:
+		return site.tariffs.Grid
+	case api.TariffFeedIn:
+		return site.tariffs.FeedIn
+	default:
+		return nil
+	}
+}
+
+// Consumers returns the array of associated consumers
+func (site *Site) Consumers() (res []site.ConsumerAPI) {
+	for _, c := range site.consumers {
+		res = append(res, c)
+	}
+	return res
+}
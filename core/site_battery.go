@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// batteryControllable returns true if any battery meter supports mode control
+func (site *Site) batteryControllable() bool {
+	for _, battery := range site.batteryMeters {
+		if _, ok := battery.(api.BatteryController); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// batteryGridChargeHysteresis is the SoC drop below the grid charge limit required to resume grid charging
+const batteryGridChargeHysteresis = 5
+
+// requiredBatteryMode determines the battery mode from tariff and loadpoint state
+func (site *Site) requiredBatteryMode(cheap bool) api.BatteryMode {
+	// charge battery from grid while cheap, resume only after dropping below the limit's hysteresis
+	limit := site.BatteryGridChargeLimit
+	if site.batteryMode != api.BatteryCharge {
+		limit -= batteryGridChargeHysteresis
+	}
+
+	if cheap && site.BatteryGridChargeLimit > 0 && site.batterySoC < limit {
+		return api.BatteryCharge
+	}
+
+	// don't discharge battery into vehicles charging from grid
+	if site.BatteryDischargeControl {
+		for _, lp := range site.loadpoints {
+			if lp.gridCharging(cheap) {
+				return api.BatteryHold
+			}
+		}
+	}
+
+	return api.BatteryNormal
+}
+
+// setBatteryMode applies the battery mode to all controllable batteries
+func (site *Site) setBatteryMode(mode api.BatteryMode) error {
+	for id, battery := range site.batteryMeters {
+		if bc, ok := battery.(api.BatteryController); ok {
+			if err := bc.SetBatteryMode(mode); err != nil {
+				return fmt.Errorf("battery %d: %w", id+1, err)
+			}
+		}
+	}
+
+	site.batteryMode = mode
+	site.publish("batteryMode", mode)
+
+	return nil
+}
+
+// updateBatteryMode updates the battery mode if changed
+func (site *Site) updateBatteryMode(cheap bool) {
+	if !site.batteryControllable() {
+		return
+	}
+
+	mode := site.requiredBatteryMode(cheap)
+	if mode == site.batteryMode {
+		return
+	}
+
+	site.log.DEBUG.Printf("battery mode: %s", mode)
+
+	if err := site.setBatteryMode(mode); err != nil {
+		site.log.ERROR.Printf("battery mode: %v", err)
+	}
+}
+
+// restoreBatteryMode returns controlled batteries to normal operation
+func (site *Site) restoreBatteryMode() {
+	if site.batteryMode == api.BatteryUnknown || site.batteryMode == api.BatteryNormal {
+		return
+	}
+
+	if err := site.setBatteryMode(api.BatteryNormal); err != nil {
+		site.log.ERROR.Printf("battery mode: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type batteryController struct {
+	api.Meter
+	api.Battery
+	modes []api.BatteryMode
+}
+
+func (b *batteryController) SetBatteryMode(mode api.BatteryMode) error {
+	b.modes = append(b.modes, mode)
+	return nil
+}
+
+func TestBatteryMode(t *testing.T) {
+	battery := new(batteryController)
+
+	lp := &LoadPoint{
+		log:    util.NewLogger("foo"),
+		Mode:   api.ModePV,
+		status: api.StatusC,
+	}
+
+	site := &Site{
+		log:                     util.NewLogger("foo"),
+		batteryMeters:           []api.Meter{battery},
+		loadpoints:              []*LoadPoint{lp},
+		BatteryDischargeControl: true,
+		BatteryGridChargeLimit:  80,
+		batterySoC:              50,
+	}
+
+	tc := []struct {
+		desc    string
+		mode    api.ChargeMode
+		status  api.ChargeStatus
+		cheap   bool
+		soc     float64
+		battery api.BatteryMode
+		res     api.BatteryMode
+	}{
+		{"pv charging", api.ModePV, api.StatusC, false, 50, api.BatteryNormal, api.BatteryNormal},
+		{"now charging", api.ModeNow, api.StatusC, false, 50, api.BatteryNormal, api.BatteryHold},
+		{"now not charging", api.ModeNow, api.StatusB, false, 50, api.BatteryNormal, api.BatteryNormal},
+		{"pv cheap charging below limit", api.ModePV, api.StatusC, true, 50, api.BatteryNormal, api.BatteryCharge},
+		{"pv cheap charging above limit", api.ModePV, api.StatusC, true, 90, api.BatteryNormal, api.BatteryHold},
+		{"off cheap above limit", api.ModeOff, api.StatusB, true, 90, api.BatteryNormal, api.BatteryNormal},
+		{"off cheap within hysteresis", api.ModeOff, api.StatusB, true, 78, api.BatteryNormal, api.BatteryNormal},
+		{"off cheap within hysteresis while charging", api.ModeOff, api.StatusB, true, 78, api.BatteryCharge, api.BatteryCharge},
+		{"off cheap at limit while charging", api.ModeOff, api.StatusB, true, 80, api.BatteryCharge, api.BatteryNormal},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.desc)
+
+		lp.Mode = tc.mode
+		lp.status = tc.status
+		site.batterySoC = tc.soc
+		site.batteryMode = tc.battery
+
+		assert.Equal(t, tc.res, site.requiredBatteryMode(tc.cheap), tc.desc)
+	}
+}
+
+func TestUpdateBatteryMode(t *testing.T) {
+	battery := new(batteryController)
+
+	lp := &LoadPoint{
+		log:    util.NewLogger("foo"),
+		Mode:   api.ModeNow,
+		status: api.StatusC,
+	}
+
+	site := &Site{
+		log:                     util.NewLogger("foo"),
+		batteryMeters:           []api.Meter{battery},
+		loadpoints:              []*LoadPoint{lp},
+		BatteryDischargeControl: true,
+	}
+
+	// mode is only sent on change
+	site.updateBatteryMode(false)
+	site.updateBatteryMode(false)
+	assert.Equal(t, []api.BatteryMode{api.BatteryHold}, battery.modes)
+
+	// restore on shutdown
+	site.restoreBatteryMode()
+	assert.Equal(t, []api.BatteryMode{api.BatteryHold, api.BatteryNormal}, battery.modes)
+}
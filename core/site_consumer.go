@@ -0,0 +1,30 @@
+package core
+
+// updateConsumers switches all consumers and publishes their state
+func (site *Site) updateConsumers(cheap bool) {
+	if len(site.consumers) == 0 {
+		return
+	}
+
+	res := make([]ConsumerStatus, 0, len(site.consumers))
+
+	for _, c := range site.consumers {
+		c.Update(cheap)
+		res = append(res, c.status())
+	}
+
+	site.publish("consumers", res)
+}
+
+// stopConsumers returns all consumers to idle level
+func (site *Site) stopConsumers() {
+	for _, c := range site.consumers {
+		if c.level == 0 {
+			continue
+		}
+
+		if err := c.setLevel(0); err != nil {
+			c.log.ERROR.Printf("set level 0: %v", err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"math"
+)
+
+// phaseLoad is a loadpoint's current load on the grid's phases
+type phaseLoad struct {
+	currents [3]float64 // current per phase (A)
+	phases   int        // number of phases used, starting at L1
+}
+
+// phaseCurrentLimits returns each loadpoint's maximum current for keeping all grid phase currents below the limit.
+// The headroom of each phase including the loadpoints' own current is shared equally among the loadpoints using it.
+func phaseCurrentLimits(maxCurrent float64, gridCurrents []float64, loads []phaseLoad) []float64 {
+	var budget [3]float64
+	var users [3]int
+
+	for p := 0; p < 3; p++ {
+		budget[p] = maxCurrent - gridCurrents[p]
+	}
+
+	for _, l := range loads {
+		for p := 0; p < l.phases; p++ {
+			budget[p] += l.currents[p]
+			users[p]++
+		}
+	}
+
+	res := make([]float64, len(loads))
+	for i, l := range loads {
+		res[i] = math.Inf(1)
+		for p := 0; p < l.phases; p++ {
+			res[i] = math.Min(res[i], budget[p]/float64(users[p]))
+		}
+		res[i] = math.Max(res[i], 0)
+	}
+
+	return res
+}
+
+// powerCurrentLimits returns each loadpoint's maximum current for keeping the grid power below the limit.
+// The headroom including the loadpoints' own power is shared equally among the loadpoints.
+func powerCurrentLimits(maxPower, gridPower float64, chargePower []float64, phases []int) []float64 {
+	budget := maxPower - gridPower
+	for _, p := range chargePower {
+		budget += p
+	}
+
+	res := make([]float64, len(chargePower))
+	for i := range res {
+		res[i] = math.Max(powerToCurrent(budget/float64(len(res)), phases[i]), 0)
+	}
+
+	return res
+}
+
+// updateGridLimit limits the loadpoints' charge current to stay below the site's maximum grid current or power.
+// Per-phase limits are used if the grid meter provides currents, otherwise the total power limit applies.
+func (site *Site) updateGridLimit() {
+	if site.MaxGridCurrent == 0 && site.MaxGridPower == 0 {
+		return
+	}
+
+	var lps []*LoadPoint
+	for _, lp := range site.loadpoints {
+		if lp.connected() {
+			lps = append(lps, lp)
+		} else {
+			lp.setGridLimit(math.Inf(1))
+		}
+	}
+
+	if len(lps) == 0 {
+		return
+	}
+
+	limits := make([]float64, len(lps))
+	for i := range limits {
+		limits[i] = math.Inf(1)
+	}
+
+	if site.MaxGridCurrent > 0 && site.gridCurrents != nil {
+		loads := make([]phaseLoad, 0, len(lps))
+		for _, lp := range lps {
+			loads = append(loads, lp.phaseLoad())
+		}
+
+		for i, limit := range phaseCurrentLimits(site.MaxGridCurrent, site.gridCurrents, loads) {
+			limits[i] = math.Min(limits[i], limit)
+		}
+	}
+
+	maxPower := site.MaxGridPower
+	if maxPower == 0 && site.gridCurrents == nil {
+		// fall back to total power if phase currents are not available
+		maxPower = 3 * site.MaxGridCurrent * Voltage
+	}
+
+	if maxPower > 0 {
+		chargePower := make([]float64, 0, len(lps))
+		phases := make([]int, 0, len(lps))
+		for _, lp := range lps {
+			chargePower = append(chargePower, lp.GetChargePower())
+			phases = append(phases, lp.activePhases())
+		}
+
+		for i, limit := range powerCurrentLimits(maxPower, site.gridPower, chargePower, phases) {
+			limits[i] = math.Min(limits[i], limit)
+		}
+	}
+
+	for i, lp := range lps {
+		lp.setGridLimit(limits[i])
+	}
+}
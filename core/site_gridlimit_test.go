@@ -0,0 +1,88 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseCurrentLimits(t *testing.T) {
+	tc := []struct {
+		desc   string
+		grid   []float64
+		loads  []phaseLoad
+		limits []float64
+	}{
+		{"single 3p loadpoint with headroom", []float64{20, 25, 30}, []phaseLoad{
+			{[3]float64{16, 16, 16}, 3},
+		}, []float64{21}},
+		{"two 3p loadpoints share equally", []float64{34, 34, 34}, []phaseLoad{
+			{[3]float64{16, 16, 16}, 3},
+			{[3]float64{16, 16, 16}, 3},
+		}, []float64{16.5, 16.5}},
+		{"1p loadpoint only limited by L1", []float64{30, 10, 10}, []phaseLoad{
+			{[3]float64{10, 0, 0}, 1},
+			{[3]float64{10, 10, 10}, 3},
+		}, []float64{12.5, 12.5}},
+		{"no headroom", []float64{50, 20, 20}, []phaseLoad{
+			{[3]float64{10, 10, 10}, 3},
+		}, []float64{0}},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.desc)
+		assert.Equal(t, tc.limits, phaseCurrentLimits(35, tc.grid, tc.loads), tc.desc)
+	}
+}
+
+func TestPowerCurrentLimits(t *testing.T) {
+	Voltage = 230 // V
+
+	// 11kW headroom including own charge power, shared by two loadpoints
+	res := powerCurrentLimits(15000, 15000, []float64{5520, 5520}, []int{3, 1})
+	assert.InDelta(t, 8, res[0], 1e-9)
+	assert.InDelta(t, 24, res[1], 1e-9)
+}
+
+func TestSetGridLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clock.NewMock(),
+		charger:       charger,
+		wakeUpTimer:   NewTimer(),
+		MinCurrent:    minA,
+		MaxCurrent:    maxA,
+		enabled:       true,
+		chargeCurrent: maxA,
+	}
+
+	// reduce exceeding current
+	charger.EXPECT().MaxCurrent(int64(10)).Return(nil)
+	lp.setGridLimit(10)
+	assert.Equal(t, 10.0, lp.chargeCurrent)
+
+	// disable immediately below minimum current
+	charger.EXPECT().Enable(false).Return(nil)
+	lp.setGridLimit(4)
+	assert.False(t, lp.enabled)
+
+	// limit also applies to subsequent updates
+	assert.NoError(t, lp.setLimit(maxA, false))
+	assert.False(t, lp.enabled)
+
+	// remove limit
+	lp.setGridLimit(math.Inf(1))
+	assert.False(t, lp.gridLimited)
+
+	ctrl.Finish()
+}
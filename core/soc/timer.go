@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/util"
 )
 
@@ -16,11 +17,13 @@ const (
 type Timer struct {
 	Adapter
 	log       *util.Logger
+	Planner   *planner.Planner // optional price planner for dynamic tariffs
 	current   float64
 	SoC       int
 	Time      time.Time
 	finishAt  time.Time
 	active    bool
+	planned   bool
 	validated bool
 }
 
@@ -52,6 +55,15 @@ func (lp *Timer) DemandValidated() bool {
 	return lp.validated
 }
 
+// Active returns if target charging is currently active
+func (lp *Timer) Active() bool {
+	if lp == nil {
+		return false
+	}
+
+	return lp.active
+}
+
 // Stop stops the target charging request
 func (lp *Timer) Stop() {
 	if lp == nil {
@@ -76,6 +88,7 @@ func (lp *Timer) Set(t time.Time) {
 	if lp.Time.IsZero() {
 		lp.Publish("targetTime", nil)
 		lp.Publish("targetTimeProjectedStart", nil)
+		lp.Publish("targetTimePlan", nil)
 	} else {
 		lp.Publish("targetTime", lp.Time)
 	}
@@ -112,9 +125,40 @@ func (lp *Timer) DemandActive() bool {
 		return false
 	}
 
+	// price-optimized charging if tariff provides rates up to target time
+	lp.planned = false
+	if lp.Planner != nil && lp.Clock().Now().Before(lp.Time) {
+		maxPower := lp.GetMaxPower()
+		requiredDuration := time.Duration(float64(se.AssumedChargeDuration(lp.SoC, maxPower)) / chargeEfficiency)
+
+		active, err := lp.planActive(requiredDuration, maxPower)
+		if err == nil {
+			lp.planned = true
+
+			if active != lp.active {
+				lp.active = active
+				lp.Publish("targetTimeActive", lp.active)
+
+				if active {
+					lp.log.INFO.Printf("target charging active for %v: planned slot", lp.Time.Local())
+				} else {
+					lp.log.DEBUG.Println("target charging: outside planned slots")
+				}
+			}
+
+			if active {
+				lp.current = lp.GetMaxCurrent()
+			}
+
+			return lp.active
+		}
+
+		lp.log.DEBUG.Printf("target charging: plan not available: %v", err)
+	}
+
 	// time
 	remainingDuration := time.Duration(float64(se.AssumedChargeDuration(lp.SoC, power)) / chargeEfficiency)
-	lp.finishAt = time.Now().Add(remainingDuration).Round(time.Minute)
+	lp.finishAt = lp.Clock().Now().Add(remainingDuration).Round(time.Minute)
 
 	lp.log.DEBUG.Printf("estimated charge duration: %v to %d%% at %.0fW", remainingDuration.Round(time.Minute), lp.SoC, power)
 	if lp.active {
@@ -129,7 +173,7 @@ func (lp *Timer) DemandActive() bool {
 
 	// timer charging is already active- only deactivate once charging has stopped
 	if lp.active {
-		if time.Now().After(lp.Time) && lp.GetStatus() != api.StatusC {
+		if lp.Clock().Now().After(lp.Time) && lp.GetStatus() != api.StatusC {
 			lp.Stop()
 		}
 
@@ -148,8 +192,42 @@ func (lp *Timer) DemandActive() bool {
 	return lp.active
 }
 
+// planActive creates and publishes the lowest-cost charging plan and returns true if the current time is planned for charging
+func (lp *Timer) planActive(requiredDuration time.Duration, power float64) (bool, error) {
+	plan, err := lp.Planner.Plan(requiredDuration, lp.Time)
+	if err != nil {
+		return false, err
+	}
+
+	slots := planner.Slots(plan, power)
+
+	var cost float64
+	for _, slot := range slots {
+		cost += slot.Cost
+	}
+
+	lp.log.DEBUG.Printf("target charging: planned %d slots for %v at expected cost %.2f", len(slots), requiredDuration.Round(time.Minute), cost)
+	lp.Publish("targetTimePlan", slots)
+	lp.Publish("targetTimePlanCost", cost)
+
+	if len(slots) > 0 {
+		lp.Publish("targetTimeProjectedStart", slots[0].Start)
+	} else {
+		lp.Publish("targetTimeProjectedStart", nil)
+	}
+
+	return lp.Planner.Active(plan), nil
+}
+
 // Handle adjusts current up/down to achieve desired target time taking.
 func (lp *Timer) Handle() float64 {
+	// planned slots always charge at maximum current
+	if lp.planned {
+		lp.current = lp.GetMaxCurrent()
+		lp.log.DEBUG.Printf("target charging: planned (%.3gA)", lp.current)
+		return lp.current
+	}
+
 	action := "steady"
 
 	switch {
@@ -0,0 +1,201 @@
+package ocpp
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	ocppcore "github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// remoteSource identifies remote demand set by the central system
+const remoteSource = "ocpp"
+
+// connector maps a loadpoint to an OCPP connector. Transactions follow the loadpoint's charging sessions.
+type connector struct {
+	mu    sync.Mutex
+	log   *util.Logger
+	id    int
+	lp    loadpoint.API
+	cp    ocpp16.ChargePoint
+	idTag string
+
+	status     ocppcore.ChargePointStatus
+	txnId      int
+	meterStart int  // meter reading in Wh at transaction start
+	rejected   bool // transaction rejected by the central system until disconnect
+	remote     bool // remote demand set by the central system
+}
+
+// chargePointStatus maps the loadpoint status to the connector status
+func (c *connector) chargePointStatus(status api.ChargeStatus, txn bool) ocppcore.ChargePointStatus {
+	switch status {
+	case api.StatusA:
+		return ocppcore.ChargePointStatusAvailable
+	case api.StatusB:
+		if txn {
+			return ocppcore.ChargePointStatusSuspendedEV
+		}
+		return ocppcore.ChargePointStatusPreparing
+	case api.StatusC:
+		return ocppcore.ChargePointStatusCharging
+	case api.StatusE, api.StatusF:
+		return ocppcore.ChargePointStatusFaulted
+	default:
+		return ocppcore.ChargePointStatusUnavailable
+	}
+}
+
+// transaction returns the current transaction id
+func (c *connector) transaction() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.txnId
+}
+
+// update starts or stops transactions and sends status changes
+func (c *connector) update() {
+	status := c.lp.GetStatus()
+	connected := status == api.StatusB || status == api.StatusC
+
+	switch txn := c.transaction(); {
+	case connected && txn == 0 && !c.rejected:
+		c.startTransaction()
+	case !connected && txn != 0:
+		c.stopTransaction(txn)
+	case !connected:
+		c.rejected = false
+	}
+
+	if cps := c.chargePointStatus(status, c.transaction() != 0); cps != c.status {
+		c.log.DEBUG.Printf("send: connector %d status: %s", c.id, cps)
+
+		if _, err := c.cp.StatusNotification(c.id, ocppcore.NoError, cps); err != nil {
+			c.log.ERROR.Printf("connector %d: %v", c.id, err)
+			return
+		}
+
+		c.status = cps
+	}
+}
+
+// meterTotal returns the charge meter's total energy in Wh
+func (c *connector) meterTotal() (int, bool) {
+	total := c.lp.GetChargeMeterTotal()
+	return int(total * 1e3), total > 0
+}
+
+func (c *connector) startTransaction() {
+	// without charge meter, sessions start with zero energy
+	meterStart, _ := c.meterTotal()
+
+	res, err := c.cp.StartTransaction(c.id, c.idTag, meterStart, types.NewDateTime(time.Now()))
+	if err != nil {
+		c.log.ERROR.Printf("connector %d: start transaction: %v", c.id, err)
+		return
+	}
+
+	// rejected transactions are not tracked until disconnect
+	if res.IdTagInfo != nil && res.IdTagInfo.Status != types.AuthorizationStatusAccepted {
+		c.log.WARN.Printf("connector %d: start transaction: %s", c.id, res.IdTagInfo.Status)
+		c.rejected = true
+		return
+	}
+
+	c.log.DEBUG.Printf("connector %d: started transaction %d", c.id, res.TransactionId)
+
+	c.mu.Lock()
+	c.txnId = res.TransactionId
+	c.meterStart = meterStart
+	c.mu.Unlock()
+}
+
+func (c *connector) stopTransaction(txn int) {
+	meterStop, ok := c.meterTotal()
+	if !ok {
+		meterStop = c.meterStart + int(c.lp.GetChargedEnergy())
+	}
+
+	if _, err := c.cp.StopTransaction(meterStop, types.NewDateTime(time.Now()), txn, func(request *ocppcore.StopTransactionRequest) {
+		request.Reason = ocppcore.ReasonEVDisconnected
+	}); err != nil {
+		c.log.ERROR.Printf("connector %d: stop transaction: %v", c.id, err)
+		return
+	}
+
+	c.log.DEBUG.Printf("connector %d: stopped transaction %d", c.id, txn)
+
+	c.mu.Lock()
+	c.txnId = 0
+	remote := c.remote
+	c.mu.Unlock()
+
+	// remote demand applies to the transaction only
+	if remote {
+		c.remoteControl(loadpoint.RemoteEnable)
+	}
+}
+
+// meterValues sends the session's energy, power and soc
+func (c *connector) meterValues() {
+	txn := c.transaction()
+	if txn == 0 {
+		return
+	}
+
+	values := []types.SampledValue{
+		{
+			Value:     strconv.Itoa(int(c.lp.GetChargePower())),
+			Context:   types.ReadingContextSamplePeriodic,
+			Measurand: types.MeasurandPowerActiveImport,
+			Location:  types.LocationOutlet,
+			Unit:      types.UnitOfMeasureW,
+		},
+	}
+
+	// the energy register requires the charge meter's total
+	if total, ok := c.meterTotal(); ok {
+		values = append(values, types.SampledValue{
+			Value:     strconv.Itoa(total),
+			Context:   types.ReadingContextSamplePeriodic,
+			Measurand: types.MeasurandEnergyActiveImportRegister,
+			Location:  types.LocationOutlet,
+			Unit:      types.UnitOfMeasureWh,
+		})
+	}
+
+	if soc := c.lp.GetVehicleSoC(); soc > 0 {
+		values = append(values, types.SampledValue{
+			Value:     strconv.Itoa(int(soc)),
+			Context:   types.ReadingContextSamplePeriodic,
+			Measurand: types.MeasueandSoC,
+			Location:  types.LocationEV,
+			Unit:      types.UnitOfMeasurePercent,
+		})
+	}
+
+	meterValue := types.MeterValue{
+		Timestamp:    types.NewDateTime(time.Now()),
+		SampledValue: values,
+	}
+
+	if _, err := c.cp.MeterValues(c.id, []types.MeterValue{meterValue}, func(request *ocppcore.MeterValuesRequest) {
+		request.TransactionId = &txn
+	}); err != nil {
+		c.log.ERROR.Printf("connector %d: meter values: %v", c.id, err)
+	}
+}
+
+// remoteControl applies the central system's remote demand to the loadpoint
+func (c *connector) remoteControl(demand loadpoint.RemoteDemand) {
+	c.lp.RemoteControl(remoteSource, demand)
+
+	c.mu.Lock()
+	c.remote = demand != loadpoint.RemoteEnable
+	c.mu.Unlock()
+}
@@ -6,9 +6,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/hems/ocpp/profile"
+	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/machine"
 
@@ -17,20 +18,26 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
-// OCPP is an OCPP client
+// OCPP is an OCPP client exposing the site's loadpoints as connectors
 type OCPP struct {
-	log  *util.Logger
-	site site.API
-	cp   ocpp16.ChargePoint
+	log        *util.Logger
+	cp         ocpp16.ChargePoint
+	interval   time.Duration
+	connectors []*connector
 }
 
 const retryTimeout = 5 * time.Second
 
 // New generates OCPP chargepoint client
 func New(conf map[string]interface{}, site site.API) (*OCPP, error) {
-	var cc struct {
+	cc := struct {
 		URI       string
 		StationID string
+		IdTag     string
+		Interval  time.Duration
+	}{
+		IdTag:    "evcc",
+		Interval: time.Minute,
 	}
 
 	if err := util.DecodeOther(conf, &cc); err != nil {
@@ -53,16 +60,26 @@ func New(conf map[string]interface{}, site site.API) (*OCPP, error) {
 	cp := ocpp16.NewChargePoint(cc.StationID, nil, ws)
 
 	s := &OCPP{
-		log:  log,
-		site: site,
-		cp:   cp,
+		log:      log,
+		cp:       cp,
+		interval: cc.Interval,
 	}
 
+	for id, lp := range site.LoadPoints() {
+		s.connectors = append(s.connectors, &connector{
+			log:   log,
+			id:    id + 1,
+			lp:    lp,
+			cp:    cp,
+			idTag: cc.IdTag,
+		})
+	}
+
+	cp.SetCoreHandler(profile.NewCore(log, profile.GetDefaultConfig(len(s.connectors), cc.Interval), s))
+	cp.SetSmartChargingHandler(profile.NewSmartCharging(log, s))
+
 	err := cp.Start(cc.URI)
 	if err == nil {
-		cp.SetCoreHandler(profile.NewCore(log, profile.GetDefaultConfig()))
-		cp.SetSmartChargingHandler(profile.NewSmartCharging(log))
-
 		go s.errorHandler(ws.Errors())
 		go s.errorHandler(cp.Errors())
 	}
@@ -77,21 +94,80 @@ func (s *OCPP) errorHandler(errC <-chan error) {
 	}
 }
 
+// connector returns the connector by id
+func (s *OCPP) connector(id int) *connector {
+	if id < 1 || id > len(s.connectors) {
+		return nil
+	}
+	return s.connectors[id-1]
+}
+
+// apply applies the remote demand to the connector or to all connectors if id is 0
+func (s *OCPP) apply(id int, demand loadpoint.RemoteDemand) bool {
+	if id == 0 {
+		for _, c := range s.connectors {
+			c.remoteControl(demand)
+		}
+		return true
+	}
+
+	c := s.connector(id)
+	if c == nil {
+		return false
+	}
+
+	c.remoteControl(demand)
+	return true
+}
+
+// RemoteStart implements the profile.Controller interface
+func (s *OCPP) RemoteStart(id int) bool {
+	return id > 0 && s.apply(id, loadpoint.RemoteEnable)
+}
+
+// RemoteStop implements the profile.Controller interface
+func (s *OCPP) RemoteStop(transaction int) bool {
+	for _, c := range s.connectors {
+		if c.transaction() == transaction {
+			c.remoteControl(loadpoint.RemoteHardDisable)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suspend implements the profile.Controller interface
+func (s *OCPP) Suspend(id int) bool {
+	return s.apply(id, loadpoint.RemoteSoftDisable)
+}
+
+// ClearLimit implements the profile.Controller interface
+func (s *OCPP) ClearLimit(id int) bool {
+	return s.apply(id, loadpoint.RemoteEnable)
+}
+
 // Run executes the OCPP chargepoint client
 func (s *OCPP) Run() {
+	if _, err := s.cp.BootNotification("evcc", "evcc", func(request *ocppcore.BootNotificationRequest) {
+		request.FirmwareVersion = server.FormattedVersion()
+	}); err != nil {
+		s.log.ERROR.Println("boot notification:", err)
+	}
+
+	var metered time.Time
+
 	for {
-		for id, lp := range s.site.LoadPoints() {
-			connector := id + 1
+		for _, c := range s.connectors {
+			c.update()
+		}
 
-			status := ocppcore.ChargePointStatusAvailable
-			if lp.GetStatus() == api.StatusC {
-				status = ocppcore.ChargePointStatusCharging
+		if time.Since(metered) >= s.interval {
+			for _, c := range s.connectors {
+				c.meterValues()
 			}
 
-			s.log.DEBUG.Printf("send: lp-%d status: %+v", connector, status)
-			if _, err := s.cp.StatusNotification(connector, ocppcore.NoError, status); err != nil {
-				s.log.ERROR.Printf("lp-%d: %v", connector, err)
-			}
+			metered = time.Now()
 		}
 
 		time.Sleep(retryTimeout)
@@ -2,8 +2,11 @@ package profile
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
@@ -42,6 +45,13 @@ const (
 	MaxChargingProfilesInstalled            string = "MaxChargingProfilesInstalled"
 )
 
+// MeterValuesSampled are the measurands sent as meter values
+var MeterValuesSampled = strings.Join([]string{
+	string(types.MeasurandEnergyActiveImportRegister),
+	string(types.MeasurandPowerActiveImport),
+	string(types.MeasueandSoC),
+}, ",")
+
 // ConfigMap defines the active configuration settings
 type ConfigMap map[string]core.ConfigurationKey
 
@@ -93,21 +103,21 @@ func (c ConfigMap) set(key string, readonly bool, value string) {
 	}
 }
 
-func GetDefaultConfig() ConfigMap {
+func GetDefaultConfig(connectors int, interval time.Duration) ConfigMap {
 	intBase := 10
 
 	var cfg ConfigMap = make(map[string]core.ConfigurationKey)
 
 	// readonly
-	cfg.set(SupportedFeatureProfiles, true, core.ProfileName)
+	cfg.set(SupportedFeatureProfiles, true, core.ProfileName+","+smartcharging.ProfileName)
 	cfg.set(AuthorizeRemoteTxRequests, true, strconv.FormatBool(false))
 	cfg.set(GetConfigurationMaxKeys, true, strconv.FormatInt(50, intBase))
-	cfg.set(NumberOfConnectors, true, strconv.FormatInt(1, intBase))
+	cfg.set(NumberOfConnectors, true, strconv.Itoa(connectors))
 	cfg.set(LocalAuthListMaxLength, true, strconv.FormatInt(100, intBase))
 	cfg.set(SendLocalListMaxLength, true, strconv.FormatInt(20, intBase))
 	cfg.set(ChargeProfileMaxStackLevel, true, strconv.FormatInt(10, intBase))
-	cfg.set(ChargingScheduleAllowedChargingRateUnit, true, "Power")
-	cfg.set(ChargingScheduleMaxPeriods, true, strconv.FormatInt(5, intBase))
+	cfg.set(ChargingScheduleAllowedChargingRateUnit, true, "Current,Power")
+	cfg.set(ChargingScheduleMaxPeriods, true, strconv.FormatInt(1, intBase))
 	cfg.set(MaxChargingProfilesInstalled, true, strconv.FormatInt(10, intBase))
 
 	// read/write
@@ -119,13 +129,13 @@ func GetDefaultConfig() ConfigMap {
 	cfg.set(LocalAuthListEnabled, false, strconv.FormatBool(true))
 	cfg.set(LocalPreAuthorize, false, strconv.FormatBool(false))
 	cfg.set(MeterValuesAlignedData, false, string(types.MeasurandEnergyActiveExportRegister))
-	cfg.set(MeterValuesSampledData, false, string(types.MeasurandEnergyActiveExportRegister))
-	cfg.set(MeterValueSampleInterval, false, strconv.FormatInt(5, intBase))
+	cfg.set(MeterValuesSampledData, false, MeterValuesSampled)
+	cfg.set(MeterValueSampleInterval, false, strconv.Itoa(int(interval.Seconds())))
 	cfg.set(ResetRetries, false, strconv.FormatInt(10, intBase))
 	cfg.set(StopTransactionOnEVSideDisconnect, false, strconv.FormatBool(true))
 	cfg.set(StopTransactionOnInvalidID, false, strconv.FormatBool(true))
 	cfg.set(StopTxnAlignedData, false, strconv.FormatBool(true))
-	cfg.set(StopTxnSampledData, false, string(types.MeasurandEnergyActiveExportRegister))
+	cfg.set(StopTxnSampledData, false, string(types.MeasurandEnergyActiveImportRegister))
 	cfg.set(TransactionMessageAttempts, false, strconv.FormatInt(5, intBase))
 	cfg.set(TransactionMessageRetryInterval, false, strconv.FormatInt(60, intBase))
 	cfg.set(UnlockConnectorOnEVSideDisconnect, false, strconv.FormatBool(true))
@@ -6,16 +6,34 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
+// Controller applies the central system's remote control requests to the connectors
+type Controller interface {
+	RemoteStart(connector int) bool
+	RemoteStop(transaction int) bool
+	Suspend(connector int) bool
+	ClearLimit(connector int) bool
+}
+
 type Core struct {
 	log           *util.Logger
 	configuration ConfigMap
+	controller    Controller
 }
 
-func NewCore(log *util.Logger, config ConfigMap) *Core {
+func NewCore(log *util.Logger, config ConfigMap, controller Controller) *Core {
 	return &Core{
 		log:           log,
 		configuration: config,
+		controller:    controller,
+	}
+}
+
+// status converts the result of a remote control request
+func status(ok bool) types.RemoteStartStopStatus {
+	if ok {
+		return types.RemoteStartStopStatusAccepted
 	}
+	return types.RemoteStartStopStatusRejected
 }
 
 // OnChangeAvailability handles the CS message
@@ -33,11 +51,17 @@ func (s *Core) OnUnlockConnector(request *core.UnlockConnectorRequest) (confirma
 // OnRemoteStartTransaction handles the CS message
 func (s *Core) OnRemoteStartTransaction(request *core.RemoteStartTransactionRequest) (confirmation *core.RemoteStartTransactionConfirmation, err error) {
 	s.log.TRACE.Printf("recv: %s %+v", request.GetFeatureName(), request)
-	return core.NewRemoteStartTransactionConfirmation(types.RemoteStartStopStatusRejected), nil
+
+	connector := 1
+	if request.ConnectorId != nil {
+		connector = *request.ConnectorId
+	}
+
+	return core.NewRemoteStartTransactionConfirmation(status(s.controller.RemoteStart(connector))), nil
 }
 
 // OnRemoteStopTransaction handles the CS message
 func (s *Core) OnRemoteStopTransaction(request *core.RemoteStopTransactionRequest) (confirmation *core.RemoteStopTransactionConfirmation, err error) {
 	s.log.TRACE.Printf("recv: %s %+v", request.GetFeatureName(), request)
-	return core.NewRemoteStopTransactionConfirmation(types.RemoteStartStopStatusRejected), nil
+	return core.NewRemoteStopTransactionConfirmation(status(s.controller.RemoteStop(request.TransactionId))), nil
 }
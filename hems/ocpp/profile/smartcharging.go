@@ -3,28 +3,55 @@ package profile
 import (
 	"github.com/evcc-io/evcc/util"
 	sc "github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
 type SmartCharging struct {
-	log *util.Logger
+	log        *util.Logger
+	controller Controller
 }
 
-func NewSmartCharging(log *util.Logger) *SmartCharging {
+func NewSmartCharging(log *util.Logger, controller Controller) *SmartCharging {
 	return &SmartCharging{
-		log: log,
+		log:        log,
+		controller: controller,
 	}
 }
 
+// suspends returns true if the profile suspends charging. Only single period schedules with zero limit
+// are supported since the loadpoints can't follow limits or schedules set by the central system.
+func suspends(profile *types.ChargingProfile) bool {
+	schedule := profile.ChargingSchedule
+	return schedule != nil && len(schedule.ChargingSchedulePeriod) == 1 && schedule.ChargingSchedulePeriod[0].Limit == 0
+}
+
 // OnSetChargingProfile handles the CS message
 func (s *SmartCharging) OnSetChargingProfile(request *sc.SetChargingProfileRequest) (confirmation *sc.SetChargingProfileConfirmation, err error) {
 	s.log.TRACE.Printf("recv: %s %+v", request.GetFeatureName(), request)
-	return sc.NewSetChargingProfileConfirmation(sc.ChargingProfileStatusRejected), nil
+
+	status := sc.ChargingProfileStatusRejected
+	if request.ChargingProfile != nil && suspends(request.ChargingProfile) && s.controller.Suspend(request.ConnectorId) {
+		status = sc.ChargingProfileStatusAccepted
+	}
+
+	return sc.NewSetChargingProfileConfirmation(status), nil
 }
 
 // OnClearChargingProfile handles the CS message
 func (s *SmartCharging) OnClearChargingProfile(request *sc.ClearChargingProfileRequest) (confirmation *sc.ClearChargingProfileConfirmation, err error) {
 	s.log.TRACE.Printf("recv: %s %+v", request.GetFeatureName(), request)
-	return sc.NewClearChargingProfileConfirmation(sc.ClearChargingProfileStatusUnknown), nil
+
+	var connector int
+	if request.ConnectorId != nil {
+		connector = *request.ConnectorId
+	}
+
+	status := sc.ClearChargingProfileStatusUnknown
+	if s.controller.ClearLimit(connector) {
+		status = sc.ClearChargingProfileStatusAccepted
+	}
+
+	return sc.NewClearChargingProfileConfirmation(status), nil
 }
 
 // OnGetCompositeSchedule handles the CS message
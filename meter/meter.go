@@ -13,15 +13,16 @@ func init() {
 	registry.Add(api.Custom, NewConfigurableFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.BatteryController,SetBatteryMode,func(mode api.BatteryMode) error"
 
 // NewConfigurableFromConfig creates api.Meter from config
 func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error) {
 	var cc struct {
-		Power    provider.Config
-		Energy   *provider.Config  // optional
-		SoC      *provider.Config  // optional
-		Currents []provider.Config // optional
+		Power       provider.Config
+		Energy      *provider.Config  // optional
+		SoC         *provider.Config  // optional
+		Currents    []provider.Config // optional
+		BatteryMode *provider.Config  // optional
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -73,7 +74,18 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error)
 		}
 	}
 
-	res := m.Decorate(totalEnergyG, currentsG, batterySoCG)
+	// decorate Meter with BatteryController
+	var batteryModeS func(api.BatteryMode) error
+	if cc.BatteryMode != nil {
+		set, err := provider.NewIntSetterFromConfig("batteryMode", *cc.BatteryMode)
+		if err != nil {
+			return nil, fmt.Errorf("batterymode: %w", err)
+		}
+
+		batteryModeS = batteryModeSetter(set)
+	}
+
+	res := m.Decorate(totalEnergyG, currentsG, batterySoCG, batteryModeS)
 
 	return res, nil
 }
@@ -95,6 +107,22 @@ func collectCurrentProviders(g []func() (float64, error)) func() (float64, float
 	}
 }
 
+// batteryModeSetter converts battery modes into integer values 1 (normal), 2 (hold) and 3 (charge)
+func batteryModeSetter(set func(int64) error) func(api.BatteryMode) error {
+	return func(mode api.BatteryMode) error {
+		switch mode {
+		case api.BatteryNormal:
+			return set(1)
+		case api.BatteryHold:
+			return set(2)
+		case api.BatteryCharge:
+			return set(3)
+		default:
+			return fmt.Errorf("invalid battery mode: %s", mode)
+		}
+	}
+}
+
 // NewConfigurable creates a new meter
 func NewConfigurable(currentPowerG func() (float64, error)) (*Meter, error) {
 	m := &Meter{
@@ -113,8 +141,9 @@ func (m *Meter) Decorate(
 	totalEnergy func() (float64, error),
 	currents func() (float64, float64, float64, error),
 	batterySoC func() (float64, error),
+	batteryMode func(api.BatteryMode) error,
 ) api.Meter {
-	return decorateMeter(m, totalEnergy, currents, batterySoC)
+	return decorateMeter(m, totalEnergy, currents, batterySoC, batteryMode)
 }
 
 // CurrentPower implements the api.Meter interface
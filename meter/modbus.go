@@ -3,8 +3,10 @@ package meter
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/andig/gosunspec/models/model124"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
@@ -26,7 +28,7 @@ func init() {
 	registry.Add("modbus", NewModbusFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateModbus -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateModbus -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.BatteryController,SetBatteryMode,func(mode api.BatteryMode) error"
 
 // NewModbusFromConfig creates api.Meter from config
 func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
@@ -35,6 +37,7 @@ func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
 		modbus.Settings    `mapstructure:",squash"`
 		Power, Energy, SoC string
 		Currents           []string
+		BatteryControl     bool
 		Delay              time.Duration
 		Timeout            time.Duration
 	}{
@@ -142,7 +145,17 @@ func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
 		soc = m.soc
 	}
 
-	return decorateModbus(m, totalEnergy, currentsG, soc), nil
+	// decorate battery control using sunspec storage model
+	var batteryMode func(api.BatteryMode) error
+	if cc.BatteryControl {
+		if _, ok := device.(*sunspec.SunSpec); !ok {
+			return nil, errors.New("battery control requires sunspec device")
+		}
+
+		batteryMode = m.setBatteryMode
+	}
+
+	return decorateModbus(m, totalEnergy, currentsG, soc, batteryMode), nil
 }
 
 // floatGetter executes configured modbus read operation and implements func() (float64, error)
@@ -190,3 +203,48 @@ func (m *Modbus) totalEnergy() (float64, error) {
 func (m *Modbus) soc() (float64, error) {
 	return m.floatGetter(m.opSoC)
 }
+
+// sunspec storage model 124 control values
+const (
+	sunspecStorCtlDischarge = 1 << 1 // StorCtl_Mod: activate discharge limit OutWRte
+	sunspecChaGriSetPV      = 0      // ChaGriSet: charge from PV only
+	sunspecChaGriSetGrid    = 1      // ChaGriSet: allow charging from grid
+)
+
+// setBatteryMode implements the api.BatteryController interface using the sunspec storage model 124.
+// Hold limits discharge to zero, charge reverses the discharge limit and enables grid charging.
+func (m *Modbus) setBatteryMode(mode api.BatteryMode) error {
+	dev := m.device.(*sunspec.SunSpec)
+
+	block, _, err := dev.QueryPointAny(m.conn, 124, 0, model124.StorCtl_Mod)
+	if err != nil {
+		return err
+	}
+
+	// grid charging is only allowed in charge mode
+	block.MustPoint(model124.ChaGriSet).SetEnum16(sunspecChaGriSetPV)
+	points := []string{model124.StorCtl_Mod, model124.ChaGriSet}
+
+	switch mode {
+	case api.BatteryNormal:
+		block.MustPoint(model124.StorCtl_Mod).SetBitfield16(0)
+
+	case api.BatteryHold, api.BatteryCharge:
+		// discharge limit in percent of WChaMax, negative values charge the battery
+		rate := 0.0
+		if mode == api.BatteryCharge {
+			rate = -100
+			block.MustPoint(model124.ChaGriSet).SetEnum16(sunspecChaGriSetGrid)
+		}
+
+		sf := block.MustPoint(model124.InOutWRte_SF).ScaleFactor()
+		block.MustPoint(model124.OutWRte).SetInt16(int16(rate / math.Pow10(int(sf))))
+		block.MustPoint(model124.StorCtl_Mod).SetBitfield16(sunspecStorCtlDischarge)
+		points = append(points, model124.OutWRte)
+
+	default:
+		return fmt.Errorf("invalid battery mode: %s", mode)
+	}
+
+	return block.Write(points...)
+}
@@ -6,12 +6,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error)) api.Meter {
+func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error), batteryController func(mode api.BatteryMode) error) api.Meter {
 	switch {
-	case battery == nil && meterCurrent == nil && meterEnergy == nil:
+	case battery == nil && batteryController == nil && meterCurrent == nil && meterEnergy == nil:
 		return base
 
-	case battery == nil && meterCurrent == nil && meterEnergy != nil:
+	case battery == nil && batteryController == nil && meterCurrent == nil && meterEnergy != nil:
 		return &struct {
 			api.Meter
 			api.MeterEnergy
@@ -22,7 +22,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy == nil:
+	case battery == nil && batteryController == nil && meterCurrent != nil && meterEnergy == nil:
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -33,7 +33,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy != nil:
+	case battery == nil && batteryController == nil && meterCurrent != nil && meterEnergy != nil:
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -48,7 +48,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy == nil:
+	case battery != nil && batteryController == nil && meterCurrent == nil && meterEnergy == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -59,7 +59,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy != nil:
+	case battery != nil && batteryController == nil && meterCurrent == nil && meterEnergy != nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -74,7 +74,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy == nil:
+	case battery != nil && batteryController == nil && meterCurrent != nil && meterEnergy == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -89,7 +89,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy != nil:
+	case battery != nil && batteryController == nil && meterCurrent != nil && meterEnergy != nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -107,6 +107,142 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 				meterEnergy: meterEnergy,
 			},
 		}
+
+	case battery == nil && batteryController != nil && meterCurrent == nil && meterEnergy == nil:
+		return &struct {
+			api.Meter
+			api.BatteryController
+		}{
+			Meter: base,
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			Meter: base,
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			Meter: base,
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent == nil && meterEnergy == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateModbusBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
 	}
 
 	return nil
@@ -120,6 +256,14 @@ func (impl *decorateModbusBatteryImpl) SoC() (float64, error) {
 	return impl.battery()
 }
 
+type decorateModbusBatteryControllerImpl struct {
+	batteryController func(mode api.BatteryMode) error
+}
+
+func (impl *decorateModbusBatteryControllerImpl) SetBatteryMode(mode api.BatteryMode) error {
+	return impl.batteryController(mode)
+}
+
 type decorateModbusMeterCurrentImpl struct {
 	meterCurrent func() (float64, float64, float64, error)
 }
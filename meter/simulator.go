@@ -0,0 +1,37 @@
+package meter
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulator"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.Add("simulator", NewSimulatorFromConfig)
+}
+
+// NewSimulatorFromConfig creates a simulated grid or pv meter from generic config
+func NewSimulatorFromConfig(other map[string]interface{}) (api.Meter, error) {
+	cc := struct {
+		Usage              string
+		simulator.PVConfig `mapstructure:",squash"`
+		Baseload           float64
+	}{
+		Usage: "grid",
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	switch cc.Usage {
+	case "grid":
+		return simulator.Instance().Grid(cc.Baseload), nil
+	case "pv":
+		return simulator.Instance().AddPV(cc.PVConfig)
+	default:
+		return nil, fmt.Errorf("invalid usage: %s", cc.Usage)
+	}
+}
@@ -0,0 +1,217 @@
+package replay
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// duration allows decorating api.ChargeTimer without the generated code importing time
+type duration = time.Duration
+
+// charger is a recorded or replayed charger
+type charger struct {
+	recorded         api.Charger // charger wrapped for recording, nil when replaying
+	status           func() (api.ChargeStatus, error)
+	enabled          func() (bool, error)
+	enable           func(bool) error
+	maxCurrent       func(int64) error
+	loadpointControl func(loadpoint.API)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateCharger -b *charger -r api.Charger -t "api.ChargerEx,MaxCurrentMillis,func(float64) error" -t "api.PhaseSwitcher,Phases1p3p,func(int) error" -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.ChargeRater,ChargedEnergy,func() (float64, error)" -t "api.Identifier,Identify,func() (string, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.BidirectionalCharger,Discharge,func(float64) error" -t "api.Resurrector,WakeUp,func() error" -t "api.ChargeTimer,ChargingTime,func() (duration, error)"
+
+// Status implements the api.Charger interface
+func (c *charger) Status() (api.ChargeStatus, error) {
+	return c.status()
+}
+
+// Enabled implements the api.Charger interface
+func (c *charger) Enabled() (bool, error) {
+	return c.enabled()
+}
+
+// Enable implements the api.Charger interface
+func (c *charger) Enable(enable bool) error {
+	return c.enable(enable)
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *charger) MaxCurrent(current int64) error {
+	return c.maxCurrent(current)
+}
+
+var _ loadpoint.Controller = (*charger)(nil)
+
+// LoadpointControl implements loadpoint.Controller
+func (c *charger) LoadpointControl(lp loadpoint.API) {
+	if c.loadpointControl != nil {
+		c.loadpointControl(lp)
+	}
+}
+
+// Unwrap returns the charger wrapped for recording
+func (c *charger) Unwrap() api.Charger {
+	return c.recorded
+}
+
+// Unwrap returns the charger wrapped for recording, or the charger itself if it is not recorded.
+// Use it for accessing device features that are not recorded, like the OCPP maintenance API.
+func Unwrap(c api.Charger) api.Charger {
+	if rc, ok := c.(interface{ Unwrap() api.Charger }); ok && rc.Unwrap() != nil {
+		return rc.Unwrap()
+	}
+	return c
+}
+
+// Charger wraps the charger for recording
+func (r *Recorder) Charger(name string, c api.Charger) api.Charger {
+	dev := device(kindCharger, name)
+	fs := functions{"Status", "Enabled", "Enable", "MaxCurrent"}
+
+	base := &charger{
+		recorded:   c,
+		status:     recordGet(r, dev, "Status", c.Status),
+		enabled:    recordGet(r, dev, "Enabled", c.Enabled),
+		enable:     recordSet(r, dev, "Enable", c.Enable),
+		maxCurrent: recordSet(r, dev, "MaxCurrent", c.MaxCurrent),
+	}
+
+	if c, ok := c.(loadpoint.Controller); ok {
+		base.loadpointControl = c.LoadpointControl
+	}
+
+	var maxCurrentMillis func(float64) error
+	if c, ok := c.(api.ChargerEx); fs.add("MaxCurrentMillis", ok) {
+		maxCurrentMillis = recordSet(r, dev, "MaxCurrentMillis", c.MaxCurrentMillis)
+	}
+
+	var phases1p3p func(int) error
+	if c, ok := c.(api.PhaseSwitcher); fs.add("Phases1p3p", ok) {
+		phases1p3p = recordSet(r, dev, "Phases1p3p", c.Phases1p3p)
+	}
+
+	var currentPower func() (float64, error)
+	if c, ok := c.(api.Meter); fs.add("CurrentPower", ok) {
+		currentPower = recordGet(r, dev, "CurrentPower", c.CurrentPower)
+	}
+
+	var currents func() (float64, float64, float64, error)
+	if c, ok := c.(api.MeterCurrent); fs.add("Currents", ok) {
+		currents = splitCurrents(recordGet(r, dev, "Currents", joinCurrents(c.Currents)))
+	}
+
+	var totalEnergy func() (float64, error)
+	if c, ok := c.(api.MeterEnergy); fs.add("TotalEnergy", ok) {
+		totalEnergy = recordGet(r, dev, "TotalEnergy", c.TotalEnergy)
+	}
+
+	var chargedEnergy func() (float64, error)
+	if c, ok := c.(api.ChargeRater); fs.add("ChargedEnergy", ok) {
+		chargedEnergy = recordGet(r, dev, "ChargedEnergy", c.ChargedEnergy)
+	}
+
+	var identify func() (string, error)
+	if c, ok := c.(api.Identifier); fs.add("Identify", ok) {
+		identify = recordGet(r, dev, "Identify", c.Identify)
+	}
+
+	var soc func() (float64, error)
+	if c, ok := c.(api.Battery); fs.add("SoC", ok) {
+		soc = recordGet(r, dev, "SoC", c.SoC)
+	}
+
+	var discharge func(float64) error
+	if c, ok := c.(api.BidirectionalCharger); fs.add("Discharge", ok) {
+		discharge = recordSet(r, dev, "Discharge", c.Discharge)
+	}
+
+	var wakeUp func() error
+	if c, ok := c.(api.Resurrector); fs.add("WakeUp", ok) {
+		wakeUp = recordCall(r, dev, "WakeUp", c.WakeUp)
+	}
+
+	var chargingTime func() (time.Duration, error)
+	if c, ok := c.(api.ChargeTimer); fs.add("ChargingTime", ok) {
+		chargingTime = recordGet(r, dev, "ChargingTime", c.ChargingTime)
+	}
+
+	r.write(Event{Device: dev, Functions: fs})
+
+	return decorateCharger(base, maxCurrentMillis, phases1p3p, currentPower, currents, totalEnergy, chargedEnergy, identify, soc, discharge, wakeUp, chargingTime)
+}
+
+// Charger creates a stand-in for the recorded charger
+func (p *Player) Charger(name string) (api.Charger, error) {
+	dev := device(kindCharger, name)
+
+	_, fs, err := p.header(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &charger{
+		status:     replayGet[api.ChargeStatus](p, dev, "Status"),
+		enabled:    replayGet[bool](p, dev, "Enabled"),
+		enable:     replaySet[bool](p, dev, "Enable"),
+		maxCurrent: replaySet[int64](p, dev, "MaxCurrent"),
+	}
+
+	var maxCurrentMillis func(float64) error
+	if fs["MaxCurrentMillis"] {
+		maxCurrentMillis = replaySet[float64](p, dev, "MaxCurrentMillis")
+	}
+
+	var phases1p3p func(int) error
+	if fs["Phases1p3p"] {
+		phases1p3p = replaySet[int](p, dev, "Phases1p3p")
+	}
+
+	var currentPower func() (float64, error)
+	if fs["CurrentPower"] {
+		currentPower = replayGet[float64](p, dev, "CurrentPower")
+	}
+
+	var currents func() (float64, float64, float64, error)
+	if fs["Currents"] {
+		currents = splitCurrents(replayGet[[3]float64](p, dev, "Currents"))
+	}
+
+	var totalEnergy func() (float64, error)
+	if fs["TotalEnergy"] {
+		totalEnergy = replayGet[float64](p, dev, "TotalEnergy")
+	}
+
+	var chargedEnergy func() (float64, error)
+	if fs["ChargedEnergy"] {
+		chargedEnergy = replayGet[float64](p, dev, "ChargedEnergy")
+	}
+
+	var identify func() (string, error)
+	if fs["Identify"] {
+		identify = replayGet[string](p, dev, "Identify")
+	}
+
+	var soc func() (float64, error)
+	if fs["SoC"] {
+		soc = replayGet[float64](p, dev, "SoC")
+	}
+
+	var discharge func(float64) error
+	if fs["Discharge"] {
+		discharge = replaySet[float64](p, dev, "Discharge")
+	}
+
+	var wakeUp func() error
+	if fs["WakeUp"] {
+		wakeUp = replayCall(p, dev, "WakeUp")
+	}
+
+	var chargingTime func() (time.Duration, error)
+	if fs["ChargingTime"] {
+		chargingTime = replayGet[time.Duration](p, dev, "ChargingTime")
+	}
+
+	return decorateCharger(base, maxCurrentMillis, phases1p3p, currentPower, currents, totalEnergy, chargedEnergy, identify, soc, discharge, wakeUp, chargingTime), nil
+}
@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// device kinds
+const (
+	kindMeter   = "meter"
+	kindCharger = "charger"
+	kindVehicle = "vehicle"
+)
+
+// setters are the recorded functions changing device state
+var setters = map[string]bool{
+	"Enable":           true,
+	"MaxCurrent":       true,
+	"MaxCurrentMillis": true,
+	"Phases1p3p":       true,
+	"Discharge":        true,
+	"WakeUp":           true,
+	"SetBatteryMode":   true,
+}
+
+// Event is a single line of a recording. Header events without function list the device's
+// recorded functions and properties, all other events record a function call.
+type Event struct {
+	Time      int64              `json:"t"`           // unix milliseconds
+	Device    string             `json:"d"`           // kind:name
+	Function  string             `json:"f,omitempty"` // function name
+	Value     json.RawMessage    `json:"v,omitempty"` // getter result or setter argument
+	Error     string             `json:"e,omitempty"` // error
+	Functions []string           `json:"i,omitempty"` // header: recorded functions
+	Vehicle   *VehicleProperties `json:"p,omitempty"` // header: vehicle properties
+}
+
+// VehicleProperties are the vehicle's static properties
+type VehicleProperties struct {
+	Title        string           `json:"title"`
+	Capacity     float64          `json:"capacity,omitempty"`
+	Phases       int              `json:"phases,omitempty"`
+	Identifiers  []string         `json:"identifiers,omitempty"`
+	OnIdentified api.ActionConfig `json:"onIdentified"`
+	Features     []string         `json:"features,omitempty"`
+}
+
+// device returns the device key of the given kind and name
+func device(kind, name string) string {
+	return kind + ":" + name
+}
+
+// sentinels are errors whose identity is preserved during replay
+var sentinels = []error{api.ErrNotAvailable, api.ErrMustRetry, api.ErrTimeout, api.ErrSponsorRequired, api.ErrMissingCredentials}
+
+// replayError converts the recorded error message into an error
+func replayError(msg string) error {
+	for _, err := range sentinels {
+		if msg == err.Error() {
+			return err
+		}
+	}
+
+	return errors.New(msg)
+}
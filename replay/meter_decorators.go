@@ -0,0 +1,281 @@
+package replay
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateMeter(base *meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error), batteryController func(api.BatteryMode) error) api.Meter {
+	switch {
+	case battery == nil && batteryController == nil && meterCurrent == nil && meterEnergy == nil:
+		return base
+
+	case battery == nil && batteryController == nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.MeterEnergy
+		}{
+			meter: base,
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && batteryController == nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.MeterCurrent
+		}{
+			meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && batteryController == nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController == nil && meterCurrent == nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.Battery
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+		}
+
+	case battery != nil && batteryController == nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.MeterEnergy
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController == nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.MeterCurrent
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && batteryController == nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent == nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.BatteryController
+		}{
+			meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && batteryController != nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent == nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.BatteryController
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent == nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent != nil && meterEnergy == nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && batteryController != nil && meterCurrent != nil && meterEnergy != nil:
+		return &struct {
+			*meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateMeterBatteryImpl struct {
+	battery func() (float64, error)
+}
+
+func (impl *decorateMeterBatteryImpl) SoC() (float64, error) {
+	return impl.battery()
+}
+
+type decorateMeterBatteryControllerImpl struct {
+	batteryController func(api.BatteryMode) error
+}
+
+func (impl *decorateMeterBatteryControllerImpl) SetBatteryMode(p0 api.BatteryMode) error {
+	return impl.batteryController(p0)
+}
+
+type decorateMeterMeterCurrentImpl struct {
+	meterCurrent func() (float64, float64, float64, error)
+}
+
+func (impl *decorateMeterMeterCurrentImpl) Currents() (float64, float64, float64, error) {
+	return impl.meterCurrent()
+}
+
+type decorateMeterMeterEnergyImpl struct {
+	meterEnergy func() (float64, error)
+}
+
+func (impl *decorateMeterMeterEnergyImpl) TotalEnergy() (float64, error) {
+	return impl.meterEnergy()
+}
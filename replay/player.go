@@ -0,0 +1,256 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+)
+
+// Player provides stand-in devices answering from a recording at the time of the given clock
+type Player struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	start, end time.Time
+	headers    map[string]Event
+	values     map[string][]Event // getter results by device and function
+	recorded   []Event            // recorded setter calls
+	replayed   []Event            // setter calls during replay
+}
+
+// Load reads a recording
+func Load(r io.Reader, clock clock.Clock) (*Player, error) {
+	p := &Player{
+		clock:   clock,
+		headers: make(map[string]Event),
+		values:  make(map[string][]Event),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, err
+		}
+
+		ts := time.UnixMilli(ev.Time)
+		if p.start.IsZero() {
+			p.start = ts
+		}
+		p.end = ts
+
+		switch {
+		case ev.Function == "":
+			p.headers[ev.Device] = ev
+		case setters[ev.Function]:
+			p.recorded = append(p.recorded, ev)
+		default:
+			key := ev.Device + "." + ev.Function
+			p.values[key] = append(p.values[key], ev)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.start.IsZero() {
+		return nil, errors.New("empty recording")
+	}
+
+	return p, nil
+}
+
+// LoadFile reads a recording file. Files ending in .gz are decompressed.
+func LoadFile(name string, clock clock.Clock) (*Player, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		r = zr
+	}
+
+	return Load(r, clock)
+}
+
+// Start returns the time of the first recorded event
+func (p *Player) Start() time.Time {
+	return p.start
+}
+
+// End returns the time of the last recorded event
+func (p *Player) End() time.Time {
+	return p.end
+}
+
+// header returns the device's recorded functions
+func (p *Player) header(dev string) (Event, map[string]bool, error) {
+	h, ok := p.headers[dev]
+	if !ok {
+		return h, nil, fmt.Errorf("not recorded: %s", dev)
+	}
+
+	fs := make(map[string]bool, len(h.Functions))
+	for _, f := range h.Functions {
+		fs[f] = true
+	}
+
+	return h, fs, nil
+}
+
+// lookup returns the event recorded closest to the current time
+func (p *Player) lookup(dev, fn string) (Event, bool) {
+	events := p.values[dev+"."+fn]
+	if len(events) == 0 {
+		return Event{}, false
+	}
+
+	now := p.clock.Now().UnixMilli()
+	idx := sort.Search(len(events), func(i int) bool {
+		return events[i].Time >= now
+	})
+
+	switch {
+	case idx == len(events):
+		idx--
+	case idx > 0 && now-events[idx-1].Time < events[idx].Time-now:
+		idx--
+	}
+
+	return events[idx], true
+}
+
+// replayGet returns the recorded getter results
+func replayGet[T any](p *Player, dev, fn string) func() (T, error) {
+	return func() (T, error) {
+		var res T
+
+		ev, ok := p.lookup(dev, fn)
+		if !ok {
+			return res, api.ErrNotAvailable
+		}
+
+		if ev.Error != "" {
+			return res, replayError(ev.Error)
+		}
+
+		err := json.Unmarshal(ev.Value, &res)
+
+		return res, err
+	}
+}
+
+// replaySet collects the setter calls for comparison with the recording
+func replaySet[T any](p *Player, dev, fn string) func(T) error {
+	return func(val T) error {
+		b, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.replayed = append(p.replayed, Event{
+			Time:     p.clock.Now().UnixMilli(),
+			Device:   dev,
+			Function: fn,
+			Value:    b,
+		})
+
+		return nil
+	}
+}
+
+// replayCall collects the calls without arguments for comparison with the recording
+func replayCall(p *Player, dev, fn string) func() error {
+	set := replaySet[any](p, dev, fn)
+	return func() error {
+		return set(nil)
+	}
+}
+
+// call returns the event's call without time and error
+func call(ev Event) string {
+	return fmt.Sprintf("%s %s %s", ev.Device, ev.Function, ev.Value)
+}
+
+// Diff writes the differences between recorded and replayed setter calls and returns their number
+func (p *Player) Diff(w io.Writer) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	a, b := p.recorded, p.replayed
+
+	keys := func(events []Event) []string {
+		res := make([]string, len(events))
+		for i, ev := range events {
+			res[i] = call(ev)
+		}
+		return res
+	}
+	ka, kb := keys(a), keys(b)
+
+	// longest common subsequence
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if ka[i] == kb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diffs int
+	print := func(prefix string, ev Event) {
+		fmt.Fprintf(w, "%s %s %s\n", prefix, time.UnixMilli(ev.Time).Format("2006-01-02 15:04:05"), call(ev))
+		diffs++
+	}
+
+	var i, j int
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && ka[i] == kb[j]:
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			print("-", a[i])
+			i++
+		default:
+			print("+", b[j])
+			j++
+		}
+	}
+
+	return diffs
+}
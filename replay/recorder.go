@@ -0,0 +1,160 @@
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Recorder writes device function calls to a recording
+type Recorder struct {
+	mu     sync.Mutex
+	log    *util.Logger
+	clock  clock.Clock
+	enc    *json.Encoder
+	closer []io.Closer
+	failed bool
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer, clock clock.Clock) *Recorder {
+	return &Recorder{
+		log:   util.NewLogger("record"),
+		clock: clock,
+		enc:   json.NewEncoder(w),
+	}
+}
+
+// Create creates a recording file. Files ending in .gz are compressed.
+func Create(name string) (*Recorder, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, ".gz") {
+		r := NewRecorder(f, clock.New())
+		r.closer = []io.Closer{f}
+		return r, nil
+	}
+
+	zw := gzip.NewWriter(f)
+	r := NewRecorder(zw, clock.New())
+	r.closer = []io.Closer{zw, f}
+
+	return r, nil
+}
+
+// Close closes the recording file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// discard further events
+	r.enc = nil
+
+	var err error
+	for _, c := range r.closer {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// write writes a single event
+func (r *Recorder) write(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.enc == nil {
+		return
+	}
+
+	ev.Time = r.clock.Now().UnixMilli()
+
+	// log first failure only
+	if err := r.enc.Encode(ev); err != nil && !r.failed {
+		r.log.ERROR.Println(err)
+		r.failed = true
+	}
+}
+
+// call writes a function call's value and error
+func (r *Recorder) call(dev, fn string, val any, err error) {
+	ev := Event{
+		Device:   dev,
+		Function: fn,
+	}
+
+	if err != nil {
+		ev.Error = err.Error()
+	}
+
+	// skip zero getter values on error
+	if err == nil || setters[fn] {
+		ev.Value, _ = json.Marshal(val)
+	}
+
+	r.write(ev)
+}
+
+// recordGet records the getter's results
+func recordGet[T any](r *Recorder, dev, fn string, f func() (T, error)) func() (T, error) {
+	return func() (T, error) {
+		res, err := f()
+		r.call(dev, fn, res, err)
+		return res, err
+	}
+}
+
+// recordSet records the setter's arguments
+func recordSet[T any](r *Recorder, dev, fn string, f func(T) error) func(T) error {
+	return func(val T) error {
+		err := f(val)
+		r.call(dev, fn, val, err)
+		return err
+	}
+}
+
+// recordCall records calls without arguments
+func recordCall(r *Recorder, dev, fn string, f func() error) func() error {
+	set := recordSet(r, dev, fn, func(any) error { return f() })
+	return func() error {
+		return set(nil)
+	}
+}
+
+// functions collects the names of recorded functions
+type functions []string
+
+// add adds the function name if the function exists
+func (fs *functions) add(name string, ok bool) bool {
+	if ok {
+		*fs = append(*fs, name)
+	}
+	return ok
+}
+
+// joinCurrents converts per-phase currents into an array
+func joinCurrents(f func() (float64, float64, float64, error)) func() ([3]float64, error) {
+	return func() ([3]float64, error) {
+		l1, l2, l3, err := f()
+		return [3]float64{l1, l2, l3}, err
+	}
+}
+
+// splitCurrents converts an array into per-phase currents
+func splitCurrents(f func() ([3]float64, error)) func() (float64, float64, float64, error) {
+	return func() (float64, float64, float64, error) {
+		res, err := f()
+		return res[0], res[1], res[2], err
+	}
+}
@@ -0,0 +1,201 @@
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMeter struct {
+	power float64
+	err   error
+}
+
+func (m *testMeter) CurrentPower() (float64, error) {
+	return m.power, m.err
+}
+
+func (m *testMeter) Currents() (float64, float64, float64, error) {
+	return 1, 2, 3, nil
+}
+
+type testCharger struct {
+	status  api.ChargeStatus
+	enabled bool
+	current float64
+}
+
+func (c *testCharger) Status() (api.ChargeStatus, error) {
+	return c.status, nil
+}
+
+func (c *testCharger) Enabled() (bool, error) {
+	return c.enabled, nil
+}
+
+func (c *testCharger) Enable(enable bool) error {
+	c.enabled = enable
+	return nil
+}
+
+func (c *testCharger) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+func (c *testCharger) MaxCurrentMillis(current float64) error {
+	c.current = current
+	return nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	clck := clock.NewMock()
+	start := clck.Now()
+
+	var buf bytes.Buffer
+	r := NewRecorder(&buf, clck)
+
+	tm := &testMeter{power: 1000}
+	m := r.Meter("grid", tm)
+
+	tc := &testCharger{status: api.StatusB}
+	c := r.Charger("wb", tc)
+
+	_, ok := m.(api.MeterCurrent)
+	assert.True(t, ok, "meter currents")
+	_, ok = c.(api.ChargerEx)
+	assert.True(t, ok, "charger ex")
+	_, ok = c.(api.PhaseSwitcher)
+	assert.False(t, ok, "phase switcher")
+
+	_, _ = m.CurrentPower()
+	_, _, _, _ = m.(api.MeterCurrent).Currents()
+	_, _ = c.Status()
+	require.NoError(t, c.Enable(true))
+
+	clck.Add(time.Minute)
+	tm.power = -2000
+	tc.status = api.StatusC
+	_, _ = m.CurrentPower()
+	_, _ = c.Status()
+	require.NoError(t, c.(api.ChargerEx).MaxCurrentMillis(6.5))
+
+	clck.Add(time.Minute)
+	tm.err = api.ErrMustRetry
+	_, _ = m.CurrentPower()
+
+	replayClock := clock.NewMock()
+	p, err := Load(&buf, replayClock)
+	require.NoError(t, err)
+	assert.Equal(t, start, p.Start())
+	assert.Equal(t, start.Add(2*time.Minute), p.End())
+
+	_, err = p.Vehicle("car")
+	assert.Error(t, err)
+
+	pm, err := p.Meter("grid")
+	require.NoError(t, err)
+	pc, err := p.Charger("wb")
+	require.NoError(t, err)
+
+	_, ok = pm.(api.MeterCurrent)
+	assert.True(t, ok, "replayed meter currents")
+	_, ok = pm.(api.Battery)
+	assert.False(t, ok, "replayed meter battery")
+	_, ok = pc.(api.ChargerEx)
+	assert.True(t, ok, "replayed charger ex")
+
+	for _, tc := range []struct {
+		offset time.Duration
+		power  float64
+		status api.ChargeStatus
+		err    error
+	}{
+		{0, 1000, api.StatusB, nil},
+		{29 * time.Second, 1000, api.StatusB, nil},
+		{31 * time.Second, -2000, api.StatusC, nil},
+		{time.Minute, -2000, api.StatusC, nil},
+		{time.Hour, 0, api.StatusC, api.ErrMustRetry},
+	} {
+		replayClock.Set(start.Add(tc.offset))
+
+		power, err := pm.CurrentPower()
+		assert.True(t, errors.Is(err, tc.err), tc.offset)
+		assert.Equal(t, tc.power, power, tc.offset)
+
+		status, err := pc.Status()
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, status, tc.offset)
+	}
+
+	l1, l2, l3, err := pm.(api.MeterCurrent).Currents()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, []float64{l1, l2, l3})
+
+	// replay with different decision
+	replayClock.Set(start)
+	require.NoError(t, pc.Enable(true))
+	replayClock.Set(start.Add(time.Minute))
+	require.NoError(t, pc.(api.ChargerEx).MaxCurrentMillis(7))
+
+	var diff strings.Builder
+	assert.Equal(t, 2, p.Diff(&diff))
+	lines := strings.Split(strings.TrimSpace(diff.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "- ") && strings.HasSuffix(lines[0], "charger:wb MaxCurrentMillis 6.5"), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "+ ") && strings.HasSuffix(lines[1], "charger:wb MaxCurrentMillis 7"), lines[1])
+}
+
+type testBidiCharger struct {
+	testCharger
+	discharge float64
+}
+
+func (c *testBidiCharger) SoC() (float64, error) {
+	return 42, nil
+}
+
+func (c *testBidiCharger) Discharge(current float64) error {
+	c.discharge = current
+	return nil
+}
+
+func TestRecordReplayBidirectional(t *testing.T) {
+	clck := clock.NewMock()
+
+	var buf bytes.Buffer
+	r := NewRecorder(&buf, clck)
+
+	tc := &testBidiCharger{}
+	c := r.Charger("wb", tc)
+	assert.Equal(t, api.Charger(tc), Unwrap(c))
+
+	_, ok := c.(api.Resurrector)
+	assert.False(t, ok, "resurrector")
+
+	soc, err := c.(api.Battery).SoC()
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, soc)
+	require.NoError(t, c.(api.BidirectionalCharger).Discharge(-6))
+	assert.Equal(t, -6.0, tc.discharge)
+
+	p, err := Load(&buf, clock.NewMock())
+	require.NoError(t, err)
+
+	pc, err := p.Charger("wb")
+	require.NoError(t, err)
+	assert.Equal(t, pc, Unwrap(pc))
+
+	soc, err = pc.(api.Battery).SoC()
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, soc)
+	require.NoError(t, pc.(api.BidirectionalCharger).Discharge(-6))
+
+	assert.Zero(t, p.Diff(new(bytes.Buffer)))
+}
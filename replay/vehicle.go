@@ -0,0 +1,151 @@
+package replay
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// vehicle is a recorded or replayed vehicle
+type vehicle struct {
+	VehicleProperties
+	features []api.Feature
+	soc      func() (float64, error)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateVehicle -b *vehicle -r api.Vehicle -t "api.ChargeState,Status,func() (api.ChargeStatus, error)" -t "api.VehicleRange,Range,func() (int64, error)" -t "api.VehicleOdometer,Odometer,func() (float64, error)" -t "api.SocLimiter,TargetSoC,func() (float64, error)"
+
+// Title implements the api.Vehicle interface
+func (v *vehicle) Title() string {
+	return v.VehicleProperties.Title
+}
+
+// Capacity implements the api.Vehicle interface
+func (v *vehicle) Capacity() float64 {
+	return v.VehicleProperties.Capacity
+}
+
+// Phases implements the api.Vehicle interface
+func (v *vehicle) Phases() int {
+	return v.VehicleProperties.Phases
+}
+
+// Identifiers implements the api.Vehicle interface
+func (v *vehicle) Identifiers() []string {
+	return v.VehicleProperties.Identifiers
+}
+
+// OnIdentified implements the api.Vehicle interface
+func (v *vehicle) OnIdentified() api.ActionConfig {
+	return v.VehicleProperties.OnIdentified
+}
+
+// SoC implements the api.Vehicle interface
+func (v *vehicle) SoC() (float64, error) {
+	return v.soc()
+}
+
+var _ api.FeatureDescriber = (*vehicle)(nil)
+
+// Features implements the api.FeatureDescriber interface
+func (v *vehicle) Features() []api.Feature {
+	return v.features
+}
+
+// Has implements the api.FeatureDescriber interface
+func (v *vehicle) Has(f api.Feature) bool {
+	return slices.Contains(v.features, f)
+}
+
+// Vehicle wraps the vehicle for recording
+func (r *Recorder) Vehicle(name string, v api.Vehicle) api.Vehicle {
+	dev := device(kindVehicle, name)
+	fs := functions{"SoC"}
+
+	base := &vehicle{
+		VehicleProperties: VehicleProperties{
+			Title:        v.Title(),
+			Capacity:     v.Capacity(),
+			Phases:       v.Phases(),
+			Identifiers:  v.Identifiers(),
+			OnIdentified: v.OnIdentified(),
+		},
+		soc: recordGet(r, dev, "SoC", v.SoC),
+	}
+
+	if v, ok := v.(api.FeatureDescriber); ok {
+		base.features = v.Features()
+		for _, f := range base.features {
+			base.VehicleProperties.Features = append(base.VehicleProperties.Features, f.String())
+		}
+	}
+
+	var status func() (api.ChargeStatus, error)
+	if v, ok := v.(api.ChargeState); fs.add("Status", ok) {
+		status = recordGet(r, dev, "Status", v.Status)
+	}
+
+	var rng func() (int64, error)
+	if v, ok := v.(api.VehicleRange); fs.add("Range", ok) {
+		rng = recordGet(r, dev, "Range", v.Range)
+	}
+
+	var odometer func() (float64, error)
+	if v, ok := v.(api.VehicleOdometer); fs.add("Odometer", ok) {
+		odometer = recordGet(r, dev, "Odometer", v.Odometer)
+	}
+
+	var targetSoC func() (float64, error)
+	if v, ok := v.(api.SocLimiter); fs.add("TargetSoC", ok) {
+		targetSoC = recordGet(r, dev, "TargetSoC", v.TargetSoC)
+	}
+
+	r.write(Event{Device: dev, Functions: fs, Vehicle: &base.VehicleProperties})
+
+	return decorateVehicle(base, status, rng, odometer, targetSoC)
+}
+
+// Vehicle creates a stand-in for the recorded vehicle
+func (p *Player) Vehicle(name string) (api.Vehicle, error) {
+	dev := device(kindVehicle, name)
+
+	h, fs, err := p.header(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &vehicle{
+		soc: replayGet[float64](p, dev, "SoC"),
+	}
+
+	if h.Vehicle != nil {
+		base.VehicleProperties = *h.Vehicle
+	}
+
+	for _, s := range base.VehicleProperties.Features {
+		if f, err := api.FeatureString(s); err == nil {
+			base.features = append(base.features, f)
+		}
+	}
+
+	var status func() (api.ChargeStatus, error)
+	if fs["Status"] {
+		status = replayGet[api.ChargeStatus](p, dev, "Status")
+	}
+
+	var rng func() (int64, error)
+	if fs["Range"] {
+		rng = replayGet[int64](p, dev, "Range")
+	}
+
+	var odometer func() (float64, error)
+	if fs["Odometer"] {
+		odometer = replayGet[float64](p, dev, "Odometer")
+	}
+
+	var targetSoC func() (float64, error)
+	if fs["TargetSoC"] {
+		targetSoC = replayGet[float64](p, dev, "TargetSoC")
+	}
+
+	return decorateVehicle(base, status, rng, odometer, targetSoC), nil
+}
@@ -0,0 +1,281 @@
+package replay
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateVehicle(base *vehicle, chargeState func() (api.ChargeStatus, error), vehicleRange func() (int64, error), vehicleOdometer func() (float64, error), socLimiter func() (float64, error)) api.Vehicle {
+	switch {
+	case chargeState == nil && socLimiter == nil && vehicleOdometer == nil && vehicleRange == nil:
+		return base
+
+	case chargeState != nil && socLimiter == nil && vehicleOdometer == nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+		}
+
+	case chargeState == nil && socLimiter == nil && vehicleOdometer == nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.VehicleRange
+		}{
+			vehicle: base,
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState != nil && socLimiter == nil && vehicleOdometer == nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.VehicleRange
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState == nil && socLimiter == nil && vehicleOdometer != nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.VehicleOdometer
+		}{
+			vehicle: base,
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+		}
+
+	case chargeState != nil && socLimiter == nil && vehicleOdometer != nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.VehicleOdometer
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+		}
+
+	case chargeState == nil && socLimiter == nil && vehicleOdometer != nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.VehicleOdometer
+			api.VehicleRange
+		}{
+			vehicle: base,
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState != nil && socLimiter == nil && vehicleOdometer != nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.VehicleOdometer
+			api.VehicleRange
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState == nil && socLimiter != nil && vehicleOdometer == nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.SocLimiter
+		}{
+			vehicle: base,
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+		}
+
+	case chargeState != nil && socLimiter != nil && vehicleOdometer == nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.SocLimiter
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+		}
+
+	case chargeState == nil && socLimiter != nil && vehicleOdometer == nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.SocLimiter
+			api.VehicleRange
+		}{
+			vehicle: base,
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState != nil && socLimiter != nil && vehicleOdometer == nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.SocLimiter
+			api.VehicleRange
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState == nil && socLimiter != nil && vehicleOdometer != nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.SocLimiter
+			api.VehicleOdometer
+		}{
+			vehicle: base,
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+		}
+
+	case chargeState != nil && socLimiter != nil && vehicleOdometer != nil && vehicleRange == nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.SocLimiter
+			api.VehicleOdometer
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+		}
+
+	case chargeState == nil && socLimiter != nil && vehicleOdometer != nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.SocLimiter
+			api.VehicleOdometer
+			api.VehicleRange
+		}{
+			vehicle: base,
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case chargeState != nil && socLimiter != nil && vehicleOdometer != nil && vehicleRange != nil:
+		return &struct {
+			*vehicle
+			api.ChargeState
+			api.SocLimiter
+			api.VehicleOdometer
+			api.VehicleRange
+		}{
+			vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			SocLimiter: &decorateVehicleSocLimiterImpl{
+				socLimiter: socLimiter,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateVehicleChargeStateImpl struct {
+	chargeState func() (api.ChargeStatus, error)
+}
+
+func (impl *decorateVehicleChargeStateImpl) Status() (api.ChargeStatus, error) {
+	return impl.chargeState()
+}
+
+type decorateVehicleSocLimiterImpl struct {
+	socLimiter func() (float64, error)
+}
+
+func (impl *decorateVehicleSocLimiterImpl) TargetSoC() (float64, error) {
+	return impl.socLimiter()
+}
+
+type decorateVehicleVehicleOdometerImpl struct {
+	vehicleOdometer func() (float64, error)
+}
+
+func (impl *decorateVehicleVehicleOdometerImpl) Odometer() (float64, error) {
+	return impl.vehicleOdometer()
+}
+
+type decorateVehicleVehicleRangeImpl struct {
+	vehicleRange func() (int64, error)
+}
+
+func (impl *decorateVehicleVehicleRangeImpl) Range() (int64, error) {
+	return impl.vehicleRange()
+}
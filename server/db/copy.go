@@ -0,0 +1,59 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Copy copies all records of the given models from source to destination database.
+// Existing records are overwritten.
+func Copy(src, dst *gorm.DB, models ...any) error {
+	for _, model := range models {
+		if err := dst.AutoMigrate(model); err != nil {
+			return err
+		}
+
+		rows := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+		if err := src.Find(rows.Interface()).Error; err != nil {
+			return err
+		}
+
+		if rows.Elem().Len() == 0 {
+			continue
+		}
+
+		if err := dst.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows.Interface(), 100).Error; err != nil {
+			return err
+		}
+
+		if err := resetSequence(dst, model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetSequence advances postgres' auto increment sequence after inserting explicit primary keys
+func resetSequence(db *gorm.DB, model any) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil || !field.AutoIncrement {
+		return nil
+	}
+
+	table, column := stmt.Schema.Table, field.DBName
+
+	return db.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), (SELECT MAX(%s) FROM %s))", table, column, column, table)).Error
+}
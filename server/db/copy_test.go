@@ -0,0 +1,42 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID    uint `gorm:"primarykey"`
+	Value string
+}
+
+func TestCopy(t *testing.T) {
+	dir := t.TempDir()
+
+	src, err := New("sqlite", filepath.Join(dir, "src.db"))
+	require.NoError(t, err)
+
+	dst, err := New("sqlite", filepath.Join(dir, "dst.db"))
+	require.NoError(t, err)
+
+	require.NoError(t, src.AutoMigrate(new(record)))
+	require.NoError(t, src.Create(&[]record{{Value: "foo"}, {Value: "bar"}}).Error)
+
+	// existing records are overwritten
+	require.NoError(t, dst.AutoMigrate(new(record)))
+	require.NoError(t, dst.Create(&record{ID: 1, Value: "baz"}).Error)
+
+	require.NoError(t, Copy(src, dst, new(record)))
+
+	var res []record
+	require.NoError(t, dst.Order("id").Find(&res).Error)
+	assert.Equal(t, []record{{1, "foo"}, {2, "bar"}}, res)
+
+	// repeated migration is idempotent
+	require.NoError(t, Copy(src, dst, new(record)))
+	require.NoError(t, dst.Order("id").Find(&res).Error)
+	assert.Len(t, res, 2)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/glebarez/sqlite"
 	"github.com/mitchellh/go-homedir"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -28,12 +30,12 @@ func New(driver, dsn string) (*gorm.DB, error) {
 		}
 		// avoid busy errors
 		dialect = sqlite.Open(file + "?_pragma=busy_timeout(5000)")
-	// case "postgres":
-	// 	dialect = postgres.Open(dsn)
-	// case "mysql":
-	// 	dialect = mysql.Open(dsn)
+	case "postgres":
+		dialect = postgres.Open(dsn)
+	case "mysql":
+		dialect = mysql.Open(dsn)
 	default:
-		return nil, fmt.Errorf("invalid database type: %s not in [sqlite]", driver)
+		return nil, fmt.Errorf("invalid database type: %s not in [sqlite, postgres, mysql]", driver)
 	}
 
 	return gorm.Open(dialect, &gorm.Config{
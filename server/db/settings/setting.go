@@ -9,6 +9,7 @@ import (
 
 	"github.com/evcc-io/evcc/server/db"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 var ErrNotFound = errors.New("not found")
@@ -128,3 +129,8 @@ func Json(key string, res any) error {
 	}
 	return err
 }
+
+// Migrate copies all settings from source to destination database
+func Migrate(src, dst *gorm.DB) error {
+	return db.Copy(src, dst, new(setting))
+}
@@ -0,0 +1,404 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/util"
+)
+
+// haCleanupDelay is the time to wait for all values being published before removing stale entities
+const haCleanupDelay = time.Minute
+
+// haEntity describes a published topic as Home Assistant entity
+type haEntity struct {
+	Component   string // sensor, binary_sensor, number or select
+	Name        string
+	Unit        string
+	DeviceClass string
+	StateClass  string
+	Writable    bool // command topic is <key>/set
+	Min, Max    float64
+	Step        float64
+	Options     []string
+}
+
+func haSensor(name, unit, deviceClass, stateClass string) haEntity {
+	return haEntity{Component: "sensor", Name: name, Unit: unit, DeviceClass: deviceClass, StateClass: stateClass}
+}
+
+func haBinarySensor(name, deviceClass string) haEntity {
+	return haEntity{Component: "binary_sensor", Name: name, DeviceClass: deviceClass}
+}
+
+func haNumber(name, unit string, min, max, step float64) haEntity {
+	return haEntity{Component: "number", Name: name, Unit: unit, Writable: true, Min: min, Max: max, Step: step}
+}
+
+func haSelect(name string, options ...string) haEntity {
+	return haEntity{Component: "select", Name: name, Writable: true, Options: options}
+}
+
+// haSiteEntities are the site's entities by published key
+var haSiteEntities = map[string]haEntity{
+	"gridPower":                     haSensor("Grid power", "W", "power", "measurement"),
+	"pvPower":                       haSensor("PV power", "W", "power", "measurement"),
+	"batteryPower":                  haSensor("Battery power", "W", "power", "measurement"),
+	"homePower":                     haSensor("Home power", "W", "power", "measurement"),
+	"batterySoC":                    haSensor("Battery SoC", "%", "battery", "measurement"),
+	"gridEnergy":                    haSensor("Grid energy", "kWh", "energy", "total_increasing"),
+	"savingsTotalCharged":           haSensor("Total charged", "kWh", "energy", "total_increasing"),
+	"savingsSelfConsumptionPercent": haSensor("Self consumption", "%", "", "measurement"),
+	"prioritySoC":                   haNumber("Priority SoC", "%", 0, 100, 1),
+	"bufferSoC":                     haNumber("Buffer SoC", "%", 0, 100, 1),
+	"residualPower":                 haNumber("Residual power", "W", -10000, 10000, 10),
+}
+
+// haLoadpointEntities are the loadpoint's entities by published key
+var haLoadpointEntities = map[string]haEntity{
+	"chargePower":             haSensor("Charge power", "W", "power", "measurement"),
+	"chargeCurrent":           haSensor("Charge current", "A", "current", "measurement"),
+	"chargedEnergy":           haSensor("Charged energy", "Wh", "energy", "total_increasing"),
+	"chargeTotalImport":       haSensor("Charge meter total", "kWh", "energy", "total_increasing"),
+	"chargeDuration":          haSensor("Charge duration", "s", "duration", "measurement"),
+	"chargeRemainingDuration": haSensor("Remaining duration", "s", "duration", "measurement"),
+	"chargeRemainingEnergy":   haSensor("Remaining energy", "Wh", "energy", ""),
+	"connectedDuration":       haSensor("Connected duration", "s", "duration", "measurement"),
+	"phasesActive":            haSensor("Active phases", "", "", "measurement"),
+	"vehicleSoC":              haSensor("Vehicle SoC", "%", "battery", "measurement"),
+	"vehicleRange":            haSensor("Vehicle range", "km", "distance", "measurement"),
+	"vehicleOdometer":         haSensor("Vehicle odometer", "km", "distance", "total_increasing"),
+	"vehicleTitle":            haSensor("Vehicle", "", "", ""),
+	"connected":               haBinarySensor("Connected", "plug"),
+	"charging":                haBinarySensor("Charging", "battery_charging"),
+	"enabled":                 haBinarySensor("Enabled", ""),
+	"vehiclePresent":          haBinarySensor("Vehicle present", "presence"),
+	"mode":                    haSelect("Mode", "off", "now", "minpv", "pv", "v2h"),
+	"targetSoC":               haNumber("Target SoC", "%", 0, 100, 1),
+	"minSoC":                  haNumber("Min SoC", "%", 0, 100, 1),
+	"minCurrent":              haNumber("Min current", "A", 1, 32, 1),
+	"maxCurrent":              haNumber("Max current", "A", 1, 63, 1),
+}
+
+// haPhases is the phases select using the configured phases as state
+var haPhases = haSelect("Phases", "0", "1", "3")
+
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SwVersion    string   `json:"sw_version,omitempty"`
+	ViaDevice    string   `json:"via_device,omitempty"`
+}
+
+// haConfig is the Home Assistant discovery message
+type haConfig struct {
+	Name              string   `json:"name"`
+	HasEntityName     bool     `json:"has_entity_name"`
+	UniqueID          string   `json:"unique_id"`
+	ObjectID          string   `json:"object_id"`
+	StateTopic        string   `json:"state_topic"`
+	CommandTopic      string   `json:"command_topic,omitempty"`
+	CommandTemplate   string   `json:"command_template,omitempty"`
+	AvailabilityTopic string   `json:"availability_topic"`
+	Unit              string   `json:"unit_of_measurement,omitempty"`
+	DeviceClass       string   `json:"device_class,omitempty"`
+	StateClass        string   `json:"state_class,omitempty"`
+	PayloadOn         string   `json:"payload_on,omitempty"`
+	PayloadOff        string   `json:"payload_off,omitempty"`
+	Min               *float64 `json:"min,omitempty"`
+	Max               *float64 `json:"max,omitempty"`
+	Step              *float64 `json:"step,omitempty"`
+	Options           []string `json:"options,omitempty"`
+	Device            haDevice `json:"device"`
+}
+
+// HomeAssistant publishes Home Assistant MQTT discovery messages for the topics of the MQTT api
+type HomeAssistant struct {
+	log      *util.Logger
+	publish  func(topic string, payload []byte)
+	prefix   string // discovery prefix
+	root     string // MQTT api root topic
+	node     string
+	titles   map[int]string         // loadpoint titles
+	vehicles []string               // vehicle titles
+	entities map[string]haPublished // published discovery messages by topic
+	stop     func()                 // stops collecting discovery topics
+
+	mu       sync.Mutex
+	retained map[string]bool // discovery topics found at the broker
+}
+
+// haPublished is a published discovery message
+type haPublished struct {
+	lp        *int
+	key       string
+	component string
+	conf      haConfig
+	payload   []byte
+}
+
+// NewHomeAssistant creates Home Assistant discovery publisher using the given discovery prefix
+func NewHomeAssistant(prefix, root string) *HomeAssistant {
+	h := newHomeAssistant(prefix, root)
+
+	h.publish = func(topic string, payload []byte) {
+		token := mqtt.Instance.Client.Publish(topic, mqtt.Instance.Qos, true, payload)
+		go mqtt.Instance.WaitForToken(token)
+	}
+
+	// collect previously published entities
+	token := mqtt.Instance.Client.Subscribe(h.topic("+", "+"), mqtt.Instance.Qos, func(c paho.Client, msg paho.Message) {
+		if len(msg.Payload()) > 0 {
+			h.mu.Lock()
+			h.retained[msg.Topic()] = true
+			h.mu.Unlock()
+		}
+	})
+	mqtt.Instance.WaitForToken(token)
+
+	h.stop = func() {
+		mqtt.Instance.WaitForToken(mqtt.Instance.Client.Unsubscribe(h.topic("+", "+")))
+	}
+
+	return h
+}
+
+func newHomeAssistant(prefix, root string) *HomeAssistant {
+	return &HomeAssistant{
+		log:      util.NewLogger("homeassistant"),
+		prefix:   strings.Trim(prefix, "/"),
+		root:     root,
+		node:     strings.ReplaceAll(root, "/", "_"),
+		titles:   make(map[int]string),
+		entities: make(map[string]haPublished),
+		retained: make(map[string]bool),
+		stop:     func() {},
+	}
+}
+
+// topic returns the discovery topic
+func (h *HomeAssistant) topic(component, object string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/config", h.prefix, component, h.node, object)
+}
+
+// device returns the site or loadpoint device
+func (h *HomeAssistant) device(lp *int) haDevice {
+	res := haDevice{
+		Identifiers:  []string{h.node},
+		Name:         "evcc",
+		Manufacturer: "evcc.io",
+		Model:        "Site",
+		SwVersion:    Version,
+	}
+
+	if lp != nil {
+		title, ok := h.titles[*lp]
+		if !ok || title == "" {
+			title = fmt.Sprintf("Loadpoint %d", *lp+1)
+		}
+
+		res.Identifiers = []string{fmt.Sprintf("%s_loadpoint_%d", h.node, *lp+1)}
+		res.Name = title
+		res.Model = "Loadpoint"
+		res.ViaDevice = h.node
+	}
+
+	return res
+}
+
+// object returns the object id and api topic of the published key
+func (h *HomeAssistant) object(lp *int, key string) (string, string) {
+	if lp == nil {
+		return "site_" + key, fmt.Sprintf("%s/site/%s", h.root, key)
+	}
+
+	return fmt.Sprintf("loadpoint_%d_%s", *lp+1, key), fmt.Sprintf("%s/loadpoints/%d/%s", h.root, *lp+1, key)
+}
+
+// config creates the discovery message of the entity for the given key and state key
+func (h *HomeAssistant) config(lp *int, key, state string, e haEntity) haConfig {
+	object, topic := h.object(lp, key)
+	_, stateTopic := h.object(lp, state)
+
+	res := haConfig{
+		Name:              e.Name,
+		HasEntityName:     true,
+		UniqueID:          h.node + "_" + object,
+		ObjectID:          h.node + "_" + object,
+		StateTopic:        stateTopic,
+		AvailabilityTopic: h.root + "/status",
+		Unit:              e.Unit,
+		DeviceClass:       e.DeviceClass,
+		StateClass:        e.StateClass,
+		Options:           e.Options,
+		Device:            h.device(lp),
+	}
+
+	if e.Writable {
+		res.CommandTopic = topic + "/set"
+	}
+
+	switch e.Component {
+	case "binary_sensor":
+		res.PayloadOn, res.PayloadOff = "true", "false"
+	case "number":
+		res.Min, res.Max, res.Step = &e.Min, &e.Max, &e.Step
+	}
+
+	return res
+}
+
+// update publishes the discovery message if changed
+func (h *HomeAssistant) update(lp *int, key, component string, conf haConfig) {
+	object, _ := h.object(lp, key)
+	topic := h.topic(component, object)
+
+	b, err := json.Marshal(conf)
+	if err != nil {
+		h.log.ERROR.Println(err)
+		return
+	}
+
+	if prev, ok := h.entities[topic]; ok && string(prev.payload) == string(b) {
+		return
+	}
+
+	h.entities[topic] = haPublished{lp: lp, key: key, component: component, conf: conf, payload: b}
+	h.publish(topic, b)
+}
+
+// remove removes the entity
+func (h *HomeAssistant) remove(lp *int, key, component string) {
+	object, _ := h.object(lp, key)
+	topic := h.topic(component, object)
+
+	if _, ok := h.entities[topic]; ok {
+		delete(h.entities, topic)
+		h.publish(topic, nil)
+	}
+}
+
+// vehicleSelect publishes the loadpoint's vehicle select or removes it if no vehicles are configured
+func (h *HomeAssistant) vehicleSelect(lp int) {
+	if len(h.vehicles) == 0 {
+		h.remove(&lp, "vehicle", "select")
+		return
+	}
+
+	ids := make(map[string]int, len(h.vehicles))
+	for i, title := range h.vehicles {
+		ids[title] = i
+	}
+
+	b, _ := json.Marshal(ids)
+
+	conf := h.config(&lp, "vehicle", "vehicleTitle", haSelect("Vehicle", h.vehicles...))
+	conf.CommandTemplate = fmt.Sprintf("{%% set ids = %s %%}{{ ids[value] }}", b)
+
+	h.update(&lp, "vehicle", "select", conf)
+}
+
+// cleanup removes previously published entities not published anymore
+func (h *HomeAssistant) cleanup() {
+	h.stop()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic := range h.retained {
+		if _, ok := h.entities[topic]; !ok {
+			h.log.DEBUG.Printf("remove %s", topic)
+			h.publish(topic, nil)
+		}
+	}
+
+	h.retained = make(map[string]bool)
+}
+
+// handle publishes the entity for the published value
+func (h *HomeAssistant) handle(loadpoints int, p util.Param) {
+	if p.Val == nil {
+		return
+	}
+
+	if p.LoadPoint == nil {
+		if p.Key == "vehicles" {
+			if vehicles, ok := p.Val.([]string); ok {
+				h.vehicles = vehicles
+				for lp := 0; lp < loadpoints; lp++ {
+					h.vehicleSelect(lp)
+				}
+			}
+			return
+		}
+
+		if e, ok := haSiteEntities[p.Key]; ok {
+			h.update(nil, p.Key, e.Component, h.config(nil, p.Key, p.Key, e))
+		}
+
+		return
+	}
+
+	lp := *p.LoadPoint
+
+	switch p.Key {
+	case "title":
+		if title, ok := p.Val.(string); ok && title != h.titles[lp] {
+			h.titles[lp] = title
+			h.refresh(lp)
+		}
+
+	case "phasesConfigured":
+		h.update(&lp, "phases", "select", h.config(&lp, "phases", p.Key, haPhases))
+
+	default:
+		if e, ok := haLoadpointEntities[p.Key]; ok {
+			h.update(&lp, p.Key, e.Component, h.config(&lp, p.Key, p.Key, e))
+		}
+	}
+}
+
+// refresh republishes the loadpoint's entities with updated device
+func (h *HomeAssistant) refresh(lp int) {
+	topics := make([]string, 0, len(h.entities))
+	for topic, e := range h.entities {
+		if e.lp != nil && *e.lp == lp {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		e := h.entities[topic]
+		e.conf.Device = h.device(&lp)
+		h.update(&lp, e.key, e.component, e.conf)
+	}
+}
+
+// Run publishes the discovery messages for the published values
+func (h *HomeAssistant) Run(site site.API, in <-chan util.Param) {
+	loadpoints := len(site.LoadPoints())
+	cleanup := time.After(haCleanupDelay)
+
+	for {
+		select {
+		case p, ok := <-in:
+			if !ok {
+				return
+			}
+			h.handle(loadpoints, p)
+
+		case <-cleanup:
+			h.cleanup()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHomeAssistant(t *testing.T) {
+	published := make(map[string][]byte)
+
+	h := newHomeAssistant("homeassistant/", "evcc")
+	h.publish = func(topic string, payload []byte) {
+		published[topic] = payload
+	}
+
+	lp := 0
+	params := []util.Param{
+		{Key: "gridPower", Val: 1000.0},
+		{Key: "siteTitle", Val: "Home"},
+		{LoadPoint: &lp, Key: "chargePower", Val: 0.0},
+		{LoadPoint: &lp, Key: "mode", Val: "pv"},
+		{LoadPoint: &lp, Key: "phasesConfigured", Val: nil},
+		{Key: "vehicles", Val: []string{"blue", "white"}},
+	}
+
+	for _, p := range params {
+		h.handle(1, p)
+	}
+
+	assert.Len(t, published, 4)
+
+	var conf haConfig
+	require.NoError(t, json.Unmarshal(published["homeassistant/sensor/evcc/site_gridPower/config"], &conf))
+	assert.Equal(t, "evcc/site/gridPower", conf.StateTopic)
+	assert.Equal(t, "W", conf.Unit)
+	assert.Equal(t, "power", conf.DeviceClass)
+	assert.Equal(t, "measurement", conf.StateClass)
+	assert.Equal(t, "evcc/status", conf.AvailabilityTopic)
+
+	require.NoError(t, json.Unmarshal(published["homeassistant/select/evcc/loadpoint_1_mode/config"], &conf))
+	assert.Equal(t, "evcc/loadpoints/1/mode/set", conf.CommandTopic)
+	assert.Equal(t, []string{"off", "now", "minpv", "pv", "v2h"}, conf.Options)
+	assert.Equal(t, "Loadpoint 1", conf.Device.Name)
+	assert.Equal(t, "evcc", conf.Device.ViaDevice)
+
+	require.NoError(t, json.Unmarshal(published["homeassistant/select/evcc/loadpoint_1_vehicle/config"], &conf))
+	assert.Equal(t, "evcc/loadpoints/1/vehicleTitle", conf.StateTopic)
+	assert.Equal(t, []string{"blue", "white"}, conf.Options)
+	assert.Equal(t, `{% set ids = {"blue":0,"white":1} %}{{ ids[value] }}`, conf.CommandTemplate)
+
+	// unchanged values are not republished
+	published = make(map[string][]byte)
+	h.handle(1, util.Param{Key: "gridPower", Val: 2000.0})
+	assert.Empty(t, published)
+
+	// loadpoint title updates device
+	h.handle(1, util.Param{LoadPoint: &lp, Key: "title", Val: "Garage"})
+	assert.Len(t, published, 3)
+
+	require.NoError(t, json.Unmarshal(published["homeassistant/sensor/evcc/loadpoint_1_chargePower/config"], &conf))
+	assert.Equal(t, "Garage", conf.Device.Name)
+
+	// removed vehicles
+	published = make(map[string][]byte)
+	h.handle(1, util.Param{Key: "vehicles", Val: []string{}})
+	assert.Equal(t, map[string][]byte{"homeassistant/select/evcc/loadpoint_1_vehicle/config": nil}, published)
+
+	// stale entities of removed loadpoints
+	published = make(map[string][]byte)
+	h.retained = map[string]bool{
+		"homeassistant/sensor/evcc/site_gridPower/config":          true,
+		"homeassistant/sensor/evcc/loadpoint_2_chargePower/config": true,
+	}
+	h.cleanup()
+	assert.Equal(t, map[string][]byte{"homeassistant/sensor/evcc/loadpoint_2_chargePower/config": nil}, published)
+}
@@ -99,6 +99,10 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		"prioritysoc":   {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoC, site.GetPrioritySoC)},
 		"residualpower": {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
 		"sessions":      {[]string{"GET"}, "/sessions", sessionHandler},
+		"sessions2":     {[]string{"GET"}, "/sessions/totals", sessionTotalsHandler},
+		"session":       {[]string{"PUT", "OPTIONS"}, "/sessions/{id:[0-9]+}", updateSessionHandler},
+		"session2":      {[]string{"DELETE", "OPTIONS"}, "/sessions/{id:[0-9]+}", deleteSessionHandler},
+		"tariff":        {[]string{"GET"}, "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
 		"telemetry":     {[]string{"GET"}, "/settings/telemetry", boolGetHandler(telemetry.Enabled)},
 		"telemetry2":    {[]string{"POST", "OPTIONS"}, "/settings/telemetry/{value:[a-z]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
 	}
@@ -112,7 +116,7 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		loadpoint := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id)).Subrouter()
 
 		routes := map[string]route{
-			"mode":          {[]string{"POST", "OPTIONS"}, "/mode/{value:[a-z]+}", chargeModeHandler(lp)},
+			"mode":          {[]string{"POST", "OPTIONS"}, "/mode/{value:[a-z0-9]+}", chargeModeHandler(lp)},
 			"targetenergy":  {[]string{"POST", "OPTIONS"}, "/targetenergy/{value:[0-9]+}", intHandler(pass(lp.SetTargetEnergy), lp.GetTargetEnergy)},
 			"targetsoc":     {[]string{"POST", "OPTIONS"}, "/targetsoc/{value:[0-9]+}", intHandler(pass(lp.SetTargetSoC), lp.GetTargetSoC)},
 			"minsoc":        {[]string{"POST", "OPTIONS"}, "/minsoc/{value:[0-9]+}", intHandler(pass(lp.SetMinSoC), lp.GetMinSoC)},
@@ -132,6 +136,12 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		}
 	}
 
+	// consumer api
+	for id, c := range site.Consumers() {
+		consumer := api.PathPrefix(fmt.Sprintf("/consumers/%d", id)).Subrouter()
+		consumer.Methods("POST", "OPTIONS").Path("/mode/{value:[a-z]+}").Handler(consumerModeHandler(c))
+	}
+
 }
 
 // RegisterShutdownHandler connects the http handlers to the site
@@ -179,16 +179,92 @@ func stateHandler(cache *util.Cache) http.HandlerFunc {
 	}
 }
 
-// sessionHandler returns the list of charging sessions
-func sessionHandler(w http.ResponseWriter, r *http.Request) {
+// parseSessionDate parses a date or RFC3339 timestamp. Dates are extended to the end of the day if requested.
+func parseSessionDate(s string, endOfDay bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
+
+// minSessionEnergy is the default minimum charged energy in kWh of listed sessions
+const minSessionEnergy = 0.05
+
+// sessionFilter creates the sessions filter from the request's query parameters
+func sessionFilter(r *http.Request) (db.Filter, error) {
+	q := r.URL.Query()
+
+	res := db.Filter{
+		Loadpoint:  q.Get("loadpoint"),
+		Vehicle:    q.Get("vehicle"),
+		Identifier: q.Get("identifier"),
+		MinEnergy:  minSessionEnergy,
+	}
+
+	var err error
+	if s := q.Get("from"); s != "" {
+		if res.From, err = parseSessionDate(s, false); err != nil {
+			return res, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+
+	if s := q.Get("to"); s != "" {
+		if res.To, err = parseSessionDate(s, true); err != nil {
+			return res, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	if s := q.Get("min_kwh"); s != "" {
+		if res.MinEnergy, err = strconv.ParseFloat(s, 64); err != nil {
+			return res, fmt.Errorf("invalid min_kwh: %w", err)
+		}
+	}
+
+	if s := q.Get("page"); s != "" {
+		if res.Page, err = strconv.Atoi(s); err != nil {
+			return res, fmt.Errorf("invalid page: %w", err)
+		}
+	}
+
+	if s := q.Get("pagesize"); s != "" {
+		if res.PageSize, err = strconv.Atoi(s); err != nil {
+			return res, fmt.Errorf("invalid pagesize: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// findSessions returns the charging sessions matching the request's filter
+func findSessions(w http.ResponseWriter, r *http.Request) (db.Sessions, bool) {
 	if dbserver.Instance == nil {
 		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
-		return
+		return nil, false
+	}
+
+	filter, err := sessionFilter(r)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return nil, false
 	}
 
 	var res db.Sessions
-	if txn := dbserver.Instance.Where("charged_kwh>=0.05").Order("created desc").Find(&res); txn.Error != nil {
+	if txn := filter.Apply(dbserver.Instance).Order("created desc").Find(&res); txn.Error != nil {
 		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return nil, false
+	}
+
+	return res, true
+}
+
+// sessionHandler returns the list of charging sessions
+func sessionHandler(w http.ResponseWriter, r *http.Request) {
+	res, ok := findSessions(w, r)
+	if !ok {
 		return
 	}
 
@@ -202,6 +278,132 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	jsonResult(w, res)
 }
 
+// sessionTotalsHandler returns the monthly totals of charging sessions by loadpoint and vehicle
+func sessionTotalsHandler(w http.ResponseWriter, r *http.Request) {
+	res, ok := findSessions(w, r)
+	if !ok {
+		return
+	}
+
+	jsonResult(w, res.Totals())
+}
+
+// findSession returns the charging session referenced by the request's id
+func findSession(w http.ResponseWriter, r *http.Request) (db.Session, bool) {
+	var res db.Session
+
+	if dbserver.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return res, false
+	}
+
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return res, false
+	}
+
+	if txn := dbserver.Instance.Where("id = ?", id).Limit(1).Find(&res); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return res, false
+	} else if txn.RowsAffected == 0 {
+		jsonError(w, http.StatusNotFound, errors.New("session not found"))
+		return res, false
+	}
+
+	return res, true
+}
+
+// errSessionActive is returned when modifying a session that has not finished yet
+var errSessionActive = errors.New("session is active")
+
+// updateSessionHandler corrects a charging session's vehicle assignment
+func updateSessionHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := findSession(w, r)
+	if !ok {
+		return
+	}
+
+	// open sessions are persisted by the loadpoint and would overwrite the update
+	if session.Finished.IsZero() {
+		jsonError(w, http.StatusConflict, errSessionActive)
+		return
+	}
+
+	var req struct {
+		Vehicle *string `json:"vehicle"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Vehicle != nil {
+		session.Vehicle = *req.Vehicle
+	}
+
+	if txn := dbserver.Instance.Save(&session); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	jsonResult(w, session)
+}
+
+// deleteSessionHandler removes a charging session
+func deleteSessionHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := findSession(w, r)
+	if !ok {
+		return
+	}
+
+	if session.Finished.IsZero() {
+		jsonError(w, http.StatusConflict, errSessionActive)
+		return
+	}
+
+	if txn := dbserver.Instance.Delete(&session); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	jsonResult(w, struct{}{})
+}
+
+// tariffHandler returns the price forecast of the selected tariff
+func tariffHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		t := site.GetTariff(vars["tariff"])
+		if t == nil {
+			jsonError(w, http.StatusNotFound, errors.New("tariff not available"))
+			return
+		}
+
+		tr, ok := t.(api.TariffRates)
+		if !ok {
+			jsonError(w, http.StatusNotFound, errors.New("tariff does not provide rates"))
+			return
+		}
+
+		rates, err := tr.Rates()
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := struct {
+			Rates api.Rates `json:"rates"`
+		}{
+			Rates: rates,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
 // chargeModeHandler updates charge mode
 func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -215,7 +417,32 @@ func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 
 		lp.SetMode(mode)
 
-		jsonResult(w, lp.GetMode())
+		// mode may be rejected by the loadpoint
+		if res := lp.GetMode(); res != mode {
+			jsonError(w, http.StatusBadRequest, fmt.Errorf("charge mode not supported: %s", mode))
+			return
+		}
+
+		jsonResult(w, mode)
+	}
+}
+
+// consumerModeHandler updates consumer mode
+func consumerModeHandler(c site.ConsumerAPI) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		mode, err := api.ChargeModeString(vars["value"])
+		if err == nil {
+			err = c.SetMode(mode)
+		}
+
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, c.GetMode())
 	}
 }
 
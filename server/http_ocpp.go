@@ -0,0 +1,179 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+)
+
+// RegisterOCPPHandlers connects the http handlers for maintaining OCPP chargers by charger name
+func (s *HTTPd) RegisterOCPPHandlers(chargers map[string]ocpp.ChargePointProvider) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/ocpp/{name}").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	h := ocppHandler(chargers)
+
+	routes := map[string]route{
+		"status":         {[]string{"GET"}, "/status", h(ocppStatusHandler)},
+		"configuration":  {[]string{"GET"}, "/configuration", h(ocppGetConfigurationHandler)},
+		"configuration2": {[]string{"POST", "OPTIONS"}, "/configuration/{key}/{value}", h(ocppChangeConfigurationHandler)},
+		"reset":          {[]string{"POST", "OPTIONS"}, "/reset/{type:soft|hard}", h(ocppResetHandler)},
+		"unlock":         {[]string{"POST", "OPTIONS"}, "/unlock", h(ocppUnlockHandler)},
+		"unlock2":        {[]string{"POST", "OPTIONS"}, "/unlock/{connector:[0-9]+}", h(ocppUnlockHandler)},
+		"trigger":        {[]string{"POST", "OPTIONS"}, "/trigger/{message:[a-zA-Z]+}", h(ocppTriggerHandler)},
+		"diagnostics":    {[]string{"POST", "OPTIONS"}, "/diagnostics", h(ocppDiagnosticsHandler)},
+		"firmware":       {[]string{"POST", "OPTIONS"}, "/firmware", h(ocppFirmwareHandler)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// ocppHandler resolves the charger by name
+func ocppHandler(chargers map[string]ocpp.ChargePointProvider) func(func(http.ResponseWriter, *http.Request, ocpp.ChargePointProvider)) http.HandlerFunc {
+	return func(handler func(http.ResponseWriter, *http.Request, ocpp.ChargePointProvider)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			c, ok := chargers[mux.Vars(r)["name"]]
+			if !ok {
+				jsonError(w, http.StatusNotFound, errors.New("charger not found"))
+				return
+			}
+
+			handler(w, r, c)
+		}
+	}
+}
+
+// ocppStatusHandler returns the charge point's firmware and diagnostics status
+func ocppStatusHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	jsonResult(w, c.ChargePoint().MaintenanceStatus())
+}
+
+// ocppGetConfigurationHandler returns the charge point's configuration, optionally filtered by key query parameters
+func ocppGetConfigurationHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	res, err := c.ChargePoint().GetConfiguration(r.URL.Query()["key"])
+	if err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// ocppChangeConfigurationHandler changes a configuration key
+func ocppChangeConfigurationHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	vars := mux.Vars(r)
+
+	res, err := c.ChargePoint().ChangeConfiguration(vars["key"], vars["value"])
+	if err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// ocppResetHandler resets the charge point
+func ocppResetHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	resetType := core.ResetTypeSoft
+	if mux.Vars(r)["type"] == "hard" {
+		resetType = core.ResetTypeHard
+	}
+
+	if err := c.ChargePoint().Reset(resetType); err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, true)
+}
+
+// ocppUnlockHandler unlocks the charger's or the given connector
+func ocppUnlockHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	connector := c.Connector()
+	if s, ok := mux.Vars(r)["connector"]; ok {
+		var err error
+		if connector, err = strconv.Atoi(s); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := c.ChargePoint().UnlockConnector(connector); err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, true)
+}
+
+// ocppTriggerHandler triggers a charge point message for the charger's connector
+func ocppTriggerHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	message := remotetrigger.MessageTrigger(mux.Vars(r)["message"])
+
+	if err := c.ChargePoint().TriggerMessage(message, c.Connector()); err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, true)
+}
+
+// ocppDiagnosticsHandler requests a diagnostics upload to the location query parameter
+func ocppDiagnosticsHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	location := r.URL.Query().Get("location")
+	if location == "" {
+		jsonError(w, http.StatusBadRequest, errors.New("missing location"))
+		return
+	}
+
+	res, err := c.ChargePoint().GetDiagnostics(location)
+	if err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// ocppFirmwareHandler requests a firmware update from the location query parameter.
+// The optional retrieve query parameter delays the download.
+func ocppFirmwareHandler(w http.ResponseWriter, r *http.Request, c ocpp.ChargePointProvider) {
+	location := r.URL.Query().Get("location")
+	if location == "" {
+		jsonError(w, http.StatusBadRequest, errors.New("missing location"))
+		return
+	}
+
+	retrieve := time.Now()
+	if s := r.URL.Query().Get("retrieve"); s != "" {
+		var err error
+		if retrieve, err = time.Parse(time.RFC3339, strings.TrimSpace(s)); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := c.ChargePoint().UpdateFirmware(location, retrieve); err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	jsonResult(w, true)
+}
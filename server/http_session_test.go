@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/db"
+	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/glebarez/sqlite"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+func TestSessionHandlers(t *testing.T) {
+	var err error
+	if dbserver.Instance, err = gorm.Open(sqlite.Open(":memory:"), new(gorm.Config)); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { dbserver.Instance = nil }()
+
+	if err := dbserver.Instance.AutoMigrate(new(db.Session)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, session := range []db.Session{
+		{Vehicle: "zoe", ChargedEnergy: 10, Finished: time.Now()},
+		{Vehicle: "zoe", ChargedEnergy: 5}, // active
+	} {
+		if err := dbserver.Instance.Create(&session).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Methods("PUT").Path("/sessions/{id:[0-9]+}").HandlerFunc(updateSessionHandler)
+	router.Methods("DELETE").Path("/sessions/{id:[0-9]+}").HandlerFunc(deleteSessionHandler)
+
+	tc := []struct {
+		method, uri, body string
+		statusCode        int
+	}{
+		{"PUT", "/sessions/1", `{"vehicle":"model3"}`, http.StatusOK},
+		{"PUT", "/sessions/1", `foo`, http.StatusBadRequest},
+		{"PUT", "/sessions/2", `{"vehicle":"model3"}`, http.StatusConflict},
+		{"PUT", "/sessions/3", `{"vehicle":"model3"}`, http.StatusNotFound},
+		{"DELETE", "/sessions/2", "", http.StatusConflict},
+		{"DELETE", "/sessions/1", "", http.StatusOK},
+		{"DELETE", "/sessions/1", "", http.StatusNotFound},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(tc.method, tc.uri, strings.NewReader(tc.body))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != tc.statusCode {
+			t.Errorf("%s %s: handler returned wrong status code: got %v want %v", tc.method, tc.uri, status, tc.statusCode)
+		}
+
+		if tc.method == "PUT" && tc.statusCode == http.StatusOK {
+			var res db.Session
+			dbserver.Instance.First(&res, 1)
+
+			if res.Vehicle != "model3" {
+				t.Errorf("vehicle not updated: %s", res.Vehicle)
+			}
+		}
+	}
+}
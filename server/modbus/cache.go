@@ -0,0 +1,256 @@
+package modbus
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// register read function codes
+const (
+	readHolding uint8 = 3
+	readInput   uint8 = 4
+)
+
+// maxQuantity is the maximum number of registers of a merged read
+const maxQuantity = 125
+
+var errShortResponse = errors.New("short response")
+
+// read results for statistics
+const (
+	resultCache     = "cache"
+	resultCoalesced = "coalesced"
+	resultUpstream  = "upstream"
+	resultError     = "error"
+)
+
+type registerKey struct {
+	unit uint8
+	fn   uint8
+	addr uint16
+}
+
+type register struct {
+	val     uint16
+	updated time.Time
+}
+
+// readRequest is a register read waiting for the upstream connection
+type readRequest struct {
+	unit, fn  uint8
+	addr, qty int
+	res       []uint16
+	err       error
+	done      chan struct{}
+}
+
+// cache serializes register reads of an upstream connection. Reads waiting for the connection
+// are merged with overlapping reads and results are kept for the cache duration.
+type cache struct {
+	clock   clock.Clock
+	ttl     time.Duration
+	bus     chan struct{} // upstream connection token
+	mu      sync.Mutex    // guards registers and pending reads
+	regs    map[registerKey]register
+	pending []*readRequest
+}
+
+func newCache(clock clock.Clock, ttl time.Duration) *cache {
+	return &cache{
+		clock: clock,
+		ttl:   ttl,
+		bus:   make(chan struct{}, 1),
+		regs:  make(map[registerKey]register),
+	}
+}
+
+// get returns the cached registers if all are valid
+func (c *cache) get(unit, fn uint8, addr, qty int) ([]uint16, bool) {
+	if c.ttl == 0 {
+		return nil, false
+	}
+
+	res := make([]uint16, qty)
+	for i := range res {
+		r, ok := c.regs[registerKey{unit, fn, uint16(addr + i)}]
+		if !ok || c.clock.Since(r.updated) >= c.ttl {
+			return nil, false
+		}
+		res[i] = r.val
+	}
+
+	return res, true
+}
+
+// put caches the registers
+func (c *cache) put(unit, fn uint8, addr int, vals []uint16) {
+	if c.ttl == 0 {
+		return
+	}
+
+	now := c.clock.Now()
+	for i, val := range vals {
+		c.regs[registerKey{unit, fn, uint16(addr + i)}] = register{val, now}
+	}
+}
+
+// invalidate removes written holding registers from the cache
+func (c *cache) invalidate(unit uint8, addr, qty uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i < int(qty); i++ {
+		delete(c.regs, registerKey{unit, readHolding, addr + uint16(i)})
+	}
+}
+
+// remove removes the request from the pending reads
+func (c *cache) remove(req *readRequest) {
+	for i, p := range c.pending {
+		if p == req {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// merge collects the pending reads overlapping the request's range
+func (c *cache) merge(req *readRequest) (int, int, []*readRequest) {
+	start, end := req.addr, req.addr+req.qty
+
+	var merged []*readRequest
+	for changed := true; changed; {
+		changed = false
+
+		for _, p := range c.pending {
+			if p.unit != req.unit || p.fn != req.fn || p.addr > end || p.addr+p.qty < start {
+				continue
+			}
+
+			if containsRequest(merged, p) {
+				continue
+			}
+
+			s, e := min(start, p.addr), max(end, p.addr+p.qty)
+			if e-s > maxQuantity {
+				continue
+			}
+
+			start, end = s, e
+			merged = append(merged, p)
+			changed = true
+		}
+	}
+
+	return start, end - start, merged
+}
+
+func containsRequest(reqs []*readRequest, req *readRequest) bool {
+	for _, r := range reqs {
+		if r == req {
+			return true
+		}
+	}
+	return false
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// read returns the registers from cache, from an overlapping read or from upstream
+func (c *cache) read(unit, fn uint8, addr, qty uint16, upstream func(addr, qty uint16) ([]uint16, error)) ([]uint16, string, error) {
+	req := &readRequest{
+		unit: unit,
+		fn:   fn,
+		addr: int(addr),
+		qty:  int(qty),
+		done: make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	if res, ok := c.get(unit, fn, req.addr, req.qty); ok {
+		c.mu.Unlock()
+		return res, resultCache, nil
+	}
+	c.pending = append(c.pending, req)
+	c.mu.Unlock()
+
+	// wait for the upstream connection unless another read answers the request
+	select {
+	case <-req.done:
+		return req.res, resultCoalesced, req.err
+	case c.bus <- struct{}{}:
+	}
+	defer func() { <-c.bus }()
+
+	c.mu.Lock()
+	c.remove(req)
+
+	select {
+	case <-req.done:
+		c.mu.Unlock()
+		return req.res, resultCoalesced, req.err
+	default:
+	}
+
+	if res, ok := c.get(unit, fn, req.addr, req.qty); ok {
+		c.mu.Unlock()
+		return res, resultCache, nil
+	}
+
+	start, count, merged := c.merge(req)
+	for _, p := range merged {
+		c.remove(p)
+	}
+	c.mu.Unlock()
+
+	res, err := upstream(uint16(start), uint16(count))
+
+	// merged range may be rejected by the device, read requested range only
+	if err != nil && len(merged) > 0 {
+		c.mu.Lock()
+		c.pending = append(c.pending, merged...)
+		c.mu.Unlock()
+
+		start, merged = req.addr, nil
+		res, err = upstream(addr, qty)
+	}
+
+	if err == nil && len(res) < req.addr-start+req.qty {
+		err = errShortResponse
+	}
+
+	c.mu.Lock()
+	if err == nil {
+		c.put(unit, fn, start, res)
+	}
+
+	for _, p := range merged {
+		if err == nil {
+			p.res = append([]uint16(nil), res[p.addr-start:p.addr-start+p.qty]...)
+		}
+		p.err = err
+		close(p.done)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, resultError, err
+	}
+
+	return res[req.addr-start : req.addr-start+req.qty], resultUpstream, nil
+}
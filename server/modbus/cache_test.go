@@ -0,0 +1,135 @@
+package modbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubUpstream returns the register addresses as values
+type stubUpstream struct {
+	mu    sync.Mutex
+	reads [][2]uint16
+	wait  chan struct{}
+}
+
+func (s *stubUpstream) read(addr, qty uint16) ([]uint16, error) {
+	s.mu.Lock()
+	s.reads = append(s.reads, [2]uint16{addr, qty})
+	s.mu.Unlock()
+
+	if s.wait != nil {
+		<-s.wait
+	}
+
+	res := make([]uint16, qty)
+	for i := range res {
+		res[i] = addr + uint16(i)
+	}
+
+	return res, nil
+}
+
+func TestCacheTTL(t *testing.T) {
+	clck := clock.NewMock()
+	c := newCache(clck, time.Second)
+	up := new(stubUpstream)
+
+	res, result, err := c.read(1, readInput, 10, 2, up.read)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{10, 11}, res)
+	assert.Equal(t, resultUpstream, result)
+
+	// cached subset
+	res, result, _ = c.read(1, readInput, 11, 1, up.read)
+	assert.Equal(t, []uint16{11}, res)
+	assert.Equal(t, resultCache, result)
+
+	// different unit and function are not cached
+	_, result, _ = c.read(2, readInput, 10, 2, up.read)
+	assert.Equal(t, resultUpstream, result)
+	_, result, _ = c.read(1, readHolding, 10, 2, up.read)
+	assert.Equal(t, resultUpstream, result)
+
+	// partially cached
+	_, result, _ = c.read(1, readInput, 11, 2, up.read)
+	assert.Equal(t, resultUpstream, result)
+
+	// expired
+	clck.Add(time.Second)
+	_, result, _ = c.read(1, readInput, 10, 1, up.read)
+	assert.Equal(t, resultUpstream, result)
+
+	// invalidated
+	_, result, _ = c.read(1, readHolding, 10, 2, up.read)
+	assert.Equal(t, resultUpstream, result)
+	c.invalidate(1, 11, 1)
+	_, result, _ = c.read(1, readHolding, 10, 1, up.read)
+	assert.Equal(t, resultCache, result)
+	_, result, _ = c.read(1, readHolding, 11, 1, up.read)
+	assert.Equal(t, resultUpstream, result)
+
+	assert.Len(t, up.reads, 7)
+}
+
+func TestCacheCoalesce(t *testing.T) {
+	c := newCache(clock.NewMock(), 0)
+	up := &stubUpstream{wait: make(chan struct{})}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]int)
+	)
+
+	read := func(addr, qty uint16) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, result, err := c.read(1, readInput, addr, qty, up.read)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			results[result]++
+			mu.Unlock()
+
+			if assert.Len(t, res, int(qty)) {
+				assert.Equal(t, addr, res[0])
+			}
+		}()
+	}
+
+	pending := func(n int) func() bool {
+		return func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return len(c.pending) == n
+		}
+	}
+
+	// blocks the upstream connection
+	read(0, 10)
+	assert.Eventually(t, func() bool {
+		up.mu.Lock()
+		defer up.mu.Unlock()
+		return len(up.reads) == 1
+	}, time.Second, time.Millisecond)
+
+	// overlapping, adjacent and unrelated reads
+	read(5, 10)
+	read(15, 5)
+	read(100, 1)
+	assert.Eventually(t, pending(3), time.Second, time.Millisecond)
+
+	close(up.wait)
+	wg.Wait()
+
+	// first read, merged read of 5-19 and unrelated read
+	assert.Len(t, up.reads, 3)
+	assert.Contains(t, up.reads, [2]uint16{5, 15})
+	assert.Equal(t, map[string]int{resultUpstream: 3, resultCoalesced: 1}, results)
+}
@@ -3,6 +3,7 @@ package modbus
 import (
 	"encoding/binary"
 	"errors"
+	"net"
 
 	"github.com/andig/mbserver"
 	"github.com/evcc-io/evcc/util"
@@ -12,9 +13,50 @@ import (
 
 type handler struct {
 	log      *util.Logger
+	port     string
 	readOnly bool
 	mbserver.RequestHandler
-	conn *modbus.Connection
+	conn    *upstream           // default upstream device
+	devices map[uint8]*upstream // upstream devices by unit id
+}
+
+// upstream is an upstream device connection
+type upstream struct {
+	conn  *modbus.Connection
+	id    uint8 // device id, zero forwards the request's unit id
+	cache *cache
+}
+
+// upstream returns the upstream device and its device id for the request's unit id
+func (h *handler) upstream(unitID uint8) (*upstream, uint8, error) {
+	u, ok := h.devices[unitID]
+	if !ok {
+		u = h.conn
+	}
+
+	if u == nil {
+		return nil, 0, mbserver.ErrGWPathUnavailable
+	}
+
+	if u.id != 0 {
+		return u, u.id, nil
+	}
+
+	return u, unitID, nil
+}
+
+// count updates the client's request statistics
+func (h *handler) count(clientAddr, result string, err error) {
+	if err != nil {
+		result = resultError
+	}
+
+	client, _, splitErr := net.SplitHostPort(clientAddr)
+	if splitErr != nil {
+		client = clientAddr
+	}
+
+	proxyMetric.WithLabelValues(h.port, client, result).Inc()
 }
 
 func bytesAsUint16(b []byte) []uint16 {
@@ -85,7 +127,14 @@ func (h *handler) exceptionToBoolAndError(op string, b []byte, err error) ([]boo
 	return bytesAsBool(b), err
 }
 
-func (h *handler) HandleCoils(req *mbserver.CoilsRequest) ([]bool, error) {
+func (h *handler) HandleCoils(req *mbserver.CoilsRequest) (res []bool, err error) {
+	u, id, err := h.upstream(req.UnitId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { h.count(req.ClientAddr, resultUpstream, err) }()
+
 	if req.IsWrite {
 		if h.readOnly {
 			return nil, mbserver.ErrIllegalFunction
@@ -93,49 +142,79 @@ func (h *handler) HandleCoils(req *mbserver.CoilsRequest) ([]bool, error) {
 
 		if req.Quantity == 1 {
 			h.log.TRACE.Printf("write coil: id: %d addr: %d val: %t", req.UnitId, req.Addr, req.Args[0])
-			var u uint16
+			var val uint16
 			if req.Args[0] {
-				u = 0xFF00
+				val = 0xFF00
 			}
 
-			b, err := h.conn.WriteSingleCoilWithSlave(req.UnitId, req.Addr, u)
+			b, err := u.conn.WriteSingleCoilWithSlave(id, req.Addr, val)
 			return h.exceptionToBoolAndError("write coil", b, err)
 		}
 
 		h.log.TRACE.Printf("write multiple coils: id: %d addr: %d qty: %d val: %v", req.UnitId, req.Addr, req.Quantity, req.Args)
-		b, err := h.conn.WriteMultipleCoilsWithSlave(req.UnitId, req.Addr, req.Quantity, boolAsBytes(req.Args))
+		b, err := u.conn.WriteMultipleCoilsWithSlave(id, req.Addr, req.Quantity, boolAsBytes(req.Args))
 		return h.exceptionToBoolAndError("write multiple coils", b, err)
 	}
 
 	h.log.TRACE.Printf("read coil: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
-	b, err := h.conn.ReadCoilsWithSlave(req.UnitId, req.Addr, req.Quantity)
+	b, err := u.conn.ReadCoilsWithSlave(id, req.Addr, req.Quantity)
 	return h.exceptionToBoolAndError("read coil", b, err)
 }
 
+// readRegisters reads registers from cache or upstream
+func (h *handler) readRegisters(clientAddr string, unitID, fn uint8, addr, qty uint16) ([]uint16, error) {
+	u, id, err := h.upstream(unitID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, result, err := u.cache.read(unitID, fn, addr, qty, func(addr, qty uint16) ([]uint16, error) {
+		if fn == readInput {
+			b, err := u.conn.ReadInputRegistersWithSlave(id, addr, qty)
+			return h.exceptionToUint16AndError("read input", b, err)
+		}
+
+		b, err := u.conn.ReadHoldingRegistersWithSlave(id, addr, qty)
+		return h.exceptionToUint16AndError("read holding", b, err)
+	})
+
+	h.count(clientAddr, result, err)
+
+	return res, err
+}
+
 func (h *handler) HandleInputRegisters(req *mbserver.InputRegistersRequest) (res []uint16, err error) {
 	h.log.TRACE.Printf("read input: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
-	b, err := h.conn.ReadInputRegistersWithSlave(req.UnitId, req.Addr, req.Quantity)
-	return h.exceptionToUint16AndError("read input", b, err)
+	return h.readRegisters(req.ClientAddr, req.UnitId, readInput, req.Addr, req.Quantity)
 }
 
 func (h *handler) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) (res []uint16, err error) {
-	if req.IsWrite {
-		if h.readOnly {
-			return nil, mbserver.ErrIllegalFunction
-		}
+	if !req.IsWrite {
+		h.log.TRACE.Printf("read holding: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
+		return h.readRegisters(req.ClientAddr, req.UnitId, readHolding, req.Addr, req.Quantity)
+	}
 
-		if req.Quantity == 1 {
-			h.log.TRACE.Printf("write holding: id: %d addr: %d val: %0x", req.UnitId, req.Addr, req.Args[0])
-			b, err := h.conn.WriteSingleRegisterWithSlave(req.UnitId, req.Addr, req.Args[0])
-			return h.exceptionToUint16AndError("write holding", b, err)
-		}
+	if h.readOnly {
+		return nil, mbserver.ErrIllegalFunction
+	}
+
+	u, id, err := h.upstream(req.UnitId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		u.cache.invalidate(req.UnitId, req.Addr, req.Quantity)
+		h.count(req.ClientAddr, resultUpstream, err)
+	}()
 
-		h.log.TRACE.Printf("write multiple holding: id: %d addr: %d qty: %d val: %0x", req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
-		b, err := h.conn.WriteMultipleRegistersWithSlave(req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
-		return h.exceptionToUint16AndError("write multiple holding", b, err)
+	if req.Quantity == 1 {
+		h.log.TRACE.Printf("write holding: id: %d addr: %d val: %0x", req.UnitId, req.Addr, req.Args[0])
+		b, err := u.conn.WriteSingleRegisterWithSlave(id, req.Addr, req.Args[0])
+		return h.exceptionToUint16AndError("write holding", b, err)
 	}
 
-	h.log.TRACE.Printf("read holding: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
-	b, err := h.conn.ReadHoldingRegistersWithSlave(req.UnitId, req.Addr, req.Quantity)
-	return h.exceptionToUint16AndError("read holding", b, err)
+	h.log.TRACE.Printf("write multiple holding: id: %d addr: %d qty: %d val: %0x", req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
+	b, err := u.conn.WriteMultipleRegistersWithSlave(id, req.Addr, req.Quantity, asBytes(req.Args))
+	return h.exceptionToUint16AndError("write multiple holding", b, err)
 }
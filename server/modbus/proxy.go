@@ -3,29 +3,76 @@ package modbus
 import (
 	"fmt"
 	"net"
+	"strconv"
+	"time"
 
 	"github.com/andig/mbserver"
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-func StartProxy(port int, config modbus.Settings, readOnly bool) error {
+var proxyMetric *prometheus.CounterVec
+
+func init() {
+	proxyMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "evcc",
+		Subsystem: "modbusproxy",
+		Name:      "request_total",
+		Help:      "Total count of modbus proxy requests by client and result",
+	}, []string{"port", "client", "result"})
+
+	prometheus.MustRegister(proxyMetric)
+}
+
+func newUpstream(config modbus.Settings, id uint8, ttl time.Duration) (*upstream, error) {
 	conn, err := modbus.NewConnection(config.URI, config.Device, config.Comset, config.Baudrate, modbus.ProtocolFromRTU(config.RTU), config.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if !sponsor.IsAuthorized() {
-		return api.ErrSponsorRequired
-	}
+	return &upstream{
+		conn:  conn,
+		id:    id,
+		cache: newCache(clock.New(), ttl),
+	}, nil
+}
 
+// StartProxy starts a modbus tcp proxy at the given port. Requests are relayed to the device
+// configured for the request's unit id or to the default device. Register reads are served
+// from cache for the ttl duration.
+func StartProxy(port int, config modbus.Settings, devices map[uint8]modbus.Settings, readOnly bool, ttl time.Duration) error {
 	h := &handler{
 		log:            util.NewLogger(fmt.Sprintf("proxy-%d", port)),
+		port:           strconv.Itoa(port),
 		readOnly:       readOnly,
 		RequestHandler: new(mbserver.DummyHandler),
-		conn:           conn,
+		devices:        make(map[uint8]*upstream),
+	}
+
+	if config.URI != "" || config.Device != "" {
+		conn, err := newUpstream(config, 0, ttl)
+		if err != nil {
+			return err
+		}
+
+		h.conn = conn
+	}
+
+	for unitID, device := range devices {
+		conn, err := newUpstream(device, device.ID, ttl)
+		if err != nil {
+			return err
+		}
+
+		h.devices[unitID] = conn
+	}
+
+	if !sponsor.IsAuthorized() {
+		return api.ErrSponsorRequired
 	}
 
 	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -33,7 +80,13 @@ func StartProxy(port int, config modbus.Settings, readOnly bool) error {
 		return err
 	}
 
-	h.log.DEBUG.Printf("modbus proxy for %s listening at :%d", config.String(), port)
+	if h.conn != nil {
+		h.log.DEBUG.Printf("modbus proxy for %s listening at :%d", config.String(), port)
+	}
+
+	for unitID, device := range devices {
+		h.log.DEBUG.Printf("modbus proxy for %s listening at :%d with unit id %d", device.String(), port, unitID)
+	}
 
 	srv, err := mbserver.New(h)
 
@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/andig/mbserver"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,3 +71,65 @@ type echoHandler struct {
 func (h *echoHandler) HandleInputRegisters(req *mbserver.InputRegistersRequest) (res []uint16, err error) {
 	return []uint16{req.Addr ^ uint16(req.UnitId)}, err
 }
+
+func startEcho(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv, _ := mbserver.New(&echoHandler{RequestHandler: new(mbserver.DummyHandler)})
+	assert.NoError(t, srv.Start(l))
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	return l.Addr().String()
+}
+
+func TestProxyDevices(t *testing.T) {
+	conn := func(uri string, id uint8) *upstream {
+		c, err := modbus.NewConnection(uri, "", "", 0, modbus.Tcp, 1)
+		assert.NoError(t, err)
+		return &upstream{conn: c, id: id, cache: newCache(clock.New(), time.Minute)}
+	}
+
+	h := &handler{
+		log:            util.NewLogger("foo"),
+		port:           "devices",
+		RequestHandler: new(mbserver.DummyHandler),
+		conn:           conn(startEcho(t), 0),
+		devices: map[uint8]*upstream{
+			2: conn(startEcho(t), 7),
+		},
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv, _ := mbserver.New(h)
+	assert.NoError(t, srv.Start(l))
+	defer func() { _ = srv.Stop() }()
+
+	client, err := modbus.NewConnection(l.Addr().String(), "", "", 0, modbus.Tcp, 1)
+	assert.NoError(t, err)
+
+	count := func(result string) float64 {
+		return testutil.ToFloat64(proxyMetric.WithLabelValues("devices", "127.0.0.1", result))
+	}
+	upstreams, hits := count(resultUpstream), count(resultCache)
+
+	for _, tc := range []struct {
+		unit, id uint8
+	}{
+		{1, 1}, // default device forwards unit id
+		{3, 3},
+		{2, 7}, // mapped device with device id
+	} {
+		for i := 0; i < 2; i++ {
+			b, err := client.ReadInputRegistersWithSlave(tc.unit, 10, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 10^uint16(tc.id), binary.BigEndian.Uint16(b), tc)
+		}
+	}
+
+	// second reads served from cache
+	assert.Equal(t, 3.0, count(resultUpstream)-upstreams)
+	assert.Equal(t, 3.0, count(resultCache)-hits)
+}
@@ -0,0 +1,342 @@
+package modbus
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Register map of the modbus server. Addresses are stable, new registers are only appended.
+// Any unit id is accepted. 32 bit values use two registers, high word first.
+//
+// Input registers (function code 04)
+//
+//	site
+//	   0      register map version
+//	   1      number of loadpoints
+//	   2-3    grid power (W, int32, positive is import)
+//	   4-5    pv power (W, int32)
+//	   6-7    battery power (W, int32, positive is discharge)
+//	   8      battery soc (%)
+//	loadpoint n (base address 100*n, n starting at 1)
+//	   +0     mode (0 off, 1 now, 2 minpv, 3 pv, 4 v2h)
+//	   +1     status (0 unknown, 1 A, 2 B, 3 C, 4 D, 5 E, 6 F)
+//	   +2-3   charge power (W, int32)
+//	   +4-5   charged energy of session (Wh, uint32)
+//	   +6     vehicle soc (%)
+//	   +7     target soc (%)
+//	   +8     min soc (%)
+//	   +9     max current (A)
+//
+// Holding registers (function codes 03, 06, 16), writable unless read-only
+//
+//	site
+//	   0      priority soc (%)
+//	   1      buffer soc (%)
+//	loadpoint n (base address 100*n, n starting at 1)
+//	   +0     mode (0 off, 1 now, 2 minpv, 3 pv, 4 v2h)
+//	   +1     target soc (%)
+//	   +2     min soc (%)
+//	   +3     max current (A)
+const (
+	// Version is the register map version
+	Version = 1
+
+	loadpointBase = 100
+)
+
+// modes are the charge modes by register value
+var modes = []api.ChargeMode{api.ModeOff, api.ModeNow, api.ModeMinPV, api.ModePV, api.ModeV2H}
+
+// statuses are the charge statuses by register value
+var statuses = []api.ChargeStatus{api.StatusNone, api.StatusA, api.StatusB, api.StatusC, api.StatusD, api.StatusE, api.StatusF}
+
+// Server is the modbus server exposing site and loadpoint state
+type Server struct {
+	mbserver.RequestHandler
+	log      *util.Logger
+	site     site.API
+	readOnly bool
+
+	mu     sync.Mutex
+	values map[string]float64 // published site values
+}
+
+// NewServer creates a modbus server for the given site
+func NewServer(site site.API, readOnly bool) *Server {
+	return &Server{
+		RequestHandler: new(mbserver.DummyHandler),
+		log:            util.NewLogger("modbus"),
+		site:           site,
+		readOnly:       readOnly,
+		values:         make(map[string]float64),
+	}
+}
+
+// StartServer starts a modbus tcp server at the given port
+func StartServer(port int, site site.API, in <-chan util.Param, readOnly bool) error {
+	h := NewServer(site, readOnly)
+	go h.Run(in)
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	h.log.DEBUG.Printf("modbus server listening at :%d", port)
+
+	srv, err := mbserver.New(h)
+
+	if err == nil {
+		err = srv.Start(l)
+	}
+
+	return err
+}
+
+// Run collects the published site values
+func (h *Server) Run(in <-chan util.Param) {
+	for p := range in {
+		if p.LoadPoint != nil {
+			continue
+		}
+
+		if f, ok := p.Val.(float64); ok {
+			h.mu.Lock()
+			h.values[p.Key] = f
+			h.mu.Unlock()
+		}
+	}
+}
+
+func (h *Server) value(key string) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.values[key]
+}
+
+// uint16Value clamps v to the register range
+func uint16Value(v float64) uint16 {
+	return uint16(math.Max(0, math.Min(math.MaxUint16, math.Round(v))))
+}
+
+// int32Value clamps v to two registers
+func int32Value(v float64) []uint16 {
+	u := uint32(int32(math.Max(math.MinInt32, math.Min(math.MaxInt32, math.Round(v)))))
+	return []uint16{uint16(u >> 16), uint16(u)}
+}
+
+// uint32Value clamps v to two registers
+func uint32Value(v float64) []uint16 {
+	u := uint32(math.Max(0, math.Min(math.MaxUint32, math.Round(v))))
+	return []uint16{uint16(u >> 16), uint16(u)}
+}
+
+func modeValue(mode api.ChargeMode) uint16 {
+	for i, m := range modes {
+		if m == mode {
+			return uint16(i)
+		}
+	}
+	return 0
+}
+
+func statusValue(status api.ChargeStatus) uint16 {
+	for i, s := range statuses {
+		if s == status {
+			return uint16(i)
+		}
+	}
+	return 0
+}
+
+// loadpoint returns the loadpoint for the register address' block or nil for the site block
+func (h *Server) loadpoint(addr uint16) (loadpoint.API, bool) {
+	id := int(addr / loadpointBase)
+	if id == 0 {
+		return nil, true
+	}
+
+	lps := h.site.LoadPoints()
+	if id > len(lps) {
+		return nil, false
+	}
+
+	return lps[id-1], true
+}
+
+// block returns the registers of the block containing addr and the offset of addr
+func (h *Server) block(addr uint16, site func() []uint16, lp func(loadpoint.API) []uint16) ([]uint16, uint16, error) {
+	l, ok := h.loadpoint(addr)
+	if !ok {
+		return nil, 0, mbserver.ErrIllegalDataAddress
+	}
+
+	if l == nil {
+		return site(), addr, nil
+	}
+
+	return lp(l), addr % loadpointBase, nil
+}
+
+// read returns quantity registers starting at addr of a single block
+func (h *Server) read(addr, quantity uint16, site func() []uint16, lp func(loadpoint.API) []uint16) ([]uint16, error) {
+	regs, offset, err := h.block(addr, site, lp)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(offset)+int(quantity) > len(regs) {
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	return regs[offset : offset+quantity], nil
+}
+
+func (h *Server) siteInputs() []uint16 {
+	res := []uint16{Version, uint16(len(h.site.LoadPoints()))}
+	res = append(res, int32Value(h.value("gridPower"))...)
+	res = append(res, int32Value(h.value("pvPower"))...)
+	res = append(res, int32Value(h.value("batteryPower"))...)
+	return append(res, uint16Value(h.value("batterySoC")))
+}
+
+func loadpointInputs(lp loadpoint.API) []uint16 {
+	res := []uint16{modeValue(lp.GetMode()), statusValue(lp.GetStatus())}
+	res = append(res, int32Value(lp.GetChargePower())...)
+	res = append(res, uint32Value(lp.GetChargedEnergy())...)
+	return append(res,
+		uint16Value(lp.GetVehicleSoC()),
+		uint16(lp.GetTargetSoC()),
+		uint16(lp.GetMinSoC()),
+		uint16Value(lp.GetMaxCurrent()),
+	)
+}
+
+func (h *Server) siteHoldings() []uint16 {
+	return []uint16{
+		uint16Value(h.site.GetPrioritySoC()),
+		uint16Value(h.site.GetBufferSoC()),
+	}
+}
+
+func loadpointHoldings(lp loadpoint.API) []uint16 {
+	return []uint16{
+		modeValue(lp.GetMode()),
+		uint16(lp.GetTargetSoC()),
+		uint16(lp.GetMinSoC()),
+		uint16Value(lp.GetMaxCurrent()),
+	}
+}
+
+func validSoC(val uint16) error {
+	if val > 100 {
+		return mbserver.ErrIllegalDataValue
+	}
+	return nil
+}
+
+// writeSite writes a single site holding register
+func (h *Server) writeSite(offset, val uint16) error {
+	if err := validSoC(val); err != nil {
+		return err
+	}
+
+	var err error
+	switch offset {
+	case 0:
+		err = h.site.SetPrioritySoC(float64(val))
+	case 1:
+		err = h.site.SetBufferSoC(float64(val))
+	default:
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	if err != nil {
+		h.log.ERROR.Println(err)
+		return mbserver.ErrServerDeviceFailure
+	}
+
+	return nil
+}
+
+// writeLoadpoint writes a single loadpoint holding register
+func writeLoadpoint(lp loadpoint.API, offset, val uint16) error {
+	switch offset {
+	case 0:
+		if int(val) >= len(modes) {
+			return mbserver.ErrIllegalDataValue
+		}
+		lp.SetMode(modes[val])
+	case 1:
+		if err := validSoC(val); err != nil {
+			return err
+		}
+		lp.SetTargetSoC(int(val))
+	case 2:
+		if err := validSoC(val); err != nil {
+			return err
+		}
+		lp.SetMinSoC(int(val))
+	case 3:
+		if float64(val) < lp.GetMinCurrent() {
+			return mbserver.ErrIllegalDataValue
+		}
+		lp.SetMaxCurrent(float64(val))
+	default:
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	return nil
+}
+
+// HandleInputRegisters implements the mbserver.RequestHandler interface
+func (h *Server) HandleInputRegisters(req *mbserver.InputRegistersRequest) ([]uint16, error) {
+	h.log.TRACE.Printf("read input: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
+	return h.read(req.Addr, req.Quantity, h.siteInputs, loadpointInputs)
+}
+
+// HandleHoldingRegisters implements the mbserver.RequestHandler interface
+func (h *Server) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	if !req.IsWrite {
+		h.log.TRACE.Printf("read holding: id: %d addr: %d qty: %d", req.UnitId, req.Addr, req.Quantity)
+		return h.read(req.Addr, req.Quantity, h.siteHoldings, loadpointHoldings)
+	}
+
+	if h.readOnly {
+		return nil, mbserver.ErrIllegalFunction
+	}
+
+	h.log.TRACE.Printf("write holding: id: %d addr: %d qty: %d val: %v", req.UnitId, req.Addr, req.Quantity, req.Args)
+
+	// validate address range before writing
+	if _, err := h.read(req.Addr, req.Quantity, h.siteHoldings, loadpointHoldings); err != nil {
+		return nil, err
+	}
+
+	lp, _ := h.loadpoint(req.Addr)
+
+	for i, val := range req.Args {
+		addr := req.Addr + uint16(i)
+
+		var err error
+		if lp == nil {
+			err = h.writeSite(addr, val)
+		} else {
+			err = writeLoadpoint(lp, addr%loadpointBase, val)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+package modbus
+
+import (
+	"net"
+	"testing"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSite struct {
+	site.API
+	lps         []loadpoint.API
+	prioritySoC float64
+}
+
+func (s *testSite) LoadPoints() []loadpoint.API      { return s.lps }
+func (s *testSite) GetPrioritySoC() float64          { return s.prioritySoC }
+func (s *testSite) GetBufferSoC() float64            { return 0 }
+func (s *testSite) SetPrioritySoC(soc float64) error { s.prioritySoC = soc; return nil }
+
+type testLoadpoint struct {
+	loadpoint.API
+	mode       api.ChargeMode
+	targetSoC  int
+	maxCurrent float64
+}
+
+func (lp *testLoadpoint) GetMode() api.ChargeMode       { return lp.mode }
+func (lp *testLoadpoint) SetMode(mode api.ChargeMode)   { lp.mode = mode }
+func (lp *testLoadpoint) GetStatus() api.ChargeStatus   { return api.StatusC }
+func (lp *testLoadpoint) GetChargePower() float64       { return 11000 }
+func (lp *testLoadpoint) GetChargedEnergy() float64     { return 70000 }
+func (lp *testLoadpoint) GetVehicleSoC() float64        { return 55.4 }
+func (lp *testLoadpoint) GetTargetSoC() int             { return lp.targetSoC }
+func (lp *testLoadpoint) SetTargetSoC(soc int)          { lp.targetSoC = soc }
+func (lp *testLoadpoint) GetMinSoC() int                { return 10 }
+func (lp *testLoadpoint) GetMinCurrent() float64        { return 6 }
+func (lp *testLoadpoint) GetMaxCurrent() float64        { return lp.maxCurrent }
+func (lp *testLoadpoint) SetMaxCurrent(current float64) { lp.maxCurrent = current }
+
+func TestServer(t *testing.T) {
+	lp := &testLoadpoint{mode: api.ModePV, targetSoC: 80, maxCurrent: 16}
+	site := &testSite{lps: []loadpoint.API{lp}}
+
+	h := NewServer(site, false)
+
+	in := make(chan util.Param, 2)
+	in <- util.Param{Key: "gridPower", Val: -1500.0}
+	in <- util.Param{Key: "batterySoC", Val: 67.0}
+	close(in)
+	h.Run(in)
+
+	l, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	srv, _ := mbserver.New(h)
+	require.NoError(t, srv.Start(l))
+	defer func() { _ = srv.Stop() }()
+
+	conn, err := modbus.NewConnection(l.Addr().String(), "", "", 0, modbus.Tcp, 1)
+	require.NoError(t, err)
+
+	// site
+	b, err := conn.ReadInputRegisters(0, 9)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{Version, 1, 0xFFFF, 0xFA24, 0, 0, 0, 0, 67}, bytesAsUint16(b))
+
+	// loadpoint
+	b, err = conn.ReadInputRegisters(100, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{3, 3, 0, 11000, 1, 4464, 55, 80, 10, 16}, bytesAsUint16(b))
+
+	// beyond block
+	_, err = conn.ReadInputRegisters(105, 10)
+	assert.Error(t, err)
+
+	// missing loadpoint
+	_, err = conn.ReadInputRegisters(200, 1)
+	assert.Error(t, err)
+
+	// write loadpoint
+	_, err = conn.WriteMultipleRegisters(100, 2, asBytes([]uint16{1, 90}))
+	require.NoError(t, err)
+	assert.Equal(t, api.ModeNow, lp.mode)
+	assert.Equal(t, 90, lp.targetSoC)
+
+	_, err = conn.WriteSingleRegister(103, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, lp.maxCurrent)
+
+	_, err = conn.WriteSingleRegister(100, 4)
+	require.NoError(t, err)
+	assert.Equal(t, api.ModeV2H, lp.mode)
+
+	b, err = conn.ReadInputRegisters(100, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{4}, bytesAsUint16(b))
+
+	// invalid values
+	_, err = conn.WriteSingleRegister(100, 5)
+	assert.Error(t, err)
+	_, err = conn.WriteSingleRegister(103, 5)
+	assert.Error(t, err)
+	assert.Equal(t, 10.0, lp.maxCurrent)
+
+	// write site
+	_, err = conn.WriteSingleRegister(0, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, site.prioritySoC)
+
+	b, err = conn.ReadHoldingRegisters(0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{50, 0}, bytesAsUint16(b))
+}
+
+func TestServerReadOnly(t *testing.T) {
+	lp := &testLoadpoint{mode: api.ModePV}
+	h := NewServer(&testSite{lps: []loadpoint.API{lp}}, true)
+
+	_, err := h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{
+		Addr:     100,
+		Quantity: 1,
+		IsWrite:  true,
+		Args:     []uint16{0},
+	})
+
+	assert.Equal(t, mbserver.ErrIllegalFunction, err)
+	assert.Equal(t, api.ModePV, lp.mode)
+}
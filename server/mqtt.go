@@ -1,7 +1,9 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -45,8 +47,15 @@ func (m *MQTT) encode(v interface{}) string {
 		return fmt.Sprintf("%d", int64(val.Seconds()))
 	case fmt.Stringer:
 		return val.String()
+	}
+
+	// publish complex values as json
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		b, _ := json.Marshal(v)
+		return string(b)
 	default:
-		return fmt.Sprintf("%v", val)
+		return fmt.Sprintf("%v", v)
 	}
 }
 
@@ -162,6 +171,15 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 		m.listenSetters(topic, site, lp)
 	}
 
+	// consumer setters
+	for id, c := range site.Consumers() {
+		c := c
+		topic := fmt.Sprintf("%s/consumers/%d/mode/set", m.root, id+1)
+		m.Handler.ListenSetter(topic, func(payload string) {
+			_ = c.SetMode(api.ChargeMode(payload))
+		})
+	}
+
 	// TODO remove deprecated topics
 	for id := range site.LoadPoints() {
 		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMqttEncode(t *testing.T) {
+	m := new(MQTT)
+	ts := time.Unix(1700000000, 0).UTC()
+
+	for _, tc := range []struct {
+		in       interface{}
+		expected string
+	}{
+		{nil, ""},
+		{"foo", "foo"},
+		{1.5, "1.5"},
+		{3, "3"},
+		{true, "true"},
+		{ts, "1700000000"},
+		{90 * time.Second, "90"},
+		{api.ModePV, "pv"},
+		{api.Rates{{Start: ts, End: ts.Add(time.Hour), Price: 0.3}}, `[{"start":"2023-11-14T22:13:20Z","end":"2023-11-14T23:13:20Z","price":0.3}]`},
+		{[]struct {
+			Title string `json:"title"`
+			Level int    `json:"level"`
+		}{{"heatpump", 2}}, `[{"title":"heatpump","level":2}]`},
+		{map[string]struct {
+			Firmware string `json:"firmware,omitempty"`
+		}{"cp1": {"Installed"}}, `{"cp1":{"firmware":"Installed"}}`},
+		{[]planner.Slot{{Start: ts, End: ts.Add(time.Hour), Price: 0.3, Cost: 3.3}}, `[{"start":"2023-11-14T22:13:20Z","end":"2023-11-14T23:13:20Z","price":0.3,"cost":3.3}]`},
+	} {
+		assert.Equal(t, tc.expected, m.encode(tc.in), tc.in)
+	}
+}
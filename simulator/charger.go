@@ -0,0 +1,390 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+const (
+	voltage    = 230              // V
+	minCurrent = 6                // A, minimum current according to IEC 61851
+	step       = time.Second      // integration step
+	delay      = 10 * time.Second // default delay between enabling and charging
+)
+
+// ChargerConfig is the simulated charger's configuration
+type ChargerConfig struct {
+	Vehicle     string        // id of the connected vehicle, a default vehicle is used if empty
+	Arrival     string        // HH:MM the vehicle is connected, always connected if empty
+	Departure   string        // HH:MM the vehicle is disconnected
+	MaxCurrent  float64       // A
+	Phases      int           // phases connected to the charger
+	Delay       time.Duration // delay between enabling and charging
+	RampRate    float64       // current increase in A/s once charging
+	Ventilation bool          // report status D instead of C while charging
+}
+
+// Charger is a simulated charger following the IEC 61851 charging states
+type Charger struct {
+	mu          sync.Mutex
+	site        *Site
+	vehicleId   string
+	vehicle     *Vehicle
+	arrival     time.Duration
+	departure   time.Duration
+	maxCurrent  float64
+	phases      int
+	delay       time.Duration
+	rampRate    float64
+	ventilation bool
+
+	updated   time.Time
+	connected bool
+	enabled   bool
+	enabledAt time.Time // start of delay
+	setpoint  float64   // current limit
+	current   float64   // actual current
+	fault     api.ChargeStatus
+	session   float64 // kWh
+	total     float64 // kWh
+}
+
+// AddCharger adds a simulated charger to the site
+func (s *Site) AddCharger(conf ChargerConfig) (*Charger, error) {
+	if conf.MaxCurrent == 0 {
+		conf.MaxCurrent = 16
+	}
+
+	if conf.MaxCurrent < minCurrent {
+		return nil, fmt.Errorf("invalid max current: %.1fA", conf.MaxCurrent)
+	}
+
+	if conf.Phases == 0 {
+		conf.Phases = 3
+	}
+
+	if conf.Phases != 1 && conf.Phases != 3 {
+		return nil, fmt.Errorf("invalid phases: %d", conf.Phases)
+	}
+
+	if conf.Delay == 0 {
+		conf.Delay = delay
+	}
+
+	if conf.RampRate == 0 {
+		conf.RampRate = 2
+	}
+
+	arrival, err := parseTimeOfDay(conf.Arrival)
+	if err != nil {
+		return nil, fmt.Errorf("arrival: %w", err)
+	}
+
+	departure, err := parseTimeOfDay(conf.Departure)
+	if err != nil {
+		return nil, fmt.Errorf("departure: %w", err)
+	}
+
+	if (conf.Arrival == "") != (conf.Departure == "") {
+		return nil, errors.New("need both arrival and departure")
+	}
+
+	c := &Charger{
+		site:        s,
+		vehicleId:   conf.Vehicle,
+		arrival:     arrival,
+		departure:   departure,
+		maxCurrent:  conf.MaxCurrent,
+		phases:      conf.Phases,
+		delay:       conf.Delay,
+		rampRate:    conf.RampRate,
+		ventilation: conf.Ventilation,
+		setpoint:    minCurrent,
+		updated:     s.clock.Now(),
+	}
+
+	c.connected = c.scheduled(c.updated)
+
+	if conf.Vehicle == "" {
+		c.vehicle, _ = NewVehicle("", VehicleConfig{Capacity: 50, SoC: 20})
+	}
+
+	s.mu.Lock()
+	s.chargers = append(s.chargers, c)
+	s.mu.Unlock()
+
+	return c, nil
+}
+
+// scheduled returns true if the vehicle is connected at the given time
+func (c *Charger) scheduled(now time.Time) bool {
+	if c.arrival == c.departure {
+		return true
+	}
+
+	tod := timeOfDay(now)
+	if c.arrival < c.departure {
+		return tod >= c.arrival && tod < c.departure
+	}
+
+	// overnight
+	return tod >= c.arrival || tod < c.departure
+}
+
+// effectivePhases returns the phases used for charging
+func (c *Charger) effectivePhases() int {
+	if c.vehicle != nil && c.vehicle.Phases() < c.phases {
+		return c.vehicle.Phases()
+	}
+	return c.phases
+}
+
+// update advances the simulation to the given time
+func (c *Charger) update(now time.Time) error {
+	if c.vehicle == nil {
+		v, err := c.site.vehicle(c.vehicleId)
+		if err != nil {
+			return err
+		}
+		c.vehicle = v
+	}
+
+	for c.updated.Before(now) {
+		dt := now.Sub(c.updated)
+		if dt > step {
+			dt = step
+		}
+
+		c.advance(c.updated.Add(dt), dt)
+	}
+
+	return nil
+}
+
+// advance performs a single integration step ending at the given time
+func (c *Charger) advance(now time.Time, dt time.Duration) {
+	c.updated = now
+
+	connected := c.scheduled(now)
+	if connected && !c.connected {
+		c.vehicle.arrive()
+		c.session = 0
+	}
+	c.connected = connected
+
+	var target float64
+	if connected && c.fault == api.StatusNone && c.enabled && now.Sub(c.enabledAt) > c.delay {
+		target = math.Min(c.setpoint, c.vehicle.acceptedCurrent())
+	}
+
+	prev := c.current
+	if target > c.current {
+		c.current = math.Min(target, c.current+c.rampRate*dt.Seconds())
+	} else {
+		c.current = target
+	}
+
+	energy := (prev + c.current) / 2 * voltage * float64(c.effectivePhases()) * dt.Hours() / 1e3
+	if energy > 0 {
+		c.session += energy
+		c.total += energy
+		c.vehicle.charge(energy)
+	}
+}
+
+// Update advances the simulation to the current time
+func (c *Charger) Update() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.update(c.site.clock.Now())
+}
+
+// Status implements the api.Charger interface
+func (c *Charger) Status() (api.ChargeStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.update(c.site.clock.Now()); err != nil {
+		return api.StatusNone, err
+	}
+
+	switch {
+	case !c.connected:
+		return api.StatusA, nil
+	case c.fault != api.StatusNone:
+		return c.fault, nil
+	case c.current > 0 && c.ventilation:
+		return api.StatusD, nil
+	case c.current > 0:
+		return api.StatusC, nil
+	default:
+		return api.StatusB, nil
+	}
+}
+
+// Enabled implements the api.Charger interface
+func (c *Charger) Enabled() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (c *Charger) Enable(enable bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.site.clock.Now()
+	if err := c.update(now); err != nil {
+		return err
+	}
+
+	if enable && !c.enabled {
+		c.enabledAt = now
+	}
+	c.enabled = enable
+
+	if !enable {
+		c.current = 0
+	}
+
+	return nil
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *Charger) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *Charger) MaxCurrentMillis(current float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current < minCurrent || current > c.maxCurrent {
+		return fmt.Errorf("invalid current %.1fA", current)
+	}
+
+	if err := c.update(c.site.clock.Now()); err != nil {
+		return err
+	}
+
+	c.setpoint = current
+
+	return nil
+}
+
+// SetPhases switches the charger's phases. Switching interrupts charging for the charger's delay.
+func (c *Charger) SetPhases(phases int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if phases != 1 && phases != 3 {
+		return fmt.Errorf("invalid phases: %d", phases)
+	}
+
+	now := c.site.clock.Now()
+	if err := c.update(now); err != nil {
+		return err
+	}
+
+	if phases != c.phases {
+		c.phases = phases
+		c.current = 0
+		c.enabledAt = now
+	}
+
+	return nil
+}
+
+// SetFault sets the charger's error state. Use api.StatusE or api.StatusF to inject a fault and api.StatusNone to clear it.
+func (c *Charger) SetFault(status api.ChargeStatus) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch status {
+	case api.StatusNone, api.StatusE, api.StatusF:
+	default:
+		return fmt.Errorf("invalid fault status: %s", status)
+	}
+
+	if err := c.update(c.site.clock.Now()); err != nil {
+		return err
+	}
+
+	c.fault = status
+
+	if status != api.StatusNone {
+		c.current = 0
+	}
+
+	return nil
+}
+
+// CurrentPower implements the api.Meter interface
+func (c *Charger) CurrentPower() (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.update(c.site.clock.Now()); err != nil {
+		return 0, err
+	}
+
+	return c.current * voltage * float64(c.effectivePhases()), nil
+}
+
+// Currents implements the api.MeterCurrent interface
+func (c *Charger) Currents() (float64, float64, float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.update(c.site.clock.Now()); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if c.effectivePhases() == 1 {
+		return c.current, 0, 0, nil
+	}
+
+	return c.current, c.current, c.current, nil
+}
+
+// ChargedEnergy implements the api.ChargeRater interface
+func (c *Charger) ChargedEnergy() (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.update(c.site.clock.Now())
+
+	return c.session, err
+}
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (c *Charger) TotalEnergy() (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.update(c.site.clock.Now())
+
+	return c.total, err
+}
+
+// Identify implements the api.Identifier interface
+func (c *Charger) Identify() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// default vehicle has no identifier
+	if err := c.update(c.site.clock.Now()); err != nil || !c.connected {
+		return "", err
+	}
+
+	return c.vehicle.ID(), nil
+}
@@ -0,0 +1,140 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSite(start time.Time, speed float64) (*Site, *clock.Mock) {
+	clock := clock.NewMock()
+	return NewSite(NewClock(clock, start, speed)), clock
+}
+
+func status(t *testing.T, c *Charger) api.ChargeStatus {
+	t.Helper()
+	status, err := c.Status()
+	require.NoError(t, err)
+	return status
+}
+
+func power(t *testing.T, c *Charger) float64 {
+	t.Helper()
+	p, err := c.CurrentPower()
+	require.NoError(t, err)
+	return p
+}
+
+func TestChargerStates(t *testing.T) {
+	site, clock := newTestSite(time.Date(2022, 6, 21, 6, 0, 0, 0, time.UTC), 1)
+
+	_, err := site.AddVehicle("ev", VehicleConfig{Capacity: 50, SoC: 20})
+	require.NoError(t, err)
+
+	c, err := site.AddCharger(ChargerConfig{Vehicle: "ev", Arrival: "07:00", Departure: "08:00"})
+	require.NoError(t, err)
+
+	assert.Equal(t, api.StatusA, status(t, c))
+
+	clock.Add(time.Hour)
+	assert.Equal(t, api.StatusB, status(t, c))
+
+	id, err := c.Identify()
+	require.NoError(t, err)
+	assert.Equal(t, "ev", id)
+
+	require.NoError(t, c.Enable(true))
+	assert.Equal(t, api.StatusB, status(t, c), "delay")
+
+	clock.Add(delay + time.Second)
+	assert.Equal(t, api.StatusC, status(t, c))
+
+	require.NoError(t, c.SetFault(api.StatusF))
+	assert.Equal(t, api.StatusF, status(t, c))
+	assert.Equal(t, 0.0, power(t, c))
+
+	require.NoError(t, c.SetFault(api.StatusNone))
+	clock.Add(time.Second)
+	assert.Equal(t, api.StatusC, status(t, c))
+
+	clock.Add(time.Hour)
+	assert.Equal(t, api.StatusA, status(t, c))
+}
+
+func TestChargerRamp(t *testing.T) {
+	site, clock := newTestSite(time.Time{}, 1)
+
+	c, err := site.AddCharger(ChargerConfig{Delay: 5 * time.Second, RampRate: 1})
+	require.NoError(t, err)
+
+	assert.Error(t, c.MaxCurrent(5))
+	assert.Error(t, c.MaxCurrent(17))
+	require.NoError(t, c.MaxCurrent(16))
+	require.NoError(t, c.Enable(true))
+
+	clock.Add(5 * time.Second)
+	assert.Equal(t, 0.0, power(t, c))
+
+	clock.Add(4 * time.Second)
+	assert.Equal(t, float64(4*3*voltage), power(t, c))
+
+	clock.Add(time.Minute)
+	assert.Equal(t, float64(16*3*voltage), power(t, c))
+
+	// ramp down is immediate
+	require.NoError(t, c.MaxCurrent(6))
+	clock.Add(time.Second)
+	assert.Equal(t, float64(6*3*voltage), power(t, c))
+
+	// phase switching restarts the delay
+	require.NoError(t, c.SetPhases(1))
+	clock.Add(time.Second)
+	assert.Equal(t, 0.0, power(t, c))
+
+	clock.Add(time.Minute)
+	assert.Equal(t, float64(6*voltage), power(t, c))
+
+	l1, l2, l3, err := c.Currents()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{6, 0, 0}, []float64{l1, l2, l3})
+}
+
+func TestChargerVehicle(t *testing.T) {
+	site, clock := newTestSite(time.Time{}, 60)
+
+	v, err := site.AddVehicle("ev", VehicleConfig{Capacity: 10, Phases: 1, SoC: 50})
+	require.NoError(t, err)
+
+	c, err := site.AddCharger(ChargerConfig{Vehicle: "ev"})
+	require.NoError(t, err)
+
+	require.NoError(t, c.MaxCurrent(16))
+	require.NoError(t, c.Enable(true))
+
+	// single phase vehicle
+	clock.Add(time.Second)
+	assert.Equal(t, float64(16*voltage), power(t, c))
+
+	// 4 simulated hours charge the 10kWh vehicle to full including taper
+	clock.Add(4 * time.Minute)
+	assert.Equal(t, api.StatusB, status(t, c))
+	assert.Equal(t, 100.0, v.SoC())
+
+	energy, err := c.ChargedEnergy()
+	require.NoError(t, err)
+	assert.InDelta(t, 5, energy, 0.01)
+}
+
+func TestChargerMissingVehicle(t *testing.T) {
+	site, _ := newTestSite(time.Time{}, 1)
+
+	c, err := site.AddCharger(ChargerConfig{Vehicle: "foo"})
+	require.NoError(t, err)
+
+	_, err = c.Status()
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package simulator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Clock is the simulation time running at configurable speed. It implements clock.Clock
+// by scaling durations to real time for sharing the simulation time with site and loadpoints.
+type Clock struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	real  time.Time // real time at start
+	start time.Time // simulation time at start
+	speed float64
+}
+
+// NewClock creates a simulation clock starting at the given time.
+// Speed is the number of simulated seconds per real second.
+func NewClock(clock clock.Clock, start time.Time, speed float64) *Clock {
+	if start.IsZero() {
+		start = clock.Now()
+	}
+
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &Clock{
+		clock: clock,
+		real:  clock.Now(),
+		start: start,
+		speed: speed,
+	}
+}
+
+// Now returns the current simulation time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := c.clock.Since(c.real)
+	return c.start.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// Speed returns the clock's speed-up factor
+func (c *Clock) Speed() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.speed
+}
+
+var _ clock.Clock = (*Clock)(nil)
+
+// scale converts a simulated duration into real time
+func (c *Clock) scale(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / c.Speed())
+}
+
+// After implements the clock.Clock interface
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.clock.After(c.scale(d))
+}
+
+// AfterFunc implements the clock.Clock interface
+func (c *Clock) AfterFunc(d time.Duration, f func()) *clock.Timer {
+	return c.clock.AfterFunc(c.scale(d), f)
+}
+
+// Since implements the clock.Clock interface
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Until implements the clock.Clock interface
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// Sleep implements the clock.Clock interface
+func (c *Clock) Sleep(d time.Duration) {
+	c.clock.Sleep(c.scale(d))
+}
+
+// Tick implements the clock.Clock interface
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	return c.clock.Tick(c.scale(d))
+}
+
+// Ticker implements the clock.Clock interface
+func (c *Clock) Ticker(d time.Duration) *clock.Ticker {
+	return c.clock.Ticker(c.scale(d))
+}
+
+// Timer implements the clock.Clock interface
+func (c *Clock) Timer(d time.Duration) *clock.Timer {
+	return c.clock.Timer(c.scale(d))
+}
+
+// WithDeadline implements the clock.Clock interface
+func (c *Clock) WithDeadline(parent context.Context, t time.Time) (context.Context, context.CancelFunc) {
+	return c.clock.WithTimeout(parent, c.scale(c.Until(t)))
+}
+
+// WithTimeout implements the clock.Clock interface
+func (c *Clock) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return c.clock.WithTimeout(parent, c.scale(d))
+}
+
+// timeOfDay returns the duration since midnight
+func timeOfDay(t time.Time) time.Duration {
+	y, m, d := t.Date()
+	return t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+}
+
+// parseTimeOfDay parses HH:MM into the duration since midnight
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
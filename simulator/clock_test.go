@@ -0,0 +1,38 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	clck := clock.NewMock()
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClock(clck, start, 60)
+
+	clck.Add(time.Minute)
+	assert.Equal(t, start.Add(time.Hour), c.Now())
+	assert.Equal(t, time.Hour, c.Since(start))
+	assert.Equal(t, time.Hour, c.Until(start.Add(2*time.Hour)))
+
+	// simulated durations elapse at speed
+	timer := c.Timer(time.Hour)
+	clck.Add(59 * time.Second)
+
+	select {
+	case <-timer.C:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	clck.Add(time.Second)
+
+	select {
+	case <-timer.C:
+	default:
+		t.Fatal("timer not fired")
+	}
+}
@@ -0,0 +1,90 @@
+package simulator
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	sunrise = 6 * time.Hour  // start of default pv profile
+	sunset  = 20 * time.Hour // end of default pv profile
+)
+
+// PVConfig is the simulated pv meter's configuration
+type PVConfig struct {
+	Peak    float64   // peak power in W
+	Profile []float64 // optional hourly factors of peak power, interpolated in between
+}
+
+// PV is a simulated pv meter following a daily profile
+type PV struct {
+	site    *Site
+	peak    float64
+	profile []float64
+}
+
+// AddPV adds a simulated pv meter to the site
+func (s *Site) AddPV(conf PVConfig) (*PV, error) {
+	if conf.Peak <= 0 {
+		return nil, errors.New("missing peak power")
+	}
+
+	if len(conf.Profile) > 0 && len(conf.Profile) != 24 {
+		return nil, errors.New("profile must have 24 hourly values")
+	}
+
+	m := &PV{
+		site:    s,
+		peak:    conf.Peak,
+		profile: conf.Profile,
+	}
+
+	s.mu.Lock()
+	s.pv = append(s.pv, m)
+	s.mu.Unlock()
+
+	return m, nil
+}
+
+// factor returns the share of peak power at the given time of day
+func (m *PV) factor(tod time.Duration) float64 {
+	if len(m.profile) == 0 {
+		if tod < sunrise || tod >= sunset {
+			return 0
+		}
+
+		return math.Sin(math.Pi * float64(tod-sunrise) / float64(sunset-sunrise))
+	}
+
+	hours := tod.Hours()
+	idx := int(hours)
+	next := (idx + 1) % len(m.profile)
+	frac := hours - float64(idx)
+
+	return m.profile[idx] + frac*(m.profile[next]-m.profile[idx])
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *PV) CurrentPower() (float64, error) {
+	return m.peak * m.factor(timeOfDay(m.site.clock.Now())), nil
+}
+
+// Grid is a simulated grid meter balancing the site's consumption and production
+type Grid struct {
+	site     *Site
+	baseload float64
+}
+
+// Grid creates a simulated grid meter with the given household base load in W
+func (s *Site) Grid(baseload float64) *Grid {
+	return &Grid{
+		site:     s,
+		baseload: baseload,
+	}
+}
+
+// CurrentPower implements the api.Meter interface. Positive values denote grid import.
+func (m *Grid) CurrentPower() (float64, error) {
+	return m.baseload + m.site.ChargePower() - m.site.PVPower(), nil
+}
@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPVProfile(t *testing.T) {
+	site, clock := newTestSite(time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC), 3600)
+
+	pv, err := site.AddPV(PVConfig{Peak: 10e3})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		hour  time.Duration
+		power float64
+	}{
+		{0, 0},
+		{6, 0},
+		{13, 10e3},
+		{20, 0},
+	} {
+		clock.Set(time.Unix(0, 0).Add(tc.hour * time.Second))
+		p, err := pv.CurrentPower()
+		require.NoError(t, err)
+		assert.InDelta(t, tc.power, p, 1e-6, tc.hour)
+	}
+
+	profile := make([]float64, 24)
+	profile[12] = 1
+
+	pv, err = site.AddPV(PVConfig{Peak: 10e3, Profile: profile})
+	require.NoError(t, err)
+
+	clock.Set(time.Unix(0, 0).Add(12*time.Second + 500*time.Millisecond))
+	p, err := pv.CurrentPower()
+	require.NoError(t, err)
+	assert.InDelta(t, 5e3, p, 1e-6)
+
+	_, err = site.AddPV(PVConfig{Peak: 10e3, Profile: []float64{1}})
+	assert.Error(t, err)
+}
+
+func TestGrid(t *testing.T) {
+	site, clock := newTestSite(time.Date(2022, 6, 21, 13, 0, 0, 0, time.UTC), 1)
+
+	_, err := site.AddPV(PVConfig{Peak: 10e3})
+	require.NoError(t, err)
+
+	c, err := site.AddCharger(ChargerConfig{})
+	require.NoError(t, err)
+	require.NoError(t, c.MaxCurrent(16))
+	require.NoError(t, c.Enable(true))
+
+	grid := site.Grid(500)
+
+	p, err := grid.CurrentPower()
+	require.NoError(t, err)
+	assert.InDelta(t, 500-10e3, p, 1e-6)
+
+	clock.Add(time.Minute)
+	p, err = grid.CurrentPower()
+	require.NoError(t, err)
+	assert.InDelta(t, 500+16*3*voltage-10e3, p, 1)
+}
@@ -0,0 +1,121 @@
+package simulator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Config is the simulation configuration
+type Config struct {
+	Speed float64   // simulated seconds per real second, replays a full day in 24 minutes at speed 60
+	Start time.Time // simulation start time, defaults to now
+}
+
+// Site is the virtual site connecting the simulated chargers, vehicles and meters
+type Site struct {
+	mu       sync.Mutex
+	clock    *Clock
+	vehicles map[string]*Vehicle
+	chargers []*Charger
+	pv       []*PV
+}
+
+// NewSite creates a virtual site using the given simulation clock
+func NewSite(clock *Clock) *Site {
+	return &Site{
+		clock:    clock,
+		vehicles: make(map[string]*Vehicle),
+	}
+}
+
+var (
+	mu       sync.Mutex
+	instance *Site
+)
+
+// Configure replaces the virtual site using the given configuration.
+// Must be called before creating devices.
+func Configure(conf Config) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	instance = NewSite(NewClock(clock.New(), conf.Start, conf.Speed))
+}
+
+// Instance returns the virtual site
+func Instance() *Site {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if instance == nil {
+		instance = NewSite(NewClock(clock.New(), time.Time{}, 1))
+	}
+
+	return instance
+}
+
+// Clock returns the simulation clock
+func (s *Site) Clock() *Clock {
+	return s.clock
+}
+
+// vehicle returns the vehicle by id
+func (s *Site) vehicle(id string) (*Vehicle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vehicles[id]
+	if !ok {
+		return nil, fmt.Errorf("vehicle not found: %s", id)
+	}
+
+	return v, nil
+}
+
+// ChargePower returns the total power of all chargers
+func (s *Site) ChargePower() float64 {
+	s.mu.Lock()
+	chargers := s.chargers
+	s.mu.Unlock()
+
+	var res float64
+	for _, c := range chargers {
+		p, _ := c.CurrentPower()
+		res += p
+	}
+
+	return res
+}
+
+// PVPower returns the total power of all pv meters
+func (s *Site) PVPower() float64 {
+	s.mu.Lock()
+	pv := s.pv
+	s.mu.Unlock()
+
+	var res float64
+	for _, m := range pv {
+		p, _ := m.CurrentPower()
+		res += p
+	}
+
+	return res
+}
+
+// Update advances the simulation of all chargers to the current time
+func (s *Site) Update() error {
+	s.mu.Lock()
+	chargers := s.chargers
+	s.mu.Unlock()
+
+	for _, c := range chargers {
+		if err := c.Update(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
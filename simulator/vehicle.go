@@ -0,0 +1,138 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// taperSoC is the soc above which the vehicle reduces its charge current
+const taperSoC = 90
+
+// VehicleConfig is the simulated vehicle's configuration
+type VehicleConfig struct {
+	Capacity   float64 // kWh
+	Phases     int     // phases the vehicle's onboard charger supports
+	MaxCurrent float64 // A
+	SoC        float64 // soc in % on arrival
+}
+
+// Vehicle is a simulated vehicle
+type Vehicle struct {
+	mu         sync.Mutex
+	id         string
+	capacity   float64
+	phases     int
+	maxCurrent float64
+	initial    float64
+	soc        float64
+}
+
+// NewVehicle creates a simulated vehicle
+func NewVehicle(id string, conf VehicleConfig) (*Vehicle, error) {
+	if conf.Capacity <= 0 {
+		return nil, errors.New("missing capacity")
+	}
+
+	if conf.Phases == 0 {
+		conf.Phases = 3
+	}
+
+	if conf.Phases != 1 && conf.Phases != 3 {
+		return nil, errors.New("invalid phases")
+	}
+
+	if conf.MaxCurrent == 0 {
+		conf.MaxCurrent = 16
+	}
+
+	if conf.SoC < 0 || conf.SoC > 100 {
+		return nil, errors.New("invalid soc")
+	}
+
+	v := &Vehicle{
+		id:         id,
+		capacity:   conf.Capacity,
+		phases:     conf.Phases,
+		maxCurrent: conf.MaxCurrent,
+		initial:    conf.SoC,
+		soc:        conf.SoC,
+	}
+
+	return v, nil
+}
+
+// AddVehicle adds a simulated vehicle to the site
+func (s *Site) AddVehicle(id string, conf VehicleConfig) (*Vehicle, error) {
+	if id == "" {
+		return nil, errors.New("missing id")
+	}
+
+	v, err := NewVehicle(id, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vehicles[id]; ok {
+		return nil, fmt.Errorf("duplicate vehicle: %s", id)
+	}
+
+	s.vehicles[id] = v
+
+	return v, nil
+}
+
+// ID returns the vehicle's id
+func (v *Vehicle) ID() string {
+	return v.id
+}
+
+// Phases returns the phases the vehicle's onboard charger supports
+func (v *Vehicle) Phases() int {
+	return v.phases
+}
+
+// SoC returns the vehicle's soc in %
+func (v *Vehicle) SoC() float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.soc
+}
+
+// arrive resets the vehicle's soc when it is connected to a charger
+func (v *Vehicle) arrive() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.soc = v.initial
+}
+
+// acceptedCurrent returns the current the vehicle accepts. Current is reduced linearly above taperSoC
+// with a minimum of 1A to allow the battery to become full.
+func (v *Vehicle) acceptedCurrent() float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.soc >= 100 {
+		return 0
+	}
+
+	if v.soc > taperSoC {
+		return math.Max(1, v.maxCurrent*(100-v.soc)/(100-taperSoC))
+	}
+
+	return v.maxCurrent
+}
+
+// charge adds energy in kWh to the vehicle's battery
+func (v *Vehicle) charge(energy float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.soc = math.Min(100, v.soc+100*energy/v.capacity)
+}
@@ -21,7 +21,10 @@ type Awattar struct {
 	data  []awattar.PriceInfo
 }
 
-var _ api.Tariff = (*Awattar)(nil)
+var (
+	_ api.Tariff      = (*Awattar)(nil)
+	_ api.TariffRates = (*Awattar)(nil)
+)
 
 func NewAwattar(other map[string]interface{}) (*Awattar, error) {
 	cc := struct {
@@ -63,18 +66,30 @@ func (t *Awattar) Run() {
 }
 
 func (t *Awattar) CurrentPrice() (float64, error) {
+	rates, _ := t.Rates()
+
+	if rate, err := rates.Current(time.Now()); err == nil {
+		return rate.Price, nil
+	}
+
+	return 0, errors.New("unable to find current awattar price")
+}
+
+// Rates implements the api.TariffRates interface
+func (t *Awattar) Rates() (api.Rates, error) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
-	for i := len(t.data) - 1; i >= 0; i-- {
-		pi := t.data[i]
-
-		if pi.StartTimestamp.Before(time.Now()) && pi.EndTimestamp.After(time.Now()) {
-			return pi.Marketprice / 1000, nil // convert EUR/MWh to EUR/KWh
-		}
+	res := make(api.Rates, 0, len(t.data))
+	for _, r := range t.data {
+		res = append(res, api.Rate{
+			Start: r.StartTimestamp,
+			End:   r.EndTimestamp,
+			Price: r.Marketprice / 1e3, // convert EUR/MWh to EUR/KWh
+		})
 	}
 
-	return 0, errors.New("unable to find current awattar price")
+	return res, nil
 }
 
 func (t *Awattar) IsCheap() (bool, error) {
@@ -0,0 +1,50 @@
+package tariff
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// CustomCO2 provides the grid's carbon intensity using a configurable plugin
+type CustomCO2 struct {
+	intensityG func() (float64, error)
+}
+
+var _ api.CO2Intensity = (*CustomCO2)(nil)
+
+// NewCO2FromConfig creates new grid carbon intensity source from config
+func NewCO2FromConfig(typ string, other map[string]interface{}) (api.CO2Intensity, error) {
+	switch strings.ToLower(typ) {
+	case api.Custom:
+		return NewCustomCO2FromConfig(other)
+	default:
+		return nil, errors.New("unknown co2 source: " + typ)
+	}
+}
+
+// NewCustomCO2FromConfig creates a custom carbon intensity source from generic config
+func NewCustomCO2FromConfig(other map[string]interface{}) (*CustomCO2, error) {
+	var cc struct {
+		Intensity provider.Config
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	intensityG, err := provider.NewFloatGetterFromConfig(cc.Intensity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CustomCO2{intensityG: intensityG}, nil
+}
+
+// CurrentCO2Intensity implements the api.CO2Intensity interface
+func (t *CustomCO2) CurrentCO2Intensity() (float64, error) {
+	return t.intensityG()
+}
@@ -0,0 +1,30 @@
+package tariff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomCO2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"carbonIntensity":{"actual":null,"forecast":215}}]}`))
+	}))
+	defer srv.Close()
+
+	co2, err := NewCO2FromConfig("custom", map[string]interface{}{
+		"intensity": map[string]interface{}{
+			"source": "http",
+			"uri":    srv.URL,
+			"jq":     ".data[0].carbonIntensity.forecast",
+		},
+	})
+	require.NoError(t, err)
+
+	res, err := co2.CurrentCO2Intensity()
+	require.NoError(t, err)
+	assert.Equal(t, 215.0, res)
+}
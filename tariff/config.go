@@ -11,11 +11,15 @@ import (
 func NewFromConfig(typ string, other map[string]interface{}) (t api.Tariff, err error) {
 	switch strings.ToLower(typ) {
 	case "fixed":
-		t, err = NewFixed(other)
+		t, err = NewFixedFromConfig(other)
 	case "awattar":
 		t, err = NewAwattar(other)
 	case "tibber":
 		t, err = NewTibber(other)
+	case "entsoe":
+		t, err = NewEntsoe(other)
+	case api.Custom:
+		t, err = NewCustomFromConfig(other)
 	default:
 		return nil, errors.New("unknown tariff: " + typ)
 	}
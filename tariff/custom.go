@@ -0,0 +1,113 @@
+package tariff
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Custom is a tariff using configurable plugins for current price and price forecast
+type Custom struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	cheap    float64
+	priceG   func() (float64, error)
+	forecast func() (string, error)
+	data     api.Rates
+	updated  time.Time
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateCustom -b *Custom -r api.Tariff -t "api.TariffRates,Rates,func() (api.Rates, error)"
+
+// NewCustomFromConfig creates a custom tariff from generic config
+func NewCustomFromConfig(other map[string]interface{}) (api.Tariff, error) {
+	var cc struct {
+		Cheap    float64
+		Price    *provider.Config
+		Forecast *provider.Config
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Price == nil && cc.Forecast == nil {
+		return nil, errors.New("missing either price or forecast")
+	}
+
+	t := &Custom{
+		clock: clock.New(),
+		cheap: cc.Cheap,
+	}
+
+	if cc.Price != nil {
+		var err error
+		if t.priceG, err = provider.NewFloatGetterFromConfig(*cc.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	var rates func() (api.Rates, error)
+	if cc.Forecast != nil {
+		var err error
+		if t.forecast, err = provider.NewStringGetterFromConfig(*cc.Forecast); err != nil {
+			return nil, err
+		}
+
+		rates = t.rates
+	}
+
+	return decorateCustom(t, rates), nil
+}
+
+// rates decodes the forecast json list of start/end/price rates, updated hourly
+func (t *Custom) rates() (api.Rates, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.data != nil && t.clock.Since(t.updated) < time.Hour {
+		return t.data, nil
+	}
+
+	s, err := t.forecast()
+	if err != nil {
+		return nil, err
+	}
+
+	var res api.Rates
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return nil, err
+	}
+
+	res.Sort()
+
+	t.data = res
+	t.updated = t.clock.Now()
+
+	return res, nil
+}
+
+func (t *Custom) CurrentPrice() (float64, error) {
+	if t.priceG != nil {
+		return t.priceG()
+	}
+
+	rates, err := t.rates()
+	if err != nil {
+		return 0, err
+	}
+
+	rate, err := rates.Current(t.clock.Now())
+	return rate.Price, err
+}
+
+func (t *Custom) IsCheap() (bool, error) {
+	price, err := t.CurrentPrice()
+	return price <= t.cheap, err
+}
@@ -0,0 +1,35 @@
+package tariff
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateCustom(base *Custom, tariffRates func() (api.Rates, error)) api.Tariff {
+	switch {
+	case tariffRates == nil:
+		return base
+
+	case tariffRates != nil:
+		return &struct {
+			*Custom
+			api.TariffRates
+		}{
+			Custom: base,
+			TariffRates: &decorateCustomTariffRatesImpl{
+				tariffRates: tariffRates,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateCustomTariffRatesImpl struct {
+	tariffRates func() (api.Rates, error)
+}
+
+func (impl *decorateCustomTariffRatesImpl) Rates() (api.Rates, error) {
+	return impl.tariffRates()
+}
@@ -0,0 +1,97 @@
+package tariff
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustom(t *testing.T) {
+	start := time.Now().Truncate(time.Hour)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"current":0.25,"prices":[{"from":"%s","to":"%s","value":0.3},{"from":"%s","to":"%s","value":0.15}]}`,
+			start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339),
+			start.Add(time.Hour).Format(time.RFC3339), start.Add(2*time.Hour).Format(time.RFC3339),
+		)
+	}))
+	defer srv.Close()
+
+	tf, err := NewCustomFromConfig(map[string]interface{}{
+		"cheap": 0.2,
+		"price": map[string]interface{}{
+			"source": "http",
+			"uri":    srv.URL,
+			"jq":     ".current",
+		},
+	})
+	require.NoError(t, err)
+
+	_, ok := tf.(api.TariffRates)
+	assert.False(t, ok)
+
+	price, err := tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, price)
+
+	tf, err = NewCustomFromConfig(map[string]interface{}{
+		"cheap": 0.2,
+		"forecast": map[string]interface{}{
+			"source": "http",
+			"uri":    srv.URL,
+			"jq":     ".prices | map({start: .from, end: .to, price: .value}) | tojson",
+		},
+	})
+	require.NoError(t, err)
+
+	tr, ok := tf.(api.TariffRates)
+	require.True(t, ok)
+
+	rates, err := tr.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, 0.15, rates[1].Price)
+
+	price, err = tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, price)
+
+	cheap, err := tf.IsCheap()
+	require.NoError(t, err)
+	assert.False(t, cheap)
+
+}
+
+func TestCustomRatesCache(t *testing.T) {
+	clock := clock.NewMock()
+
+	var requests int
+	tf := &Custom{
+		clock: clock,
+		forecast: func() (string, error) {
+			requests++
+			return fmt.Sprintf(`[{"start":"%s","end":"%s","price":0.3}]`,
+				clock.Now().Format(time.RFC3339), clock.Now().Add(time.Hour).Format(time.RFC3339),
+			), nil
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		rates, err := tf.rates()
+		require.NoError(t, err)
+		require.Len(t, rates, 1)
+	}
+	assert.Equal(t, 1, requests)
+
+	clock.Add(time.Hour)
+	_, err := tf.rates()
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,144 @@
+package tariff
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/tariff/entsoe"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+type Entsoe struct {
+	mux     sync.Mutex
+	log     *util.Logger
+	uri     string
+	token   string
+	domain  string
+	cheap   float64
+	charges float64
+	tax     float64
+	data    api.Rates
+}
+
+var (
+	_ api.Tariff      = (*Entsoe)(nil)
+	_ api.TariffRates = (*Entsoe)(nil)
+)
+
+func NewEntsoe(other map[string]interface{}) (*Entsoe, error) {
+	var cc struct {
+		SecurityToken string
+		Domain        string
+		Cheap         float64
+		Charges       float64
+		Tax           float64
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.SecurityToken == "" {
+		return nil, errors.New("missing securitytoken")
+	}
+
+	if cc.Domain == "" {
+		return nil, errors.New("missing domain")
+	}
+
+	t := &Entsoe{
+		log:     util.NewLogger("entsoe").Redact(cc.SecurityToken),
+		uri:     entsoe.URI,
+		token:   cc.SecurityToken,
+		domain:  cc.Domain,
+		cheap:   cc.Cheap,
+		charges: cc.Charges,
+		tax:     cc.Tax,
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+// query fetches the day-ahead market prices for today and tomorrow
+func (t *Entsoe) query(client *request.Helper, now time.Time) (api.Rates, error) {
+	y, m, d := now.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	params := url.Values{
+		"securityToken": {t.token},
+		"documentType":  {entsoe.DocumentType},
+		"in_Domain":     {t.domain},
+		"out_Domain":    {t.domain},
+		"periodStart":   {start.UTC().Format(entsoe.TimeFormat)},
+		"periodEnd":     {start.AddDate(0, 0, 2).UTC().Format(entsoe.TimeFormat)},
+	}
+
+	b, err := client.GetBody(fmt.Sprintf("%s?%s", t.uri, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := entsoe.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Rates()
+}
+
+func (t *Entsoe) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(time.Hour).C {
+		res, err := t.query(client, time.Now())
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = res
+		t.mux.Unlock()
+	}
+}
+
+// price converts the market price in EUR/MWh to the consumer price
+func (t *Entsoe) price(market float64) float64 {
+	return (market/1e3 + t.charges) * (1 + t.tax)
+}
+
+func (t *Entsoe) CurrentPrice() (float64, error) {
+	rates, _ := t.Rates()
+
+	if rate, err := rates.Current(time.Now()); err == nil {
+		return rate.Price, nil
+	}
+
+	return 0, errors.New("unable to find current entsoe price")
+}
+
+// Rates implements the api.TariffRates interface
+func (t *Entsoe) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	res := make(api.Rates, 0, len(t.data))
+	for _, r := range t.data {
+		r.Price = t.price(r.Price)
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+func (t *Entsoe) IsCheap() (bool, error) {
+	price, err := t.CurrentPrice()
+	return price <= t.cheap, err
+}
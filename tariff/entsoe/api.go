@@ -0,0 +1,134 @@
+package entsoe
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// URI is the ENTSO-E Transparency Platform restful api
+const URI = "https://web-api.tp.entsoe.eu/api"
+
+// DocumentType for day-ahead prices
+const DocumentType = "A44"
+
+// TimeFormat is the time format of the api's period parameters
+const TimeFormat = "200601021504"
+
+// intervalFormat is the time format of the document's time intervals
+const intervalFormat = "2006-01-02T15:04Z"
+
+// PublicationMarketDocument is the day-ahead price document
+type PublicationMarketDocument struct {
+	XMLName    xml.Name     `xml:"Publication_MarketDocument"`
+	MRID       string       `xml:"mRID"`
+	TimeSeries []TimeSeries `xml:"TimeSeries"`
+}
+
+// AcknowledgementMarketDocument is returned instead of a publication if the request could not be served
+type AcknowledgementMarketDocument struct {
+	XMLName xml.Name `xml:"Acknowledgement_MarketDocument"`
+	Reason  []struct {
+		Code string `xml:"code"`
+		Text string `xml:"text"`
+	} `xml:"Reason"`
+}
+
+type TimeSeries struct {
+	Currency  string   `xml:"currency_Unit.name"`
+	Unit      string   `xml:"price_Measure_Unit.name"`
+	CurveType string   `xml:"curveType"`
+	Period    []Period `xml:"Period"`
+}
+
+type Period struct {
+	TimeInterval struct {
+		Start string `xml:"start"`
+		End   string `xml:"end"`
+	} `xml:"timeInterval"`
+	Resolution string  `xml:"resolution"`
+	Point      []Point `xml:"Point"`
+}
+
+type Point struct {
+	Position    int     `xml:"position"`
+	PriceAmount float64 `xml:"price.amount"`
+}
+
+// ParseResolution converts the ISO 8601 resolution, e.g. PT15M or PT60M, to a duration
+func ParseResolution(s string) (time.Duration, error) {
+	if !strings.HasPrefix(s, "PT") {
+		return 0, fmt.Errorf("invalid resolution: %s", s)
+	}
+
+	return time.ParseDuration(strings.ToLower(strings.TrimPrefix(s, "PT")))
+}
+
+// Parse decodes the api response into a publication document.
+// Acknowledgement documents are converted into errors.
+func Parse(b []byte) (PublicationMarketDocument, error) {
+	var res PublicationMarketDocument
+
+	if err := xml.Unmarshal(b, &res); err != nil {
+		var ack AcknowledgementMarketDocument
+		if xml.Unmarshal(b, &ack) != nil {
+			return res, err
+		}
+
+		var reasons []string
+		for _, r := range ack.Reason {
+			reasons = append(reasons, r.Text)
+		}
+
+		return res, errors.New(strings.Join(reasons, ", "))
+	}
+
+	return res, nil
+}
+
+// Rates converts the document's time series into market price rates in currency/MWh.
+// Points omitted by curve type A03 continue the preceding price.
+func (d PublicationMarketDocument) Rates() (api.Rates, error) {
+	var res api.Rates
+
+	for _, ts := range d.TimeSeries {
+		for _, p := range ts.Period {
+			start, err := time.Parse(intervalFormat, p.TimeInterval.Start)
+			if err != nil {
+				return nil, err
+			}
+
+			end, err := time.Parse(intervalFormat, p.TimeInterval.End)
+			if err != nil {
+				return nil, err
+			}
+
+			resolution, err := ParseResolution(p.Resolution)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, pt := range p.Point {
+				rate := api.Rate{
+					Start: start.Add(time.Duration(pt.Position-1) * resolution),
+					End:   end,
+					Price: pt.PriceAmount,
+				}
+
+				if i+1 < len(p.Point) {
+					rate.End = start.Add(time.Duration(p.Point[i+1].Position-1) * resolution)
+				}
+
+				res = append(res, rate)
+			}
+		}
+	}
+
+	res.Sort()
+
+	return res, nil
+}
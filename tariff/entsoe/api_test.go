@@ -0,0 +1,77 @@
+package entsoe
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolution(t *testing.T) {
+	for s, d := range map[string]time.Duration{
+		"PT15M": 15 * time.Minute,
+		"PT30M": 30 * time.Minute,
+		"PT60M": time.Hour,
+	} {
+		res, err := ParseResolution(s)
+		require.NoError(t, err)
+		assert.Equal(t, d, res, s)
+	}
+
+	_, err := ParseResolution("P1D")
+	assert.Error(t, err)
+}
+
+func TestPublication(t *testing.T) {
+	b, err := os.ReadFile("testdata/publication.xml")
+	require.NoError(t, err)
+
+	doc, err := Parse(b)
+	require.NoError(t, err)
+
+	rates, err := doc.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 24)
+
+	start := time.Date(2023, 1, 9, 23, 0, 0, 0, time.UTC)
+	assert.True(t, rates[0].Start.Equal(start))
+	assert.True(t, rates[0].End.Equal(start.Add(time.Hour)))
+	assert.Equal(t, 102.5, rates[0].Price)
+	assert.True(t, rates[23].End.Equal(start.Add(24*time.Hour)))
+	assert.Equal(t, 110.9, rates[23].Price)
+}
+
+func TestAcknowledgement(t *testing.T) {
+	b, err := os.ReadFile("testdata/acknowledgement.xml")
+	require.NoError(t, err)
+
+	_, err = Parse(b)
+	assert.ErrorContains(t, err, "No matching data found")
+}
+
+func TestCurveA03(t *testing.T) {
+	doc := PublicationMarketDocument{
+		TimeSeries: []TimeSeries{{
+			CurveType: "A03",
+			Period: []Period{{
+				Resolution: "PT15M",
+				Point: []Point{
+					{Position: 1, PriceAmount: 10},
+					{Position: 3, PriceAmount: 20},
+				},
+			}},
+		}},
+	}
+	doc.TimeSeries[0].Period[0].TimeInterval.Start = "2023-01-09T23:00Z"
+	doc.TimeSeries[0].Period[0].TimeInterval.End = "2023-01-10T00:00Z"
+
+	rates, err := doc.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	start := time.Date(2023, 1, 9, 23, 0, 0, 0, time.UTC)
+	assert.True(t, rates[0].End.Equal(start.Add(30*time.Minute)))
+	assert.True(t, rates[1].End.Equal(start.Add(time.Hour)))
+}
@@ -0,0 +1,48 @@
+package tariff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/tariff/entsoe"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntsoe(t *testing.T) {
+	b, err := os.ReadFile("entsoe/testdata/publication.xml")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "token", q.Get("securityToken"))
+		assert.Equal(t, entsoe.DocumentType, q.Get("documentType"))
+		assert.Equal(t, "10Y1001A1001A82H", q.Get("in_Domain"))
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	tf := &Entsoe{
+		log:     util.NewLogger("foo"),
+		uri:     srv.URL,
+		token:   "token",
+		domain:  "10Y1001A1001A82H",
+		charges: 0.2,
+		tax:     0.19,
+	}
+
+	tf.data, err = tf.query(request.NewHelper(tf.log), time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	rates, err := tf.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 24)
+
+	// (102.5 EUR/MWh + 0.2 EUR/kWh) * 1.19
+	assert.InDelta(t, 0.3599750, rates[0].Price, 1e-6)
+}
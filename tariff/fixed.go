@@ -1,30 +1,160 @@
 package tariff
 
 import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/tariff/fixed"
 	"github.com/evcc-io/evcc/util"
 )
 
 type Fixed struct {
-	Price float64
+	clock clock.Clock
+	price float64
+	zones fixed.Zones
 }
 
 var _ api.Tariff = (*Fixed)(nil)
 
+//go:generate go run ../cmd/tools/decorate.go -f decorateFixed -b *Fixed -r api.Tariff -t "api.TariffRates,Rates,func() (api.Rates, error)"
+
+// NewFixedFromConfig creates a fixed tariff from generic config. Price forecasts are only
+// provided if zones define varying prices.
+func NewFixedFromConfig(other map[string]interface{}) (api.Tariff, error) {
+	t, err := NewFixed(other)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates func() (api.Rates, error)
+	for _, z := range t.zones {
+		if z.Price != t.price {
+			rates = t.rates
+			break
+		}
+	}
+
+	return decorateFixed(t, rates), nil
+}
+
 func NewFixed(other map[string]interface{}) (*Fixed, error) {
-	cc := Fixed{}
+	var cc struct {
+		Price float64
+		Zones []struct {
+			Price               float64
+			Cheap               bool
+			Days, Hours, Months string
+		}
+	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
 		return nil, err
 	}
 
-	return &cc, nil
+	t := &Fixed{
+		clock: clock.New(),
+		price: cc.Price,
+	}
+
+	for i, z := range cc.Zones {
+		zone := fixed.Zone{
+			Price: z.Price,
+			Cheap: z.Cheap,
+		}
+
+		var err error
+		if z.Days != "" {
+			if zone.Days, err = fixed.ParseDays(z.Days); err != nil {
+				return nil, fmt.Errorf("zone %d: %w", i+1, err)
+			}
+		}
+
+		if z.Hours != "" {
+			if zone.Hours, err = fixed.ParseTimeRanges(z.Hours); err != nil {
+				return nil, fmt.Errorf("zone %d: %w", i+1, err)
+			}
+		}
+
+		if z.Months != "" {
+			if zone.Months, err = fixed.ParseMonths(z.Months); err != nil {
+				return nil, fmt.Errorf("zone %d: %w", i+1, err)
+			}
+		}
+
+		t.zones = append(t.zones, zone)
+	}
+
+	return t, nil
+}
+
+// priceAt returns the price and cheap status at the given time
+func (t *Fixed) priceAt(ts time.Time) (float64, bool) {
+	if z := t.zones.At(ts); z != nil {
+		return z.Price, z.Cheap
+	}
+
+	return t.price, false
 }
 
 func (t *Fixed) CurrentPrice() (float64, error) {
-	return t.Price, nil
+	price, _ := t.priceAt(t.clock.Now())
+	return price, nil
 }
 
 func (t *Fixed) IsCheap() (bool, error) {
-	return false, nil
+	_, cheap := t.priceAt(t.clock.Now())
+	return cheap, nil
+}
+
+// boundaries returns the sorted minutes of day at which zone prices may change
+func (t *Fixed) boundaries() []fixed.HourMin {
+	set := map[fixed.HourMin]bool{0: true}
+	for _, z := range t.zones {
+		for _, r := range z.Hours {
+			set[r.From%(24*60)] = true
+			set[r.To%(24*60)] = true
+		}
+	}
+
+	res := make([]fixed.HourMin, 0, len(set))
+	for m := range set {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+
+	return res
+}
+
+// rates returns the price forecast for today and tomorrow
+func (t *Fixed) rates() (api.Rates, error) {
+	y, m, d := t.clock.Now().Date()
+	end := time.Date(y, m, d+2, 0, 0, 0, 0, time.Local)
+
+	// rates between price boundaries, merging consecutive rates of identical price
+	var res api.Rates
+	for day := 0; day < 2; day++ {
+		for _, min := range t.boundaries() {
+			ts := time.Date(y, m, d+day, 0, int(min), 0, 0, time.Local)
+			price, _ := t.priceAt(ts)
+
+			if n := len(res); n > 0 {
+				res[n-1].End = ts
+				if res[n-1].Price == price {
+					continue
+				}
+			}
+
+			res = append(res, api.Rate{
+				Start: ts,
+				Price: price,
+			})
+		}
+	}
+
+	res[len(res)-1].End = end
+
+	return res, nil
 }
@@ -0,0 +1,54 @@
+package fixed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var days = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseDay parses an english weekday abbreviation or name
+func ParseDay(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		for i, d := range days {
+			if strings.HasPrefix(s, d) {
+				return time.Weekday(i), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("invalid day: %s", s)
+}
+
+// ParseDays parses a comma-separated list of weekdays or weekday ranges like Mon-Fri,Sun
+func ParseDays(s string) ([]time.Weekday, error) {
+	var res []time.Weekday
+
+	for _, seg := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(seg, "-")
+
+		start, err := ParseDay(from)
+		if err != nil {
+			return nil, err
+		}
+
+		end := start
+		if isRange {
+			if end, err = ParseDay(to); err != nil {
+				return nil, err
+			}
+		}
+
+		// ranges may wrap around the end of the week
+		for d := start; ; d = (d + 1) % 7 {
+			res = append(res, d)
+			if d == end {
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
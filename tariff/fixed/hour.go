@@ -0,0 +1,73 @@
+package fixed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HourMin is a time of day in minutes after midnight
+type HourMin int
+
+// ParseHourMin parses a time of day like 6, 06:30 or 24
+func ParseHourMin(s string) (HourMin, error) {
+	s = strings.TrimSpace(s)
+	h, m, hasMin := strings.Cut(s, ":")
+
+	hour, err := strconv.Atoi(h)
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("invalid hour: %s", s)
+	}
+
+	var min int
+	if hasMin {
+		if min, err = strconv.Atoi(m); err != nil || min < 0 || min > 59 || hour == 24 && min > 0 {
+			return 0, fmt.Errorf("invalid minute: %s", s)
+		}
+	}
+
+	return HourMin(hour*60 + min), nil
+}
+
+// TimeRange is a daily time range. Ranges ending before their start wrap around midnight.
+type TimeRange struct {
+	From, To HourMin
+}
+
+// Contains returns true if the time of day is inside the range
+func (r TimeRange) Contains(t time.Time) bool {
+	m := HourMin(t.Hour()*60 + t.Minute())
+
+	if r.From < r.To {
+		return r.From <= m && m < r.To
+	}
+
+	return m >= r.From || m < r.To
+}
+
+// ParseTimeRanges parses a comma-separated list of time ranges like 0-6,22-24
+func ParseTimeRanges(s string) ([]TimeRange, error) {
+	var res []TimeRange
+
+	for _, seg := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(seg, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range: %s", seg)
+		}
+
+		var r TimeRange
+		var err error
+
+		if r.From, err = ParseHourMin(from); err != nil {
+			return nil, err
+		}
+		if r.To, err = ParseHourMin(to); err != nil {
+			return nil, err
+		}
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,60 @@
+package fixed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var months = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// ParseMonth parses an english month abbreviation, name or number
+func ParseMonth(s string) (time.Month, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if i, err := strconv.Atoi(s); err == nil && i >= 1 && i <= 12 {
+		return time.Month(i), nil
+	}
+
+	if len(s) >= 3 {
+		for i, m := range months {
+			if strings.HasPrefix(s, m) {
+				return time.Month(i + 1), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("invalid month: %s", s)
+}
+
+// ParseMonths parses a comma-separated list of months or month ranges like Nov-Mar
+func ParseMonths(s string) ([]time.Month, error) {
+	var res []time.Month
+
+	for _, seg := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(seg, "-")
+
+		start, err := ParseMonth(from)
+		if err != nil {
+			return nil, err
+		}
+
+		end := start
+		if isRange {
+			if end, err = ParseMonth(to); err != nil {
+				return nil, err
+			}
+		}
+
+		// ranges may wrap around the end of the year
+		for m := start; ; m = m%12 + 1 {
+			res = append(res, m)
+			if m == end {
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package fixed
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Zone is a price zone valid on the given days, hours and months
+type Zone struct {
+	Price  float64
+	Cheap  bool
+	Days   []time.Weekday // empty: all days
+	Hours  []TimeRange    // empty: all day
+	Months []time.Month   // empty: all year
+}
+
+// Contains returns true if the zone applies at the given time
+func (z Zone) Contains(t time.Time) bool {
+	if len(z.Months) > 0 && !slices.Contains(z.Months, t.Month()) {
+		return false
+	}
+
+	if len(z.Days) > 0 && !slices.Contains(z.Days, t.Weekday()) {
+		return false
+	}
+
+	if len(z.Hours) == 0 {
+		return true
+	}
+
+	for _, r := range z.Hours {
+		if r.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Zones is a list of price zones
+type Zones []Zone
+
+// At returns the first zone applying at the given time or nil
+func (zz Zones) At(t time.Time) *Zone {
+	for i, z := range zz {
+		if z.Contains(t) {
+			return &zz[i]
+		}
+	}
+
+	return nil
+}
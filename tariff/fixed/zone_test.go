@@ -0,0 +1,84 @@
+package fixed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDays(t *testing.T) {
+	tc := []struct {
+		in  string
+		out []time.Weekday
+	}{
+		{"Mon", []time.Weekday{time.Monday}},
+		{"mon-fri", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"Sat,Sunday", []time.Weekday{time.Saturday, time.Sunday}},
+		{"Fri-Mon", []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+	}
+
+	for _, tc := range tc {
+		res, err := ParseDays(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.out, res, tc.in)
+	}
+
+	_, err := ParseDays("Mo-Fr")
+	assert.Error(t, err)
+}
+
+func TestParseMonths(t *testing.T) {
+	res, err := ParseMonths("Nov-Feb")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Month{time.November, time.December, time.January, time.February}, res)
+
+	res, err = ParseMonths("4,6-7")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Month{time.April, time.June, time.July}, res)
+
+	_, err = ParseMonths("13")
+	assert.Error(t, err)
+}
+
+func TestParseTimeRanges(t *testing.T) {
+	res, err := ParseTimeRanges("0-6:30,22-24")
+	require.NoError(t, err)
+	assert.Equal(t, []TimeRange{{0, 6*60 + 30}, {22 * 60, 24 * 60}}, res)
+
+	for _, s := range []string{"6", "25-6", "6:60-7", "24:30-1"} {
+		_, err := ParseTimeRanges(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestZones(t *testing.T) {
+	zones := Zones{
+		{Price: 0.1, Days: []time.Weekday{time.Saturday, time.Sunday}},
+		{Price: 0.2, Hours: []TimeRange{{22 * 60, 6 * 60}}, Cheap: true},
+		{Price: 0.4, Hours: []TimeRange{{17 * 60, 20 * 60}}, Months: []time.Month{time.November, time.December, time.January}},
+	}
+
+	tc := []struct {
+		ts    time.Time
+		price float64
+	}{
+		{time.Date(2022, 11, 5, 12, 0, 0, 0, time.Local), 0.1},  // Saturday
+		{time.Date(2022, 11, 7, 23, 0, 0, 0, time.Local), 0.2},  // Monday night
+		{time.Date(2022, 11, 8, 5, 59, 0, 0, time.Local), 0.2},  // Tuesday early morning
+		{time.Date(2022, 11, 8, 6, 0, 0, 0, time.Local), 0},     // Tuesday morning
+		{time.Date(2022, 11, 8, 18, 0, 0, 0, time.Local), 0.4},  // winter evening
+		{time.Date(2022, 7, 12, 18, 0, 0, 0, time.Local), 0},    // summer evening
+		{time.Date(2022, 7, 12, 22, 0, 0, 0, time.Local), 0.2},  // summer night
+		{time.Date(2022, 12, 31, 18, 0, 0, 0, time.Local), 0.1}, // winter weekend
+	}
+
+	for _, tc := range tc {
+		var price float64
+		if z := zones.At(tc.ts); z != nil {
+			price = z.Price
+		}
+		assert.Equal(t, tc.price, price, tc.ts)
+	}
+}
@@ -0,0 +1,35 @@
+package tariff
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateFixed(base *Fixed, tariffRates func() (api.Rates, error)) api.Tariff {
+	switch {
+	case tariffRates == nil:
+		return base
+
+	case tariffRates != nil:
+		return &struct {
+			*Fixed
+			api.TariffRates
+		}{
+			Fixed: base,
+			TariffRates: &decorateFixedTariffRatesImpl{
+				tariffRates: tariffRates,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateFixedTariffRatesImpl struct {
+	tariffRates func() (api.Rates, error)
+}
+
+func (impl *decorateFixedTariffRatesImpl) Rates() (api.Rates, error) {
+	return impl.tariffRates()
+}
@@ -0,0 +1,93 @@
+package tariff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixed(t *testing.T) {
+	tf, err := NewFixed(map[string]interface{}{
+		"price": 0.3,
+	})
+	require.NoError(t, err)
+
+	price, err := tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, price)
+
+	cheap, err := tf.IsCheap()
+	require.NoError(t, err)
+	assert.False(t, cheap)
+
+	// flat price provides no forecast
+	tariff, err := NewFixedFromConfig(map[string]interface{}{
+		"price": 0.3,
+		"zones": []map[string]interface{}{
+			{"hours": "22-6", "price": 0.3},
+		},
+	})
+	require.NoError(t, err)
+
+	_, ok := tariff.(api.TariffRates)
+	assert.False(t, ok)
+}
+
+func TestFixedZones(t *testing.T) {
+	tf, err := NewFixed(map[string]interface{}{
+		"price": 0.3,
+		"zones": []map[string]interface{}{
+			{"days": "Mon-Fri", "hours": "22-6", "price": 0.2, "cheap": true},
+		},
+	})
+	require.NoError(t, err)
+
+	clck := clock.NewMock()
+	tf.clock = clck
+
+	// Monday midnight
+	clck.Set(time.Date(2022, 11, 7, 0, 0, 0, 0, time.Local))
+
+	price, err := tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, price)
+
+	cheap, err := tf.IsCheap()
+	require.NoError(t, err)
+	assert.True(t, cheap)
+
+	rates, err := tf.rates()
+	require.NoError(t, err)
+
+	day := time.Date(2022, 11, 7, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, api.Rates{
+		{Start: day, End: day.Add(6 * time.Hour), Price: 0.2},
+		{Start: day.Add(6 * time.Hour), End: day.Add(22 * time.Hour), Price: 0.3},
+		{Start: day.Add(22 * time.Hour), End: day.Add(30 * time.Hour), Price: 0.2},
+		{Start: day.Add(30 * time.Hour), End: day.Add(46 * time.Hour), Price: 0.3},
+		{Start: day.Add(46 * time.Hour), End: day.Add(48 * time.Hour), Price: 0.2},
+	}, rates)
+
+	// varying prices provide forecast
+	tariff, err := NewFixedFromConfig(map[string]interface{}{
+		"price": 0.3,
+		"zones": []map[string]interface{}{
+			{"hours": "22-6", "price": 0.2},
+		},
+	})
+	require.NoError(t, err)
+
+	_, ok := tariff.(api.TariffRates)
+	assert.True(t, ok)
+
+	_, err = NewFixed(map[string]interface{}{
+		"zones": []map[string]interface{}{
+			{"days": "Mon-Funday", "price": 0.2},
+		},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package tariff
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider/pipeline"
+	"github.com/evcc-io/evcc/tariff/solar"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// NewSolarFromConfig creates new pv forecast source from config
+func NewSolarFromConfig(typ string, other map[string]interface{}) (api.SolarForecaster, error) {
+	switch strings.ToLower(typ) {
+	case "http":
+		return NewSolarHTTPFromConfig(other)
+	case "clearsky":
+		return NewClearSkyFromConfig(other)
+	default:
+		return nil, errors.New("unknown solar forecast: " + typ)
+	}
+}
+
+// SolarForecasts combines multiple forecasts, e.g. for differently oriented pv arrays
+type SolarForecasts []api.SolarForecaster
+
+var _ api.SolarForecaster = (SolarForecasts)(nil)
+
+// SolarForecast implements the api.SolarForecaster interface
+func (f SolarForecasts) SolarForecast() (api.Forecast, error) {
+	res := make([]api.Forecast, 0, len(f))
+	for _, s := range f {
+		forecast, err := s.SolarForecast()
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, forecast)
+	}
+
+	return solar.Merge(res...), nil
+}
+
+// SolarHTTP is a pv forecast fetched from a json web service
+type SolarHTTP struct {
+	mux      sync.Mutex
+	log      *util.Logger
+	uri      string
+	headers  map[string]string
+	pipeline *pipeline.Pipeline
+	interval time.Duration
+	data     api.Forecast
+}
+
+var _ api.SolarForecaster = (*SolarHTTP)(nil)
+
+// NewSolarHTTPFromConfig creates a json web service pv forecast from generic config
+func NewSolarHTTPFromConfig(other map[string]interface{}) (*SolarHTTP, error) {
+	cc := struct {
+		URI               string
+		Headers           map[string]string
+		pipeline.Settings `mapstructure:",squash"`
+		Interval          time.Duration
+	}{
+		Interval: time.Hour,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	pipe, err := pipeline.New(cc.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &SolarHTTP{
+		log:      util.NewLogger("solar"),
+		uri:      cc.URI,
+		headers:  cc.Headers,
+		pipeline: pipe,
+		interval: cc.Interval,
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+// query fetches and decodes the forecast
+func (t *SolarHTTP) query(client *request.Helper) (api.Forecast, error) {
+	req, err := request.New(http.MethodGet, t.uri, nil, t.headers)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := client.DoBody(req)
+	if err == nil {
+		b, err = t.pipeline.Process(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return solar.Parse(b)
+}
+
+func (t *SolarHTTP) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(t.interval).C {
+		res, err := t.query(client)
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = res
+		t.mux.Unlock()
+	}
+}
+
+// SolarForecast implements the api.SolarForecaster interface
+func (t *SolarHTTP) SolarForecast() (api.Forecast, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.data == nil {
+		return nil, api.ErrNotAvailable
+	}
+
+	return append(api.Forecast(nil), t.data...), nil
+}
+
+// ClearSky is a locally computed clear-sky pv forecast
+type ClearSky struct {
+	clock clock.Clock
+	plane solar.Plane
+}
+
+var _ api.SolarForecaster = (*ClearSky)(nil)
+
+// NewClearSkyFromConfig creates a clear-sky pv forecast from generic config
+func NewClearSkyFromConfig(other map[string]interface{}) (*ClearSky, error) {
+	cc := struct {
+		Latitude, Longitude float64
+		Azimuth, Tilt       float64
+		KWp                 float64
+		Losses              float64
+	}{
+		Losses: 0.14,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.KWp <= 0 {
+		return nil, errors.New("missing kwp")
+	}
+
+	if cc.Latitude == 0 && cc.Longitude == 0 {
+		return nil, errors.New("missing latitude/longitude")
+	}
+
+	t := &ClearSky{
+		clock: clock.New(),
+		plane: solar.Plane{
+			Latitude:  cc.Latitude,
+			Longitude: cc.Longitude,
+			Azimuth:   cc.Azimuth,
+			Tilt:      cc.Tilt,
+			KWp:       cc.KWp,
+			Losses:    cc.Losses,
+		},
+	}
+
+	return t, nil
+}
+
+// SolarForecast implements the api.SolarForecaster interface
+func (t *ClearSky) SolarForecast() (api.Forecast, error) {
+	y, m, d := t.clock.Now().Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	// today and tomorrow
+	return t.plane.Forecast(start, start.AddDate(0, 0, 2), 15*time.Minute), nil
+}
@@ -0,0 +1,100 @@
+package solar
+
+import (
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+const (
+	solarConstant = 1353 // W/m²
+	albedo        = 0.2  // ground reflectance
+)
+
+// Plane is a pv array described by location, orientation and peak power
+type Plane struct {
+	Latitude, Longitude float64 // degrees
+	Azimuth             float64 // degrees, 0 south, -90 east, 90 west
+	Tilt                float64 // degrees, 0 horizontal
+	KWp                 float64 // peak power
+	Losses              float64 // system losses, 0..1
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func deg(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// Position returns the sun's elevation and azimuth (0 south, negative east) in degrees
+// using the NOAA general solar position approximation
+func Position(ts time.Time, lat, lon float64) (elevation, azimuth float64) {
+	ts = ts.UTC()
+
+	hour := float64(ts.Hour()) + float64(ts.Minute())/60 + float64(ts.Second())/3600
+	gamma := 2 * math.Pi / 365 * (float64(ts.YearDay()-1) + (hour-12)/24)
+
+	// equation of time in minutes and declination in radians
+	eqtime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	// true solar time and hour angle
+	tst := hour*60 + eqtime + 4*lon
+	ha := rad(tst/4 - 180)
+
+	phi := rad(lat)
+	cosZenith := math.Sin(phi)*math.Sin(decl) + math.Cos(phi)*math.Cos(decl)*math.Cos(ha)
+	zenith := math.Acos(math.Max(-1, math.Min(1, cosZenith)))
+
+	azimuth = deg(math.Atan2(math.Sin(ha), math.Cos(ha)*math.Sin(phi)-math.Tan(decl)*math.Cos(phi)))
+
+	return 90 - deg(zenith), azimuth
+}
+
+// Irradiance returns the clear-sky plane-of-array irradiance in W/m²
+func (p Plane) Irradiance(ts time.Time) float64 {
+	elevation, azimuth := Position(ts, p.Latitude, p.Longitude)
+	if elevation <= 0 {
+		return 0
+	}
+
+	zenith := 90 - elevation
+	cosZenith := math.Cos(rad(zenith))
+
+	// Kasten-Young air mass and Meinel direct normal irradiance
+	am := 1 / (cosZenith + 0.50572*math.Pow(96.07995-zenith, -1.6364))
+	dni := solarConstant * math.Pow(0.7, math.Pow(am, 0.678))
+	dhi := 0.1 * dni
+	ghi := dni*cosZenith + dhi
+
+	// angle of incidence on the tilted plane
+	tilt := rad(p.Tilt)
+	cosAOI := cosZenith*math.Cos(tilt) + math.Sin(rad(zenith))*math.Sin(tilt)*math.Cos(rad(azimuth-p.Azimuth))
+
+	return dni*math.Max(0, cosAOI) + dhi*(1+math.Cos(tilt))/2 + ghi*albedo*(1-math.Cos(tilt))/2
+}
+
+// Power returns the expected clear-sky power in W
+func (p Plane) Power(ts time.Time) float64 {
+	// peak power is rated at 1000 W/m²
+	return p.KWp * p.Irradiance(ts) * (1 - p.Losses)
+}
+
+// Forecast returns the clear-sky forecast between from and to using the given resolution
+func (p Plane) Forecast(from, to time.Time, resolution time.Duration) api.Forecast {
+	var res api.Forecast
+	for ts := from; ts.Before(to); ts = ts.Add(resolution) {
+		res = append(res, api.ForecastSlot{
+			Start: ts,
+			End:   ts.Add(resolution),
+			Power: p.Power(ts.Add(resolution / 2)),
+		})
+	}
+	return res
+}
@@ -0,0 +1,150 @@
+package solar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+var timeFormats = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04"}
+
+// parseTime parses RFC3339 or local date/time formats
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeFormats {
+		if ts, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", s)
+}
+
+// Parse decodes a json forecast. Supported formats are
+//   - a list of start/end/power slots where end defaults to the next slot's start
+//   - a timestamp/power map like forecast.solar's watts result
+func Parse(b []byte) (api.Forecast, error) {
+	var slots []slot
+	if err := json.Unmarshal(b, &slots); err == nil {
+		return parseSlots(slots)
+	}
+
+	var values map[string]float64
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, errors.New("invalid forecast: expected list of slots or timestamp map")
+	}
+
+	return parseValues(values)
+}
+
+// slot is a json forecast slot with optional end
+type slot struct {
+	Start, End string
+	Power      float64
+}
+
+func parseSlots(slots []slot) (api.Forecast, error) {
+	res := make(api.Forecast, 0, len(slots))
+
+	for _, s := range slots {
+		start, err := parseTime(s.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		var end time.Time
+		if s.End != "" {
+			if end, err = parseTime(s.End); err != nil {
+				return nil, err
+			}
+		}
+
+		res = append(res, api.ForecastSlot{Start: start, End: end, Power: s.Power})
+	}
+
+	res.Sort()
+
+	// missing ends are taken from the next slot or the previous slot's duration
+	for i := range res {
+		if !res[i].End.IsZero() {
+			continue
+		}
+
+		switch {
+		case i+1 < len(res):
+			res[i].End = res[i+1].Start
+		case i > 0:
+			res[i].End = res[i].Start.Add(res[i-1].End.Sub(res[i-1].Start))
+		default:
+			res[i].End = res[i].Start.Add(time.Hour)
+		}
+	}
+
+	return res, nil
+}
+
+// parseValues converts instantaneous power values into slots using the average of adjacent values
+func parseValues(values map[string]float64) (api.Forecast, error) {
+	type point struct {
+		ts    time.Time
+		power float64
+	}
+
+	points := make([]point, 0, len(values))
+	for k, v := range values {
+		ts, err := parseTime(k)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, point{ts, v})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].ts.Before(points[j].ts)
+	})
+
+	var res api.Forecast
+	for i := 1; i < len(points); i++ {
+		res = append(res, api.ForecastSlot{
+			Start: points[i-1].ts,
+			End:   points[i].ts,
+			Power: (points[i-1].power + points[i].power) / 2,
+		})
+	}
+
+	return res, nil
+}
+
+// Merge adds up multiple forecasts, e.g. for differently oriented pv arrays
+func Merge(forecasts ...api.Forecast) api.Forecast {
+	var bounds []time.Time
+	for _, f := range forecasts {
+		for _, s := range f {
+			bounds = append(bounds, s.Start, s.End)
+		}
+	}
+
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].Before(bounds[j])
+	})
+
+	var res api.Forecast
+	for i := 1; i < len(bounds); i++ {
+		start, end := bounds[i-1], bounds[i]
+		if !end.After(start) {
+			continue
+		}
+
+		var power float64
+		for _, f := range forecasts {
+			power += f.Energy(start, end) / end.Sub(start).Hours()
+		}
+
+		res = append(res, api.ForecastSlot{Start: start, End: end, Power: power})
+	}
+
+	return res
+}
@@ -0,0 +1,89 @@
+package solar
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition(t *testing.T) {
+	// Berlin at solar noon on summer solstice
+	elevation, azimuth := Position(time.Date(2023, 6, 21, 11, 8, 0, 0, time.UTC), 52.52, 13.4)
+	assert.InDelta(t, 60.9, elevation, 0.5)
+	assert.InDelta(t, 0, azimuth, 2)
+
+	// sun rises in the east
+	elevation, azimuth = Position(time.Date(2023, 6, 21, 4, 0, 0, 0, time.UTC), 52.52, 13.4)
+	assert.Greater(t, elevation, 0.0)
+	assert.Less(t, azimuth, -90.0)
+
+	// night
+	elevation, _ = Position(time.Date(2023, 6, 21, 23, 0, 0, 0, time.UTC), 52.52, 13.4)
+	assert.Less(t, elevation, 0.0)
+}
+
+func TestClearSky(t *testing.T) {
+	p := Plane{Latitude: 52.52, Longitude: 13.4, Tilt: 30, KWp: 10, Losses: 0.14}
+
+	noon := time.Date(2023, 6, 21, 11, 0, 0, 0, time.UTC)
+	assert.Zero(t, p.Power(noon.Add(12*time.Hour)))
+	assert.Greater(t, p.Power(noon), p.Power(noon.AddDate(0, 6, 0)))
+	assert.Less(t, p.Power(noon), 10e3)
+
+	// east-facing plane produces more in the morning
+	east := p
+	east.Azimuth = -90
+	morning := noon.Add(-4 * time.Hour)
+	assert.Greater(t, east.Power(morning), p.Power(morning))
+
+	// plausible clear-sky daily yield in summer
+	day := time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC)
+	f := p.Forecast(day, day.AddDate(0, 0, 1), 15*time.Minute)
+	assert.Len(t, f, 96)
+	assert.InDelta(t, 70e3, f.Energy(day, day.AddDate(0, 0, 1)), 15e3)
+}
+
+func TestParseSlots(t *testing.T) {
+	b, err := os.ReadFile("testdata/slots.json")
+	require.NoError(t, err)
+
+	f, err := Parse(b)
+	require.NoError(t, err)
+	require.Len(t, f, 4)
+
+	// missing ends
+	assert.Equal(t, f[3].Start, f[2].End)
+	assert.Equal(t, time.Hour, f[3].End.Sub(f[3].Start))
+	assert.Equal(t, 9500.0, f.Energy(f[0].Start, f[3].End))
+}
+
+func TestParseValues(t *testing.T) {
+	f, err := Parse([]byte(`{"2023-06-21 06:00:00": 0, "2023-06-21 07:00:00": 1000, "2023-06-21 08:00:00": 2000}`))
+	require.NoError(t, err)
+	require.Len(t, f, 2)
+
+	assert.Equal(t, 500.0, f[0].Power)
+	assert.Equal(t, 1500.0, f[1].Power)
+
+	_, err = Parse([]byte(`"foo"`))
+	assert.Error(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	ts := time.Date(2023, 6, 21, 6, 0, 0, 0, time.UTC)
+
+	a := api.Forecast{{Start: ts, End: ts.Add(time.Hour), Power: 1000}}
+	b := api.Forecast{
+		{Start: ts, End: ts.Add(30 * time.Minute), Power: 200},
+		{Start: ts.Add(30 * time.Minute), End: ts.Add(time.Hour), Power: 400},
+	}
+
+	assert.Equal(t, api.Forecast{
+		{Start: ts, End: ts.Add(30 * time.Minute), Power: 1200},
+		{Start: ts.Add(30 * time.Minute), End: ts.Add(time.Hour), Power: 1400},
+	}, Merge(a, b))
+}
@@ -0,0 +1,75 @@
+package tariff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider/pipeline"
+	"github.com/evcc-io/evcc/tariff/solar"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolarHTTP(t *testing.T) {
+	b, err := os.ReadFile("solar/testdata/forecastsolar.json")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	pipe, err := pipeline.New(pipeline.Settings{Jq: ".result.watts | tojson"})
+	require.NoError(t, err)
+
+	tf := &SolarHTTP{
+		log:      util.NewLogger("foo"),
+		uri:      srv.URL,
+		headers:  map[string]string{"X-Api-Key": "secret"},
+		pipeline: pipe,
+	}
+
+	_, err = tf.SolarForecast()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+
+	tf.data, err = tf.query(request.NewHelper(tf.log))
+	require.NoError(t, err)
+
+	f, err := tf.SolarForecast()
+	require.NoError(t, err)
+	require.Len(t, f, 4)
+
+	// trapezoidal energy 200 + 1000 + 2400 + 4000 Wh
+	assert.Equal(t, 7600.0, f.Energy(f[0].Start, f[3].End))
+}
+
+func TestClearSkyForecast(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2023, 6, 21, 12, 0, 0, 0, time.Local))
+
+	tf := &ClearSky{
+		clock: clck,
+		plane: solar.Plane{Latitude: 52.52, Longitude: 13.4, Tilt: 30, KWp: 10},
+	}
+
+	f, err := tf.SolarForecast()
+	require.NoError(t, err)
+	require.Len(t, f, 2*96)
+
+	// today and tomorrow are almost identical
+	today := f.Energy(f[0].Start, f[96].Start)
+	assert.InDelta(t, today, f.Energy(f[96].Start, f[191].End), today*0.01)
+
+	// combined arrays add up
+	combined, err := SolarForecasts{tf, tf}.SolarForecast()
+	require.NoError(t, err)
+	assert.InDelta(t, 2*today, combined.Energy(f[0].Start, f[96].Start), 1e-6)
+}
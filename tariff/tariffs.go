@@ -9,14 +9,18 @@ type Tariffs struct {
 	Currency currency.Unit
 	Grid     api.Tariff
 	FeedIn   api.Tariff
+	CO2      api.CO2Intensity
+	Solar    api.SolarForecaster
 }
 
 var _ api.Tariff = (*Fixed)(nil)
 
-func NewTariffs(currency currency.Unit, grid api.Tariff, feedin api.Tariff) *Tariffs {
+func NewTariffs(currency currency.Unit, grid api.Tariff, feedin api.Tariff, co2 api.CO2Intensity, solar api.SolarForecaster) *Tariffs {
 	t := Tariffs{}
 	t.Currency = currency
 	t.Grid = grid
 	t.FeedIn = feedin
+	t.CO2 = co2
+	t.Solar = solar
 	return &t
 }
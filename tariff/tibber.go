@@ -25,7 +25,10 @@ type Tibber struct {
 	data   []tibber.PriceInfo
 }
 
-var _ api.Tariff = (*Tibber)(nil)
+var (
+	_ api.Tariff      = (*Tibber)(nil)
+	_ api.TariffRates = (*Tibber)(nil)
+)
 
 func NewTibber(other map[string]interface{}) (*Tibber, error) {
 	t := &Tibber{
@@ -93,23 +96,37 @@ func (t *Tibber) Run() {
 		}
 
 		t.mux.Lock()
-		t.data = res.Viewer.Home.CurrentSubscription.PriceInfo.Today
+		pi := res.Viewer.Home.CurrentSubscription.PriceInfo
+		t.data = append(pi.Today, pi.Tomorrow...)
 		t.mux.Unlock()
 	}
 }
 
 func (t *Tibber) CurrentPrice() (float64, error) {
+	rates, _ := t.Rates()
+
+	if rate, err := rates.Current(time.Now()); err == nil {
+		return rate.Price, nil
+	}
+
+	return 0, errors.New("unable to find current tibber price")
+}
+
+// Rates implements the api.TariffRates interface
+func (t *Tibber) Rates() (api.Rates, error) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
-	for i := len(t.data) - 1; i >= 0; i-- {
-		pi := t.data[i]
-
-		if pi.StartsAt.Before(time.Now()) {
-			return pi.Total, nil
-		}
+	res := make(api.Rates, 0, len(t.data))
+	for _, r := range t.data {
+		res = append(res, api.Rate{
+			Start: r.StartsAt,
+			End:   r.StartsAt.Add(time.Hour), // tibber prices are hourly
+			Price: r.Total,
+		})
 	}
-	return 0, errors.New("unable to find current tibber price")
+
+	return res, nil
 }
 
 func (t *Tibber) IsCheap() (bool, error) {
@@ -0,0 +1,70 @@
+package vehicle
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulator"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Simulator is a virtual vehicle connected to the simulated site's chargers by id
+type Simulator struct {
+	*embed
+	site    *simulator.Site
+	vehicle *simulator.Vehicle
+}
+
+func init() {
+	registry.Add("simulator", NewSimulatorFromConfig)
+}
+
+// NewSimulatorFromConfig creates a simulated vehicle from generic config
+func NewSimulatorFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed      `mapstructure:",squash"`
+		ID         string
+		SoC        float64
+		MaxCurrent float64
+	}{
+		embed: embed{
+			Capacity_: 50,
+		},
+		SoC: 20,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	site := simulator.Instance()
+	vehicle, err := site.AddVehicle(cc.ID, simulator.VehicleConfig{
+		Capacity:   cc.Capacity_,
+		Phases:     cc.Phases_,
+		MaxCurrent: cc.MaxCurrent,
+		SoC:        cc.SoC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// chargers identify the vehicle by id
+	if len(cc.Identifiers_) == 0 {
+		cc.Identifiers_ = []string{cc.ID}
+	}
+
+	v := &Simulator{
+		embed:   &cc.embed,
+		site:    site,
+		vehicle: vehicle,
+	}
+
+	return v, nil
+}
+
+// SoC implements the api.Vehicle interface
+func (v *Simulator) SoC() (float64, error) {
+	if err := v.site.Update(); err != nil {
+		return 0, err
+	}
+
+	return v.vehicle.SoC(), nil
+}